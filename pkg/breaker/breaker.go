@@ -0,0 +1,114 @@
+// Package breaker wraps calls to a dependency in a circuit breaker: once enough calls fail it opens and
+// calls fail fast with ErrOpen instead of waiting on the dependency, after OpenMs it lets HalfOpenProbes
+// calls through and closes again once they succeed
+package breaker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"go.uber.org/zap"
+)
+
+// ErrOpen is returned without calling the dependency while the breaker is open, or half-open with every
+// probe already taken
+var ErrOpen = errors.New("circuit_open")
+
+// States as returned by State
+const (
+	StateClosed   = "closed"
+	StateHalfOpen = "half-open"
+	StateOpen     = "open"
+)
+
+// Breaker is safe for concurrent use. A nil *Breaker, from a disabled config, calls straight through
+type Breaker struct {
+	name        string
+	cb          *gobreaker.CircuitBreaker
+	callTimeout time.Duration
+	isFailure   func(error) bool
+	metrics     *Metrics
+}
+
+// Options are what the caller decides rather than the config
+type Options struct {
+	// IsFailure reports whether err counts against the dependency, eg a cache miss doesn't.
+	// Default: any error but the caller's context being cancelled
+	IsFailure func(err error) bool
+	Metrics   *Metrics // nil records nothing
+	Log       *zap.Logger
+}
+
+// New returns nil when cfg is nil or disabled
+func New(name string, cfg *Config, opts Options) *Breaker {
+	if cfg == nil || !cfg.IsEnabled {
+		return nil
+	}
+	log := opts.Log
+	if log == nil {
+		log = zap.NewNop()
+	}
+	isFailure := opts.IsFailure
+	if isFailure == nil {
+		isFailure = func(err error) bool { return !errors.Is(err, context.Canceled) }
+	}
+
+	minCalls, ratio, consecutive := cfg.minCalls(), cfg.failureRatio(), cfg.consecutiveFailures()
+	b := &Breaker{name: name, callTimeout: cfg.callTimeout(), isFailure: isFailure}
+	b.cb = gobreaker.NewCircuitBreaker(gobreaker.Settings{
+		Name:        name,
+		MaxRequests: cfg.halfOpenProbes(),
+		Interval:    cfg.interval(),
+		Timeout:     cfg.openTimeout(),
+		ReadyToTrip: func(c gobreaker.Counts) bool {
+			if c.ConsecutiveFailures >= consecutive {
+				return true
+			}
+			return c.Requests >= minCalls && float64(c.TotalFailures)/float64(c.Requests) >= ratio
+		},
+		OnStateChange: func(_ string, from, to gobreaker.State) {
+			opts.Metrics.transition(name, to)
+			if to == gobreaker.StateOpen {
+				log.Warn("circuit breaker opened", zap.String("breaker", name), zap.String("from", from.String()))
+				return
+			}
+			log.Info("circuit breaker state changed", zap.String("breaker", name),
+				zap.String("from", from.String()), zap.String("to", to.String()))
+		},
+		IsSuccessful: func(err error) bool { return err == nil || !isFailure(err) },
+	})
+	opts.Metrics.init(name)
+	b.metrics = opts.Metrics
+	return b
+}
+
+// Do calls fn through the breaker, bounded by the configured call timeout. It returns ErrOpen without
+// calling fn when the breaker rejects the call
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if b == nil {
+		return fn(ctx)
+	}
+	_, err := b.cb.Execute(func() (any, error) {
+		if b.callTimeout <= 0 {
+			return nil, fn(ctx)
+		}
+		cctx, cancel := context.WithTimeout(ctx, b.callTimeout)
+		defer cancel()
+		return nil, fn(cctx)
+	})
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		b.metrics.rejected(b.name)
+		return ErrOpen
+	}
+	return err
+}
+
+// State is closed, half-open or open, closed for a nil Breaker
+func (b *Breaker) State() string {
+	if b == nil {
+		return StateClosed
+	}
+	return b.cb.State().String()
+}
@@ -0,0 +1,129 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errDown = errors.New("down")
+
+func fail(context.Context) error { return errDown }
+
+func ok(context.Context) error { return nil }
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	b := New("redis", &Config{IsEnabled: true, ConsecutiveFailures: 3, OpenMs: 20}, Options{})
+
+	for range 3 {
+		if err := b.Do(context.Background(), fail); !errors.Is(err, errDown) {
+			t.Fatalf("closed breaker should return the call's error, got %v", err)
+		}
+	}
+	if got := b.State(); got != "open" {
+		t.Fatalf("state %s, want open", got)
+	}
+
+	called := false
+	err := b.Do(context.Background(), func(context.Context) error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrOpen) || called {
+		t.Fatalf("open breaker should fail fast with ErrOpen, got %v (called %v)", err, called)
+	}
+
+	// after OpenMs a probe goes through and closes it
+	time.Sleep(30 * time.Millisecond)
+	if err = b.Do(context.Background(), ok); err != nil {
+		t.Fatalf("half-open probe: %v", err)
+	}
+	if got := b.State(); got != "closed" {
+		t.Fatalf("state %s after a successful probe, want closed", got)
+	}
+}
+
+func TestBreaker_FailedProbeReopens(t *testing.T) {
+	b := New("kafka", &Config{IsEnabled: true, ConsecutiveFailures: 1, OpenMs: 10}, Options{})
+	_ = b.Do(context.Background(), fail)
+
+	time.Sleep(20 * time.Millisecond)
+	if got := b.State(); got != "half-open" {
+		t.Fatalf("state %s, want half-open", got)
+	}
+	_ = b.Do(context.Background(), fail)
+	if got := b.State(); got != "open" {
+		t.Fatalf("state %s after a failed probe, want open", got)
+	}
+}
+
+func TestBreaker_FailureRatio(t *testing.T) {
+	b := New("redis", &Config{IsEnabled: true, ConsecutiveFailures: 100, MinCalls: 4, FailureRatio: 0.5}, Options{})
+
+	for _, fn := range []func(context.Context) error{ok, fail, ok} {
+		_ = b.Do(context.Background(), fn)
+	}
+	if got := b.State(); got != "closed" {
+		t.Fatalf("state %s before MinCalls, want closed", got)
+	}
+	_ = b.Do(context.Background(), fail)
+	if got := b.State(); got != "open" {
+		t.Fatalf("state %s at 2/4 failures, want open", got)
+	}
+}
+
+func TestBreaker_IsFailure(t *testing.T) {
+	errMiss := errors.New("miss")
+	b := New("redis", &Config{IsEnabled: true, ConsecutiveFailures: 1}, Options{
+		IsFailure: func(err error) bool { return !errors.Is(err, errMiss) },
+	})
+
+	if err := b.Do(context.Background(), func(context.Context) error { return errMiss }); !errors.Is(err, errMiss) {
+		t.Fatalf("got %v, want the miss returned", err)
+	}
+	if got := b.State(); got != "closed" {
+		t.Fatalf("a miss must not count as a failure, state %s", got)
+	}
+}
+
+func TestBreaker_CallTimeout(t *testing.T) {
+	b := New("redis", &Config{IsEnabled: true, ConsecutiveFailures: 1, CallTimeoutMs: 10}, Options{})
+
+	err := b.Do(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want the call timeout", err)
+	}
+	if got := b.State(); got != "open" {
+		t.Fatalf("a timed out call must count as a failure, state %s", got)
+	}
+}
+
+func TestBreaker_Disabled(t *testing.T) {
+	for _, cfg := range []*Config{nil, {IsEnabled: false}} {
+		b := New("redis", cfg, Options{})
+		if b != nil {
+			t.Fatal("expected a nil breaker")
+		}
+		for range 10 {
+			if err := b.Do(context.Background(), fail); !errors.Is(err, errDown) {
+				t.Fatalf("got %v, want calls straight through", err)
+			}
+		}
+		if got := b.State(); got != "closed" {
+			t.Fatalf("state %s, want closed", got)
+		}
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	if err := (&Config{IsEnabled: true, FailureRatio: 1.5, OpenMs: -1}).Validate(); err == nil {
+		t.Fatal("expected problems")
+	}
+	if err := (&Config{IsEnabled: true}).Validate(); err != nil {
+		t.Fatalf("defaults should be valid: %v", err)
+	}
+}
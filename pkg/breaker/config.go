@@ -0,0 +1,77 @@
+package breaker
+
+import (
+	"errors"
+	"kei-services/pkg/config"
+	"time"
+)
+
+// Config sets when a breaker opens and how it recovers, zero values take the defaults
+type Config struct {
+	IsEnabled           bool
+	CallTimeoutMs       int     // per call, below the client's own timeout so slow calls count as failures, default: none
+	ConsecutiveFailures int     // opens after this many failures in a row, default: 5
+	FailureRatio        float64 // or once this share of the calls in IntervalMs failed, default: 0.5
+	MinCalls            int     // calls in IntervalMs before FailureRatio applies, default: 20
+	IntervalMs          int     // closed-state counts reset this often, default: 10000
+	OpenMs              int     // open this long before probing, default: 5000
+	HalfOpenProbes      int     // calls let through while half-open, all must succeed to close, default: 1
+}
+
+func (c *Config) Validate() error {
+	if !c.IsEnabled {
+		return nil
+	}
+	var errs []error
+	for _, f := range []struct {
+		name  string
+		value int
+	}{
+		{"CallTimeoutMs", c.CallTimeoutMs},
+		{"ConsecutiveFailures", c.ConsecutiveFailures},
+		{"MinCalls", c.MinCalls},
+		{"IntervalMs", c.IntervalMs},
+		{"OpenMs", c.OpenMs},
+		{"HalfOpenProbes", c.HalfOpenProbes},
+	} {
+		if f.value < 0 {
+			errs = append(errs, config.Problem(f.name, "must not be negative"))
+		}
+	}
+	if c.FailureRatio < 0 || c.FailureRatio > 1 {
+		errs = append(errs, config.Problem("FailureRatio", "%v, expected 0-1", c.FailureRatio))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Config) callTimeout() time.Duration {
+	return time.Duration(c.CallTimeoutMs) * time.Millisecond
+}
+
+func (c *Config) consecutiveFailures() uint32 { return uint32(orDefault(c.ConsecutiveFailures, 5)) }
+
+func (c *Config) failureRatio() float64 {
+	if c.FailureRatio <= 0 {
+		return 0.5
+	}
+	return c.FailureRatio
+}
+
+func (c *Config) minCalls() uint32 { return uint32(orDefault(c.MinCalls, 20)) }
+
+func (c *Config) interval() time.Duration {
+	return time.Duration(orDefault(c.IntervalMs, 10000)) * time.Millisecond
+}
+
+func (c *Config) openTimeout() time.Duration {
+	return time.Duration(orDefault(c.OpenMs, 5000)) * time.Millisecond
+}
+
+func (c *Config) halfOpenProbes() uint32 { return uint32(orDefault(c.HalfOpenProbes, 1)) }
+
+func orDefault(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,53 @@
+package breaker
+
+import (
+	"kei-services/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sony/gobreaker"
+)
+
+// Metrics are the breakers' Prometheus collectors, shared by every breaker of a service and labelled by
+// breaker name. A nil *Metrics records nothing
+type Metrics struct {
+	state       *prometheus.GaugeVec
+	transitions *prometheus.CounterVec
+	rejections  *prometheus.CounterVec
+}
+
+func NewMetrics(reg *metrics.Registry) *Metrics {
+	return &Metrics{
+		state: metrics.CGauge(reg.Reg, reg.DefaultNS, "circuit_breaker_state",
+			"Circuit breaker state: 0 closed, 1 half-open, 2 open.", nil,
+			[]string{"breaker"}),
+		transitions: metrics.CCounter(reg.Reg, reg.DefaultNS, "circuit_breaker_transitions_total",
+			"Circuit breaker state changes by breaker and the state entered.", nil,
+			[]string{"breaker", "to"}),
+		rejections: metrics.CCounter(reg.Reg, reg.DefaultNS, "circuit_breaker_rejected_total",
+			"Calls failed fast without reaching the dependency because the breaker was open.", nil,
+			[]string{"breaker"}),
+	}
+}
+
+func (m *Metrics) init(name string) {
+	if m == nil {
+		return
+	}
+	m.state.WithLabelValues(name).Set(float64(gobreaker.StateClosed))
+	m.rejections.WithLabelValues(name)
+}
+
+func (m *Metrics) transition(name string, to gobreaker.State) {
+	if m == nil {
+		return
+	}
+	m.state.WithLabelValues(name).Set(float64(to))
+	m.transitions.WithLabelValues(name, to.String()).Inc()
+}
+
+func (m *Metrics) rejected(name string) {
+	if m == nil {
+		return
+	}
+	m.rejections.WithLabelValues(name).Inc()
+}
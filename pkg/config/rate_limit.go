@@ -0,0 +1,8 @@
+package config
+
+// RateLimit is a token bucket per client IP
+type RateLimit struct {
+	IsEnabled      bool
+	RequestsPerSec float64
+	Burst          int
+}
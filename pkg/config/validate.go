@@ -0,0 +1,159 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Validator is a config section checking its own values, reporting every problem at once
+type Validator interface {
+	Validate() error
+}
+
+// Required validates a section that must be present, problems are prefixed with name, eg "Network.Port: ..."
+func Required[T any, P interface {
+	*T
+	Validator
+}](name string, section P) error {
+	if section == nil {
+		return fmt.Errorf("%s: section is missing", name)
+	}
+	return Prefix(name, section.Validate())
+}
+
+// Optional validates a section when present
+func Optional[T any, P interface {
+	*T
+	Validator
+}](name string, section P) error {
+	if section == nil {
+		return nil
+	}
+	return Prefix(name, section.Validate())
+}
+
+// Prefix prefixes each problem joined in err with name
+func Prefix(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return fmt.Errorf("%s.%w", name, err)
+	}
+	var out []error
+	for _, e := range joined.Unwrap() {
+		out = append(out, Prefix(name, e))
+	}
+	return errors.Join(out...)
+}
+
+// Problem is a field's validation error, Prefix adds the section names in front of field
+func Problem(field, format string, args ...any) error {
+	return fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...))
+}
+
+// CheckPort reports ports outside 1-65535
+func CheckPort(field string, port int) error {
+	if port < 1 || port > 65535 {
+		return Problem(field, "%d is not a port, expected 1-65535", port)
+	}
+	return nil
+}
+
+// CheckPortString is CheckPort for ports configured as strings
+func CheckPortString(field, port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return Problem(field, "%q is not a port, expected 1-65535", port)
+	}
+	return CheckPort(field, n)
+}
+
+// CheckFile reports a path that is set but isn't a readable file
+func CheckFile(field, path string) error {
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Problem(field, "%v", err)
+	}
+	if info.IsDir() {
+		return Problem(field, "%s is a directory", path)
+	}
+	return nil
+}
+
+// MustValidate exits the process listing every problem of cfg, loaded from path, when it's invalid.
+// With checkOnly, eg from -check-config, it also exits when cfg is valid
+func MustValidate(path string, cfg Validator, checkOnly bool) {
+	if err := cfg.Validate(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "invalid config %s:\n%v\n", path, err)
+		os.Exit(1)
+	}
+	if checkOnly {
+		fmt.Printf("config %s is valid\n", path)
+		os.Exit(0)
+	}
+}
+
+func (a *App) Validate() error {
+	var errs []error
+	if a.Name == "" {
+		errs = append(errs, Problem("Name", "is required"))
+	}
+	if a.Environment != Dev && a.Environment != Prod {
+		errs = append(errs, Problem("Environment", "%q, expected %s or %s", a.Environment, Dev, Prod))
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Network) Validate() error {
+	errs := []error{CheckPort("Port", n.Port)}
+	if n.Ssl.IsEnabled {
+		if n.Ssl.CertFile == "" || n.Ssl.KeyFile == "" {
+			errs = append(errs, Problem("Ssl", "certFile and keyFile are required when enabled"))
+		}
+		errs = append(errs,
+			CheckFile("Ssl.CertFile", n.Ssl.CertFile),
+			CheckFile("Ssl.KeyFile", n.Ssl.KeyFile),
+			CheckFile("Ssl.CAFile", n.Ssl.CAFile))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Cors) Validate() error {
+	if !c.IsEnabled {
+		return nil
+	}
+	var errs []error
+	if len(c.AllowOrigins) == 0 {
+		errs = append(errs, Problem("AllowOrigins", "is empty, every cross-origin request would be rejected"))
+	}
+	for i, o := range c.AllowOrigins {
+		if o == "" {
+			errs = append(errs, Problem(fmt.Sprintf("AllowOrigins[%d]", i), "is empty"))
+		}
+	}
+	if c.AllowMaxAge < 0 {
+		errs = append(errs, Problem("AllowMaxAge", "must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+func (r *RateLimit) Validate() error {
+	if !r.IsEnabled {
+		return nil
+	}
+	var errs []error
+	if r.RequestsPerSec <= 0 {
+		errs = append(errs, Problem("RequestsPerSec", "must be positive"))
+	}
+	if r.Burst < 0 {
+		errs = append(errs, Problem("Burst", "must not be negative"))
+	}
+	return errors.Join(errs...)
+}
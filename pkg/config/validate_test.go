@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testConfig struct {
+	App     *App
+	Network *Network
+	Cors    *Cors
+}
+
+func (c *testConfig) Validate() error {
+	return errors.Join(
+		Required("App", c.App),
+		Required("Network", c.Network),
+		Optional("Cors", c.Cors),
+	)
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	cfg := &testConfig{
+		App:     &App{Environment: "staging"},
+		Network: &Network{Port: 0, Ssl: Ssl{IsEnabled: true, CertFile: "missing.pem"}},
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+
+	want := []string{
+		"App.Name: is required",
+		`App.Environment: "staging"`,
+		"Network.Port: 0 is not a port",
+		"Network.Ssl: certFile and keyFile are required",
+		"Network.Ssl.CertFile:",
+	}
+	for _, w := range want {
+		if !strings.Contains(err.Error(), w) {
+			t.Errorf("expected %q in:\n%v", w, err)
+		}
+	}
+	if strings.Contains(err.Error(), "Cors") {
+		t.Errorf("optional missing section reported:\n%v", err)
+	}
+}
+
+func TestRequired_MissingSection(t *testing.T) {
+	err := (&testConfig{App: &App{Name: "a", Environment: Dev}}).Validate()
+	if err == nil || err.Error() != "Network: section is missing" {
+		t.Errorf("expected missing Network, got %v", err)
+	}
+}
+
+func TestNetwork_Validate(t *testing.T) {
+	dir := t.TempDir()
+	cert := filepath.Join(dir, "cert.pem")
+	if err := os.WriteFile(cert, []byte("cert"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	n := &Network{Port: 8443, Ssl: Ssl{IsEnabled: true, CertFile: cert, KeyFile: cert}}
+	if err := n.Validate(); err != nil {
+		t.Errorf("expected valid network, got %v", err)
+	}
+
+	n.Ssl.KeyFile = dir
+	if err := n.Validate(); err == nil || !strings.Contains(err.Error(), "is a directory") {
+		t.Errorf("expected directory error, got %v", err)
+	}
+}
+
+func TestCheckPortString(t *testing.T) {
+	if err := CheckPortString("Port", "6379"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	for _, p := range []string{"", "redis", "0", "65536"} {
+		if err := CheckPortString("Port", p); err == nil {
+			t.Errorf("expected error for %q", p)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: events/v1/auctions.proto
+
+package eventspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AuctionOpened is published to auction.opened by the auction service.
+// Mirrors schema-version 1 of the JSON payload.
+type AuctionOpened struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AuctionId     string                 `protobuf:"bytes,1,opt,name=auction_id,json=auctionId,proto3" json:"auction_id,omitempty"`
+	EndsAt        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	StartingPrice float64                `protobuf:"fixed64,3,opt,name=starting_price,json=startingPrice,proto3" json:"starting_price,omitempty"`
+	MinIncrement  float64                `protobuf:"fixed64,4,opt,name=min_increment,json=minIncrement,proto3" json:"min_increment,omitempty"`
+	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	Version       int64                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuctionOpened) Reset() {
+	*x = AuctionOpened{}
+	mi := &file_events_v1_auctions_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuctionOpened) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuctionOpened) ProtoMessage() {}
+
+func (x *AuctionOpened) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_auctions_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuctionOpened.ProtoReflect.Descriptor instead.
+func (*AuctionOpened) Descriptor() ([]byte, []int) {
+	return file_events_v1_auctions_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AuctionOpened) GetAuctionId() string {
+	if x != nil {
+		return x.AuctionId
+	}
+	return ""
+}
+
+func (x *AuctionOpened) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *AuctionOpened) GetStartingPrice() float64 {
+	if x != nil {
+		return x.StartingPrice
+	}
+	return 0
+}
+
+func (x *AuctionOpened) GetMinIncrement() float64 {
+	if x != nil {
+		return x.MinIncrement
+	}
+	return 0
+}
+
+func (x *AuctionOpened) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *AuctionOpened) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// AuctionClosed is published to auction.closed by the auction service.
+// Mirrors schema-version 1 of the JSON payload.
+type AuctionClosed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AuctionId     string                 `protobuf:"bytes,1,opt,name=auction_id,json=auctionId,proto3" json:"auction_id,omitempty"`
+	ClosedAt      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=closed_at,json=closedAt,proto3" json:"closed_at,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuctionClosed) Reset() {
+	*x = AuctionClosed{}
+	mi := &file_events_v1_auctions_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuctionClosed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuctionClosed) ProtoMessage() {}
+
+func (x *AuctionClosed) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_auctions_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuctionClosed.ProtoReflect.Descriptor instead.
+func (*AuctionClosed) Descriptor() ([]byte, []int) {
+	return file_events_v1_auctions_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AuctionClosed) GetAuctionId() string {
+	if x != nil {
+		return x.AuctionId
+	}
+	return ""
+}
+
+func (x *AuctionClosed) GetClosedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClosedAt
+	}
+	return nil
+}
+
+func (x *AuctionClosed) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+var File_events_v1_auctions_proto protoreflect.FileDescriptor
+
+const file_events_v1_auctions_proto_rawDesc = "" +
+	"\n" +
+	"\x18events/v1/auctions.proto\x12\tevents.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe5\x01\n" +
+	"\rAuctionOpened\x12\x1d\n" +
+	"\n" +
+	"auction_id\x18\x01 \x01(\tR\tauctionId\x123\n" +
+	"\aends_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x12%\n" +
+	"\x0estarting_price\x18\x03 \x01(\x01R\rstartingPrice\x12#\n" +
+	"\rmin_increment\x18\x04 \x01(\x01R\fminIncrement\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\"\x81\x01\n" +
+	"\rAuctionClosed\x12\x1d\n" +
+	"\n" +
+	"auction_id\x18\x01 \x01(\tR\tauctionId\x127\n" +
+	"\tclosed_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bclosedAt\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversionB$Z\"kei-services/pkg/eventspb;eventspbb\x06proto3"
+
+var (
+	file_events_v1_auctions_proto_rawDescOnce sync.Once
+	file_events_v1_auctions_proto_rawDescData []byte
+)
+
+func file_events_v1_auctions_proto_rawDescGZIP() []byte {
+	file_events_v1_auctions_proto_rawDescOnce.Do(func() {
+		file_events_v1_auctions_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_events_v1_auctions_proto_rawDesc), len(file_events_v1_auctions_proto_rawDesc)))
+	})
+	return file_events_v1_auctions_proto_rawDescData
+}
+
+var file_events_v1_auctions_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_events_v1_auctions_proto_goTypes = []any{
+	(*AuctionOpened)(nil),         // 0: events.v1.AuctionOpened
+	(*AuctionClosed)(nil),         // 1: events.v1.AuctionClosed
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+}
+var file_events_v1_auctions_proto_depIdxs = []int32{
+	2, // 0: events.v1.AuctionOpened.ends_at:type_name -> google.protobuf.Timestamp
+	2, // 1: events.v1.AuctionClosed.closed_at:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_events_v1_auctions_proto_init() }
+func file_events_v1_auctions_proto_init() {
+	if File_events_v1_auctions_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_events_v1_auctions_proto_rawDesc), len(file_events_v1_auctions_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_events_v1_auctions_proto_goTypes,
+		DependencyIndexes: file_events_v1_auctions_proto_depIdxs,
+		MessageInfos:      file_events_v1_auctions_proto_msgTypes,
+	}.Build()
+	File_events_v1_auctions_proto = out.File
+	file_events_v1_auctions_proto_goTypes = nil
+	file_events_v1_auctions_proto_depIdxs = nil
+}
@@ -0,0 +1,174 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: events/v1/bids.proto
+
+package eventspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// BidPlaced is published to bids.placed by bid-command when a bid is accepted, keyed by auction_id.
+// Mirrors schema-version 1 of the JSON payload.
+type BidPlaced struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AuctionId string                 `protobuf:"bytes,1,opt,name=auction_id,json=auctionId,proto3" json:"auction_id,omitempty"`
+	BidId     string                 `protobuf:"bytes,2,opt,name=bid_id,json=bidId,proto3" json:"bid_id,omitempty"`
+	BidderId  string                 `protobuf:"bytes,3,opt,name=bidder_id,json=bidderId,proto3" json:"bidder_id,omitempty"`
+	Amount    float64                `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	At        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=at,proto3" json:"at,omitempty"`
+	// bids table sequence, orders bids of one auction
+	Seq           int64 `protobuf:"varint,6,opt,name=seq,proto3" json:"seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BidPlaced) Reset() {
+	*x = BidPlaced{}
+	mi := &file_events_v1_bids_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BidPlaced) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BidPlaced) ProtoMessage() {}
+
+func (x *BidPlaced) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_bids_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BidPlaced.ProtoReflect.Descriptor instead.
+func (*BidPlaced) Descriptor() ([]byte, []int) {
+	return file_events_v1_bids_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *BidPlaced) GetAuctionId() string {
+	if x != nil {
+		return x.AuctionId
+	}
+	return ""
+}
+
+func (x *BidPlaced) GetBidId() string {
+	if x != nil {
+		return x.BidId
+	}
+	return ""
+}
+
+func (x *BidPlaced) GetBidderId() string {
+	if x != nil {
+		return x.BidderId
+	}
+	return ""
+}
+
+func (x *BidPlaced) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *BidPlaced) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *BidPlaced) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+var File_events_v1_bids_proto protoreflect.FileDescriptor
+
+const file_events_v1_bids_proto_rawDesc = "" +
+	"\n" +
+	"\x14events/v1/bids.proto\x12\tevents.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb4\x01\n" +
+	"\tBidPlaced\x12\x1d\n" +
+	"\n" +
+	"auction_id\x18\x01 \x01(\tR\tauctionId\x12\x15\n" +
+	"\x06bid_id\x18\x02 \x01(\tR\x05bidId\x12\x1b\n" +
+	"\tbidder_id\x18\x03 \x01(\tR\bbidderId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x01R\x06amount\x12*\n" +
+	"\x02at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12\x10\n" +
+	"\x03seq\x18\x06 \x01(\x03R\x03seqB$Z\"kei-services/pkg/eventspb;eventspbb\x06proto3"
+
+var (
+	file_events_v1_bids_proto_rawDescOnce sync.Once
+	file_events_v1_bids_proto_rawDescData []byte
+)
+
+func file_events_v1_bids_proto_rawDescGZIP() []byte {
+	file_events_v1_bids_proto_rawDescOnce.Do(func() {
+		file_events_v1_bids_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_events_v1_bids_proto_rawDesc), len(file_events_v1_bids_proto_rawDesc)))
+	})
+	return file_events_v1_bids_proto_rawDescData
+}
+
+var file_events_v1_bids_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_events_v1_bids_proto_goTypes = []any{
+	(*BidPlaced)(nil),             // 0: events.v1.BidPlaced
+	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+}
+var file_events_v1_bids_proto_depIdxs = []int32{
+	1, // 0: events.v1.BidPlaced.at:type_name -> google.protobuf.Timestamp
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_events_v1_bids_proto_init() }
+func file_events_v1_bids_proto_init() {
+	if File_events_v1_bids_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_events_v1_bids_proto_rawDesc), len(file_events_v1_bids_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_events_v1_bids_proto_goTypes,
+		DependencyIndexes: file_events_v1_bids_proto_depIdxs,
+		MessageInfos:      file_events_v1_bids_proto_msgTypes,
+	}.Build()
+	File_events_v1_bids_proto = out.File
+	file_events_v1_bids_proto_goTypes = nil
+	file_events_v1_bids_proto_depIdxs = nil
+}
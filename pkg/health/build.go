@@ -0,0 +1,43 @@
+package health
+
+import (
+	"kei-services/pkg/config"
+	"runtime/debug"
+)
+
+// BuildInfo identifies the running binary
+type BuildInfo struct {
+	Service     string `json:"service"`
+	Version     string `json:"version"`
+	Environment string `json:"environment"`
+	GoVersion   string `json:"goVersion"`
+	Revision    string `json:"revision,omitempty"` // vcs commit the binary was built from
+	BuildTime   string `json:"buildTime,omitempty"`
+	Modified    bool   `json:"modified,omitempty"` // built from a dirty tree
+}
+
+// NewBuildInfo reads the vcs stamp go build embeds, app may be nil
+func NewBuildInfo(service string, app *config.App) BuildInfo {
+	b := BuildInfo{Service: service}
+	if app != nil {
+		b.Version = app.Version
+		b.Environment = string(app.Environment)
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b
+	}
+	b.GoVersion = info.GoVersion
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			b.Revision = s.Value
+		case "vcs.time":
+			b.BuildTime = s.Value
+		case "vcs.modified":
+			b.Modified = s.Value == "true"
+		}
+	}
+	return b
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// SQLPinger is implemented by *sql.DB
+type SQLPinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Postgres pings db, eg the *sql.DB behind gorm
+func Postgres(db SQLPinger) Check {
+	return Check{Name: "postgres", Ping: db.PingContext}
+}
+
+// MongoPinger is implemented by *mongo.Client
+type MongoPinger interface {
+	Ping(ctx context.Context, rp *readpref.ReadPref) error
+}
+
+// Mongo pings the primary, reads and writes both need it
+func Mongo(client MongoPinger) Check {
+	return Check{Name: "mongo", Ping: func(ctx context.Context) error {
+		return client.Ping(ctx, readpref.Primary())
+	}}
+}
+
+// Redis pings client, standalone, sentinel and cluster clients all implement Ping
+func Redis(client interface {
+	Ping(ctx context.Context) *redis.StatusCmd
+}) Check {
+	return Check{Name: "redis", Ping: func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}}
+}
+
+// Kafka asks brokers for the cluster metadata through transport, the writer's or a reader's so TLS and SASL
+// apply, nil uses kafka-go's default. Each of topics must exist with a leader for every partition
+func Kafka(brokers []string, transport kafka.RoundTripper, topics ...string) Check {
+	client := &kafka.Client{Addr: kafka.TCP(brokers...), Transport: transport}
+	if len(topics) == 0 {
+		topics = []string{} // no topics rather than nil, which lists every topic
+	}
+	return Check{Name: "kafka", Ping: func(ctx context.Context) error {
+		res, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: topics})
+		if err != nil {
+			return err
+		}
+		if len(res.Brokers) == 0 {
+			return errors.New("no brokers in cluster metadata")
+		}
+		var errs []error
+		for _, t := range res.Topics {
+			if t.Error != nil {
+				errs = append(errs, fmt.Errorf("topic %s: %w", t.Name, t.Error))
+				continue
+			}
+			for _, p := range t.Partitions {
+				if p.Leader.Host == "" {
+					errs = append(errs, fmt.Errorf("topic %s partition %d has no leader", t.Name, p.ID))
+				}
+			}
+		}
+		return errors.Join(errs...)
+	}}
+}
+
+// Func is a custom check
+func Func(name string, ping func(ctx context.Context) error) Check {
+	return Check{Name: name, Ping: ping}
+}
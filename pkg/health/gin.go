@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// probeTimeout bounds a probe request, checks time out individually within it
+const probeTimeout = 3 * time.Second
+
+// Register serves the probes on r: /livez and /healthz answer while the process runs, /readyz while every
+// required check passes and /startupz once the service started
+func (h *Health) Register(r gin.IRoutes) {
+	live := func(c *gin.Context) {
+		c.JSON(http.StatusOK, h.body(StatusAlive, nil))
+	}
+	r.GET("/livez", live)
+	r.GET("/healthz", live)
+	r.GET("/readyz", h.probe(h.Readiness))
+	r.GET("/startupz", h.probe(h.Startup))
+}
+
+// Handler serves the probes, for services without a gin server of their own
+func (h *Health) Handler() http.Handler {
+	r := gin.New()
+	h.Register(r)
+	return r
+}
+
+func (h *Health) probe(run func(context.Context) (string, bool, []Result)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), probeTimeout)
+		defer cancel()
+
+		status, ok, results := run(ctx)
+		code := http.StatusOK
+		if !ok {
+			code = http.StatusServiceUnavailable
+		}
+		c.JSON(code, h.body(status, results))
+	}
+}
+
+func (h *Health) body(status string, results []Result) gin.H {
+	body := gin.H{
+		"status":    status,
+		"startedAt": h.startedAt,
+		"uptime":    h.Uptime().String(),
+		"build":     h.build,
+	}
+	if results != nil {
+		body["checks"] = results
+	}
+	return body
+}
@@ -0,0 +1,178 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultTimeout  = time.Second
+	DefaultCacheTTL = 2 * time.Second
+
+	NoCache time.Duration = -1 // for in-memory checks, run on every probe
+)
+
+// Check reports whether a dependency is usable. Failing checks fail readiness unless Optional, startup
+// only waits for checks with Startup set
+type Check struct {
+	Name     string
+	Ping     func(ctx context.Context) error
+	Timeout  time.Duration // default: DefaultTimeout
+	CacheTTL time.Duration // results are reused this long so probes don't load the dependency, default: DefaultCacheTTL
+	Optional bool          // failing only degrades readiness
+	Startup  bool          // also required before the startup probe passes
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Name      string    `json:"name"`
+	OK        bool      `json:"ok"`
+	Err       string    `json:"err,omitempty"`
+	Optional  bool      `json:"optional,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Health runs a service's checks for the liveness, readiness and startup probes
+type Health struct {
+	build     BuildInfo
+	startedAt time.Time
+	started   atomic.Bool
+	now       func() time.Time
+
+	mu     sync.RWMutex
+	checks []*entry
+}
+
+type entry struct {
+	Check
+	mu   sync.Mutex // one run at a time, concurrent probes share its result
+	last Result
+}
+
+func New(build BuildInfo) *Health {
+	return &Health{build: build, startedAt: time.Now(), now: time.Now}
+}
+
+// Add registers checks, they may be added until the service is started
+func (h *Health) Add(checks ...Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, c := range checks {
+		if c.Timeout <= 0 {
+			c.Timeout = DefaultTimeout
+		}
+		if c.CacheTTL == 0 {
+			c.CacheTTL = DefaultCacheTTL
+		}
+		h.checks = append(h.checks, &entry{Check: c})
+	}
+}
+
+// MarkStarted ends startup, readiness fails until then
+func (h *Health) MarkStarted() { h.started.Store(true) }
+
+func (h *Health) Started() bool { return h.started.Load() }
+
+func (h *Health) StartedAt() time.Time { return h.startedAt }
+
+func (h *Health) Uptime() time.Duration { return h.now().Sub(h.startedAt).Truncate(time.Second) }
+
+// Run runs the checks selected by include concurrently, each bounded by its timeout and ctx, and returns
+// their results in registration order
+func (h *Health) Run(ctx context.Context, include func(Check) bool) []Result {
+	h.mu.RLock()
+	checks := make([]*entry, 0, len(h.checks))
+	for _, e := range h.checks {
+		if include == nil || include(e.Check) {
+			checks = append(checks, e)
+		}
+	}
+	h.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, e := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.run(ctx, e)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (h *Health) run(ctx context.Context, e *entry) Result {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.CacheTTL > 0 && !e.last.CheckedAt.IsZero() && h.now().Sub(e.last.CheckedAt) < e.CacheTTL {
+		return e.last
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, e.Timeout)
+	defer cancel()
+
+	start := h.now()
+	err := e.Ping(checkCtx)
+	res := Result{
+		Name:      e.Name,
+		OK:        err == nil,
+		Optional:  e.Optional,
+		Duration:  h.now().Sub(start).String(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		res.Err = err.Error()
+	}
+	if ctx.Err() == nil { // a probe giving up isn't the dependency's fault, don't cache it
+		e.last = res
+	}
+	return res
+}
+
+// Status of a probe
+const (
+	StatusAlive       = "alive"
+	StatusReady       = "ready"
+	StatusDegraded    = "degraded"    // optional checks failing, still ready
+	StatusUnavailable = "unavailable" // not ready
+	StatusStarting    = "starting"
+	StatusStarted     = "started"
+)
+
+// Readiness runs every check, ok unless a required check fails or the service hasn't started
+func (h *Health) Readiness(ctx context.Context) (status string, ok bool, results []Result) {
+	results = h.Run(ctx, nil)
+	if !h.Started() {
+		return StatusStarting, false, results
+	}
+	status = StatusReady
+	for _, r := range results {
+		switch {
+		case r.OK:
+		case r.Optional:
+			status = StatusDegraded
+		default:
+			return StatusUnavailable, false, results
+		}
+	}
+	return status, true, results
+}
+
+// Startup is ok once the service is started and its startup checks pass
+func (h *Health) Startup(ctx context.Context) (status string, ok bool, results []Result) {
+	results = h.Run(ctx, func(c Check) bool { return c.Startup })
+	if !h.Started() {
+		return StatusStarting, false, results
+	}
+	for _, r := range results {
+		if !r.OK {
+			return StatusStarting, false, results
+		}
+	}
+	return StatusStarted, true, results
+}
@@ -0,0 +1,113 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReadiness(t *testing.T) {
+	h := New(BuildInfo{Service: "test"})
+	var cacheErr error
+	h.Add(
+		Func("db", func(context.Context) error { return nil }),
+		Check{Name: "cache", Optional: true, CacheTTL: NoCache, Ping: func(context.Context) error { return cacheErr }},
+	)
+
+	if status, ok, _ := h.Readiness(context.Background()); ok || status != StatusStarting {
+		t.Fatalf("before MarkStarted: got %s/%v, want starting", status, ok)
+	}
+
+	h.MarkStarted()
+	if status, ok, _ := h.Readiness(context.Background()); !ok || status != StatusReady {
+		t.Fatalf("got %s/%v, want ready", status, ok)
+	}
+
+	cacheErr = errors.New("down")
+	if status, ok, _ := h.Readiness(context.Background()); !ok || status != StatusDegraded {
+		t.Fatalf("optional failing: got %s/%v, want degraded and ok", status, ok)
+	}
+}
+
+func TestRun_ConcurrentWithTimeout(t *testing.T) {
+	h := New(BuildInfo{})
+	h.Add(
+		Check{Name: "slow", Timeout: 20 * time.Millisecond, Ping: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+		Check{Name: "slow2", Timeout: 20 * time.Millisecond, Ping: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	)
+
+	start := time.Now()
+	results := h.Run(context.Background(), nil)
+	if d := time.Since(start); d > 200*time.Millisecond {
+		t.Errorf("checks took %s, expected them to run concurrently", d)
+	}
+	for _, r := range results {
+		if r.OK || r.Err == "" {
+			t.Errorf("%s: expected a timeout error, got %+v", r.Name, r)
+		}
+	}
+}
+
+func TestRun_CachesResults(t *testing.T) {
+	h := New(BuildInfo{})
+	var calls atomic.Int32
+	h.Add(Func("db", func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}))
+
+	h.Run(context.Background(), nil)
+	h.Run(context.Background(), nil)
+	if n := calls.Load(); n != 1 {
+		t.Errorf("pinged %d times, want 1 within the cache TTL", n)
+	}
+
+	h.now = func() time.Time { return time.Now().Add(DefaultCacheTTL) }
+	h.Run(context.Background(), nil)
+	if n := calls.Load(); n != 2 {
+		t.Errorf("pinged %d times, want 2 once the cache expired", n)
+	}
+}
+
+func TestRegister_Probes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := New(BuildInfo{Service: "test", Version: "1.2.3"})
+	h.Add(Check{Name: "schema", Startup: true, Ping: func(context.Context) error { return nil }})
+	r := gin.New()
+	h.Register(r)
+
+	get := func(path string) (int, map[string]any) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var body map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &body)
+		return rec.Code, body
+	}
+
+	if code, body := get("/livez"); code != http.StatusOK || body["build"].(map[string]any)["version"] != "1.2.3" {
+		t.Errorf("/livez: got %d %v", code, body)
+	}
+	if code, _ := get("/startupz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/startupz before start: got %d, want 503", code)
+	}
+
+	h.MarkStarted()
+	for _, path := range []string{"/startupz", "/readyz", "/healthz"} {
+		if code, _ := get(path); code != http.StatusOK {
+			t.Errorf("%s: got %d, want 200", path, code)
+		}
+	}
+}
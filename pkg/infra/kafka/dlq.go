@@ -0,0 +1,214 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kei-services/pkg/config"
+	"strconv"
+	"strings"
+	"time"
+
+	segmentKafka "github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// Dead-letter headers, added on top of the original message headers
+const (
+	HeaderDLQError           = "dlq-error"
+	HeaderDLQStage           = "dlq-stage" // where it failed, eg decode or handle
+	HeaderDLQAttempts        = "dlq-attempts"
+	HeaderDLQSourceTopic     = "dlq-source-topic"
+	HeaderDLQSourcePartition = "dlq-source-partition"
+	HeaderDLQSourceOffset    = "dlq-source-offset"
+	HeaderDLQFailedAt        = "dlq-failed-at" // RFC3339Nano
+	HeaderDLQOrigin          = "dlq-origin"    // absent for the live projector, see OriginRebuild
+)
+
+// OriginRebuild marks dead letters written while replaying into a rebuild target. Redrive skips them, the
+// live projection never missed those messages
+const OriginRebuild = "rebuild"
+
+type DeadLetterConfig struct {
+	Topic          string
+	RedriveGroupID string // default: Topic + "-redrive"
+}
+
+func (c *DeadLetterConfig) Validate() error {
+	if c.Topic == "" {
+		return config.Problem("Topic", "is required")
+	}
+	return nil
+}
+
+func (c *DeadLetterConfig) RedriveGroup() string {
+	if c.RedriveGroupID != "" {
+		return c.RedriveGroupID
+	}
+	return c.Topic + "-redrive"
+}
+
+// DeadLetter publishes messages that could not be projected to a per-service dead-letter topic
+type DeadLetter struct {
+	topic  string
+	origin string
+	writer *segmentKafka.Writer
+	now    func() time.Time
+}
+
+// NewDeadLetter publishes to cfg.Topic on brokers, sec is the consumer's Security so both share credentials
+func NewDeadLetter(brokers []string, sec *Security, cfg *DeadLetterConfig, log *zap.Logger) (*DeadLetter, error) {
+	if cfg == nil || cfg.Topic == "" {
+		return nil, errors.New("dead-letter topic is required")
+	}
+	w, err := NewWriter(&WriterConfig{Brokers: brokers, Topic: cfg.Topic, Security: sec}, log)
+	if err != nil {
+		return nil, fmt.Errorf("dead-letter writer: %w", err)
+	}
+	return &DeadLetter{
+		topic:  cfg.Topic,
+		writer: w,
+		now:    time.Now,
+	}, nil
+}
+
+func (d *DeadLetter) Topic() string { return d.topic }
+
+// WithOrigin returns a DeadLetter tagging what it publishes with origin, it shares d's writer so only d
+// is closed. Nil stays nil
+func (d *DeadLetter) WithOrigin(origin string) *DeadLetter {
+	if d == nil {
+		return nil
+	}
+	c := *d
+	c.origin = origin
+	return &c
+}
+
+func (d *DeadLetter) Publish(ctx context.Context, msg segmentKafka.Message, stage string, cause error,
+	attempts int) error {
+	return d.writer.WriteMessages(ctx, d.message(msg, stage, cause, attempts))
+}
+
+func (d *DeadLetter) message(msg segmentKafka.Message, stage string, cause error, attempts int) segmentKafka.Message {
+	dl := DeadLetterMessage(msg, stage, cause, attempts, d.now())
+	if d.origin != "" {
+		dl.Headers = append(dl.Headers, segmentKafka.Header{Key: HeaderDLQOrigin, Value: []byte(d.origin)})
+	}
+	return dl
+}
+
+func (d *DeadLetter) Close() error { return d.writer.Close() }
+
+// DeadLetterMessage copies key, value and the original headers of msg and records why and where it failed.
+// Dead-letter headers already on msg (a re-driven message failing again) are replaced, not appended
+func DeadLetterMessage(msg segmentKafka.Message, stage string, cause error, attempts int,
+	at time.Time) segmentKafka.Message {
+	errText := ""
+	if cause != nil {
+		errText = cause.Error()
+	}
+
+	headers := originalHeaders(msg.Headers)
+	headers = append(headers,
+		segmentKafka.Header{Key: HeaderDLQError, Value: []byte(errText)},
+		segmentKafka.Header{Key: HeaderDLQStage, Value: []byte(stage)},
+		segmentKafka.Header{Key: HeaderDLQAttempts, Value: []byte(strconv.Itoa(attempts))},
+		segmentKafka.Header{Key: HeaderDLQSourceTopic, Value: []byte(msg.Topic)},
+		segmentKafka.Header{Key: HeaderDLQSourcePartition, Value: []byte(strconv.Itoa(msg.Partition))},
+		segmentKafka.Header{Key: HeaderDLQSourceOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		segmentKafka.Header{Key: HeaderDLQFailedAt, Value: []byte(at.UTC().Format(time.RFC3339Nano))},
+	)
+
+	return segmentKafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	}
+}
+
+// DeadLetterRecord is a parsed dead-letter message
+type DeadLetterRecord struct {
+	Partition int   // in the dead-letter topic
+	Offset    int64 // in the dead-letter topic
+	Stage     string
+	Error     string
+	Attempts  int
+	FailedAt  time.Time
+	Origin    string // empty for the live projector, see OriginRebuild
+
+	// Original is the message as it was on the source topic, topic, partition and offset included
+	Original segmentKafka.Message
+}
+
+func ParseDeadLetter(msg segmentKafka.Message) (DeadLetterRecord, error) {
+	rec := DeadLetterRecord{
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Original: segmentKafka.Message{
+			Key:     msg.Key,
+			Value:   msg.Value,
+			Headers: originalHeaders(msg.Headers),
+		},
+	}
+
+	var err error
+	for _, h := range msg.Headers {
+		v := string(h.Value)
+		switch h.Key {
+		case HeaderDLQError:
+			rec.Error = v
+		case HeaderDLQStage:
+			rec.Stage = v
+		case HeaderDLQAttempts:
+			rec.Attempts, err = strconv.Atoi(v)
+		case HeaderDLQSourceTopic:
+			rec.Original.Topic = v
+		case HeaderDLQSourcePartition:
+			rec.Original.Partition, err = strconv.Atoi(v)
+		case HeaderDLQSourceOffset:
+			rec.Original.Offset, err = strconv.ParseInt(v, 10, 64)
+		case HeaderDLQFailedAt:
+			rec.FailedAt, err = time.Parse(time.RFC3339Nano, v)
+		case HeaderDLQOrigin:
+			rec.Origin = v
+		}
+		if err != nil {
+			return DeadLetterRecord{}, fmt.Errorf("dead letter header %s: %w", h.Key, err)
+		}
+	}
+
+	if rec.Original.Topic == "" {
+		return DeadLetterRecord{}, errors.New("dead letter: missing source topic")
+	}
+	return rec, nil
+}
+
+func originalHeaders(hs []segmentKafka.Header) []segmentKafka.Header {
+	out := make([]segmentKafka.Header, 0, len(hs)+7)
+	for _, h := range hs {
+		if strings.HasPrefix(h.Key, "dlq-") {
+			continue
+		}
+		out = append(out, h)
+	}
+	return out
+}
+
+// ReadDeadLetters reads topic from the first retained offset up to the end offsets at call time,
+// without a consumer group so listing never moves any committed offset
+func ReadDeadLetters(ctx context.Context, brokers []string, sec *Security, topic string,
+	fn func(DeadLetterRecord) error) error {
+	ends, err := EndOffsets(ctx, brokers, sec, []string{topic})
+	if err != nil {
+		return err
+	}
+
+	return Replay(ctx, brokers, sec, nil, nil, ends, func(msg segmentKafka.Message) error {
+		rec, err := ParseDeadLetter(msg)
+		if err != nil {
+			return fmt.Errorf("%s/%d@%d: %w", topic, msg.Partition, msg.Offset, err)
+		}
+		return fn(rec)
+	})
+}
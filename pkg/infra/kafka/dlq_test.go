@@ -0,0 +1,133 @@
+package kafka
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	segmentKafka "github.com/segmentio/kafka-go"
+)
+
+func TestDeadLetterMessage_RoundTrip(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	original := segmentKafka.Message{
+		Topic:     "bids.placed",
+		Partition: 2,
+		Offset:    42,
+		Key:       []byte("auction-1"),
+		Value:     []byte(`{"bidId":"b1"}`),
+		Headers:   []segmentKafka.Header{{Key: "content-type", Value: []byte("application/json")}},
+	}
+
+	dl := DeadLetterMessage(original, "handle", errors.New("mongo down"), 3, at)
+
+	if dl.Topic != "" {
+		t.Errorf("expected empty topic so the dead-letter writer topic is used, got %q", dl.Topic)
+	}
+
+	// simulate the broker assigning a position in the dead-letter topic
+	dl.Partition, dl.Offset = 0, 7
+
+	rec, err := ParseDeadLetter(dl)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	if rec.Stage != "handle" || rec.Error != "mongo down" || rec.Attempts != 3 {
+		t.Errorf("unexpected failure details: %+v", rec)
+	}
+	if !rec.FailedAt.Equal(at) {
+		t.Errorf("expected failedAt %v, got %v", at, rec.FailedAt)
+	}
+	if rec.Partition != 0 || rec.Offset != 7 {
+		t.Errorf("expected dead-letter position 0@7, got %d@%d", rec.Partition, rec.Offset)
+	}
+	if rec.Original.Topic != "bids.placed" || rec.Original.Partition != 2 || rec.Original.Offset != 42 {
+		t.Errorf("unexpected source position: %s/%d@%d",
+			rec.Original.Topic, rec.Original.Partition, rec.Original.Offset)
+	}
+	if string(rec.Original.Key) != "auction-1" || string(rec.Original.Value) != `{"bidId":"b1"}` {
+		t.Errorf("key/value not preserved")
+	}
+	if len(rec.Original.Headers) != 1 || rec.Original.Headers[0].Key != "content-type" {
+		t.Errorf("expected only the original headers, got %+v", rec.Original.Headers)
+	}
+}
+
+func TestDeadLetterMessage_ReplacesPreviousFailure(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	first := DeadLetterMessage(segmentKafka.Message{Topic: "bids.placed", Offset: 1}, "handle",
+		errors.New("first"), 1, at)
+	rec, err := ParseDeadLetter(first)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	second := DeadLetterMessage(rec.Original, "handle", errors.New("second"), rec.Attempts+1, at)
+
+	count := 0
+	for _, h := range second.Headers {
+		if h.Key == HeaderDLQError {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected a single %s header, got %d", HeaderDLQError, count)
+	}
+
+	rec, err = ParseDeadLetter(second)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if rec.Error != "second" || rec.Attempts != 2 {
+		t.Errorf("expected latest failure, got %+v", rec)
+	}
+}
+
+func TestDeadLetter_WithOrigin(t *testing.T) {
+	live := &DeadLetter{topic: "bid-projector.dlq", now: time.Now}
+	rebuild := live.WithOrigin(OriginRebuild)
+	msg := segmentKafka.Message{Topic: "bids.placed", Offset: 1}
+
+	rec, err := ParseDeadLetter(live.message(msg, "handle", errors.New("x"), 1))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if rec.Origin != "" {
+		t.Errorf("expected no origin from the live dead letter, got %q", rec.Origin)
+	}
+
+	rec, err = ParseDeadLetter(rebuild.message(msg, "handle", errors.New("x"), 1))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if rec.Origin != OriginRebuild {
+		t.Errorf("expected origin %q, got %q", OriginRebuild, rec.Origin)
+	}
+	if len(rec.Original.Headers) != 0 {
+		t.Errorf("origin must not leak into the original headers, got %+v", rec.Original.Headers)
+	}
+
+	if (*DeadLetter)(nil).WithOrigin(OriginRebuild) != nil {
+		t.Error("expected nil to stay nil")
+	}
+}
+
+func TestParseDeadLetter_MissingSourceTopic(t *testing.T) {
+	_, err := ParseDeadLetter(segmentKafka.Message{Value: []byte("x")})
+	if err == nil {
+		t.Error("expected error for message without source topic")
+	}
+}
+
+func TestDeadLetterConfig_RedriveGroup(t *testing.T) {
+	c := &DeadLetterConfig{Topic: "bid-projector.dlq"}
+	if got := c.RedriveGroup(); got != "bid-projector.dlq-redrive" {
+		t.Errorf("unexpected default group %q", got)
+	}
+
+	c.RedriveGroupID = "custom"
+	if got := c.RedriveGroup(); got != "custom" {
+		t.Errorf("expected explicit group, got %q", got)
+	}
+}
@@ -3,10 +3,15 @@ package kafka
 import (
 	"crypto/tls"
 	"errors"
+	"fmt"
+	"kei-services/pkg/config"
+	"kei-services/pkg/metrics"
+	"net"
+	"strings"
 	"time"
 
 	segmentKafka "github.com/segmentio/kafka-go"
-	"github.com/segmentio/kafka-go/sasl/plain"
+	"go.uber.org/zap"
 )
 
 // OffsetMode controls where a new group starts reading.
@@ -36,11 +41,16 @@ type ReaderConfig struct {
 
 	Offset OffsetMode // default OffsetLast
 
-	TLS       *tls.Config
+	TLS       *tls.Config // built in code, takes precedence over Security.TLS
 	SASLPlain *struct {
 		Username string
 		Password string
-	}
+	} // takes precedence over Security.SASL
+	Security *Security
+
+	// optional instrumentation, wired in code
+	Metrics *metrics.Registry `json:"-" mapstructure:"-"` // exports Stats() as <ns>_kafka_reader_* on every scrape
+	Log     *zap.Logger       `json:"-" mapstructure:"-"` // kafka-go's own logs
 }
 
 func NewReader(cfg *ReaderConfig) (*segmentKafka.Reader, error) {
@@ -80,29 +90,84 @@ func NewReader(cfg *ReaderConfig) (*segmentKafka.Reader, error) {
 		rc.GroupTopics = append([]string(nil), cfg.GroupTopics...)
 	}
 
-	if cfg.TLS != nil || cfg.SASLPlain != nil {
-		dialer := &segmentKafka.Dialer{
-			Timeout:   10 * time.Second,
-			DualStack: true,
-			TLS:       cfg.TLS,
-		}
-		if cfg.SASLPlain != nil {
-			dialer.SASLMechanism = plain.Mechanism{
-				Username: cfg.SASLPlain.Username,
-				Password: cfg.SASLPlain.Password,
-			}
+	tlsCfg, mech, err := connSecurity(cfg.TLS, cfg.SASLPlain, cfg.Security)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil || mech != nil {
+		rc.Dialer = &segmentKafka.Dialer{
+			Timeout:       10 * time.Second,
+			DualStack:     true,
+			TLS:           tlsCfg,
+			SASLMechanism: mech,
 		}
-		rc.Dialer = dialer
+	}
+
+	topic := cfg.Topic
+	if !single {
+		topic = strings.Join(cfg.GroupTopics, ",")
+	}
+	if cfg.Log != nil {
+		rc.Logger, rc.ErrorLogger = zapLoggers(cfg.Log, "kafka reader "+cfg.GroupID)
 	}
 
 	r := segmentKafka.NewReader(rc)
 
-	// todo: add metrics hook
-	// todo: figure out how to log from reader
+	if cfg.Metrics != nil {
+		if err := cfg.Metrics.Reg.Register(newReaderCollector(r, cfg.Metrics, cfg.GroupID, topic)); err != nil {
+			_ = r.Close()
+			return nil, fmt.Errorf("register reader metrics: %w", err)
+		}
+	}
 
 	return r, nil
 }
 
+// Transport dials the brokers with cfg's TLS and SASL, for admin requests such as health checks
+func (cfg *ReaderConfig) Transport() (segmentKafka.RoundTripper, error) {
+	tlsCfg, mech, err := connSecurity(cfg.TLS, cfg.SASLPlain, cfg.Security)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg == nil && mech == nil {
+		return segmentKafka.DefaultTransport, nil
+	}
+	return &segmentKafka.Transport{DialTimeout: 10 * time.Second, TLS: tlsCfg, SASL: mech}, nil
+}
+
+func (cfg *ReaderConfig) Validate() error {
+	errs := []error{checkBrokers(cfg.Brokers)}
+	if cfg.GroupID == "" {
+		errs = append(errs, config.Problem("GroupID", "is required"))
+	}
+	if (cfg.Topic == "") == (len(cfg.GroupTopics) == 0) {
+		errs = append(errs, config.Problem("Topic", "set either topic or groupTopics"))
+	}
+	switch cfg.Offset {
+	case "", OffsetFirst, OffsetLast:
+	default:
+		errs = append(errs, config.Problem("Offset", "%q, expected first or last", cfg.Offset))
+	}
+	if cfg.MinBytes < 0 || cfg.MaxBytes < 0 || (cfg.MaxBytes > 0 && cfg.MinBytes > cfg.MaxBytes) {
+		errs = append(errs, config.Problem("MinBytes", "must not be negative or exceed maxBytes"))
+	}
+	errs = append(errs, config.Optional("Security", cfg.Security))
+	return errors.Join(errs...)
+}
+
+func checkBrokers(brokers []string) error {
+	if len(brokers) == 0 {
+		return config.Problem("Brokers", "is empty")
+	}
+	var errs []error
+	for i, b := range brokers {
+		if _, port, err := net.SplitHostPort(b); err != nil || port == "" {
+			errs = append(errs, config.Problem(fmt.Sprintf("Brokers[%d]", i), "%q, expected host:port", b))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func defaultInt(v, def int) int {
 	if v == 0 {
 		return def
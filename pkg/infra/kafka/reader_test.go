@@ -1,6 +1,7 @@
 package kafka
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -270,3 +271,28 @@ func TestOffsetModeConstants(t *testing.T) {
 		t.Errorf("expected OffsetFirst to be 'first', got %s", OffsetFirst)
 	}
 }
+
+func TestReaderConfig_Validate(t *testing.T) {
+	cfg := &ReaderConfig{
+		Brokers:     []string{"kafka"},
+		Topic:       "a",
+		GroupTopics: []string{"b"},
+		Offset:      "middle",
+		Security:    &Security{SASL: &SASLConfig{Mechanism: "GSSAPI", Username: "u"}},
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{"Brokers[0]", "GroupID", "Topic", "Offset", "Security.SASL"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %s in:\n%v", want, err)
+		}
+	}
+
+	valid := &ReaderConfig{Brokers: []string{"kafka:9092"}, GroupTopics: []string{"b"}, GroupID: "g"}
+	if err = valid.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+}
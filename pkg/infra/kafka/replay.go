@@ -0,0 +1,198 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	segmentKafka "github.com/segmentio/kafka-go"
+)
+
+type TopicPartition struct {
+	Topic     string
+	Partition int
+}
+
+// Offsets maps a partition to an offset, eg where to start or stop reading
+type Offsets map[TopicPartition]int64
+
+// EndOffsets returns the high watermark (next offset to be written) of every partition of topics
+func EndOffsets(ctx context.Context, brokers []string, sec *Security, topics []string) (Offsets, error) {
+	out := Offsets{}
+	err := forEachPartition(ctx, brokers, sec, topics, func(tp TopicPartition, first, last int64) {
+		out[tp] = last
+	})
+	return out, err
+}
+
+// FirstOffsets returns the first retained offset of every partition of topics
+func FirstOffsets(ctx context.Context, brokers []string, sec *Security, topics []string) (Offsets, error) {
+	out := Offsets{}
+	err := forEachPartition(ctx, brokers, sec, topics, func(tp TopicPartition, first, last int64) {
+		out[tp] = first
+	})
+	return out, err
+}
+
+// Replay reads every partition in to from the offset in from (first retained offset when missing) up to,
+// not including, the offset in to. Partitions are read one after the other without a consumer group,
+// so order is kept within a partition and no committed offset moves. Brokers are dialed with sec, nil for
+// plaintext.
+//
+// Topics are replayed in the order of topics, partitions of a topic in ascending order. Topics in to that
+// topics doesn't list follow in name order, so nil replays every topic in name order
+func Replay(ctx context.Context, brokers []string, sec *Security, topics []string, from, to Offsets,
+	fn func(segmentKafka.Message) error) error {
+	dialer, err := sec.Dialer()
+	if err != nil {
+		return err
+	}
+
+	var firsts Offsets
+	for _, tp := range to.ordered(topics) {
+		end := to[tp]
+		start, ok := from[tp]
+		if !ok {
+			if firsts == nil {
+				var err error
+				if firsts, err = FirstOffsets(ctx, brokers, sec, topicsOf(to)); err != nil {
+					return err
+				}
+			}
+			start = firsts[tp]
+		}
+		if end <= start {
+			continue
+		}
+		if err := replayPartition(ctx, brokers, dialer, tp, start, end, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ordered returns the partitions of o sorted by the position of their topic in topics, then by name and
+// partition
+func (o Offsets) ordered(topics []string) []TopicPartition {
+	rank := make(map[string]int, len(topics))
+	for i, t := range topics {
+		if _, ok := rank[t]; !ok {
+			rank[t] = i
+		}
+	}
+	rankOf := func(t string) int {
+		if r, ok := rank[t]; ok {
+			return r
+		}
+		return len(topics)
+	}
+
+	out := make([]TopicPartition, 0, len(o))
+	for tp := range o {
+		out = append(out, tp)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if ra, rb := rankOf(a.Topic), rankOf(b.Topic); ra != rb {
+			return ra < rb
+		}
+		if a.Topic != b.Topic {
+			return a.Topic < b.Topic
+		}
+		return a.Partition < b.Partition
+	})
+	return out
+}
+
+// Remaining is the number of messages between from and to
+func Remaining(from, to Offsets) int64 {
+	var n int64
+	for tp, end := range to {
+		if d := end - from[tp]; d > 0 {
+			n += d
+		}
+	}
+	return n
+}
+
+func replayPartition(ctx context.Context, brokers []string, dialer *segmentKafka.Dialer, tp TopicPartition,
+	start, end int64, fn func(segmentKafka.Message) error) error {
+	r := segmentKafka.NewReader(segmentKafka.ReaderConfig{
+		Brokers:   brokers,
+		Dialer:    dialer,
+		Topic:     tp.Topic,
+		Partition: tp.Partition,
+		MinBytes:  1,
+		MaxBytes:  10 << 20,
+	})
+	defer r.Close()
+
+	if err := r.SetOffset(start); err != nil {
+		return fmt.Errorf("seek %s/%d: %w", tp.Topic, tp.Partition, err)
+	}
+
+	for {
+		msg, err := r.ReadMessage(ctx)
+		if err != nil {
+			return fmt.Errorf("read %s/%d: %w", tp.Topic, tp.Partition, err)
+		}
+		// compaction or transactions can leave gaps, never read past end
+		if msg.Offset >= end {
+			return nil
+		}
+		if err = fn(msg); err != nil {
+			return err
+		}
+		if msg.Offset >= end-1 {
+			return nil
+		}
+	}
+}
+
+func topicsOf(o Offsets) []string {
+	seen := map[string]bool{}
+	var out []string
+	for tp := range o {
+		if !seen[tp.Topic] {
+			seen[tp.Topic] = true
+			out = append(out, tp.Topic)
+		}
+	}
+	return out
+}
+
+func forEachPartition(ctx context.Context, brokers []string, sec *Security, topics []string,
+	fn func(tp TopicPartition, first, last int64)) error {
+	if len(brokers) == 0 {
+		return errors.New("no brokers provided")
+	}
+	dialer, err := sec.Dialer()
+	if err != nil {
+		return err
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", brokers[0])
+	if err != nil {
+		return fmt.Errorf("dial broker: %w", err)
+	}
+	defer conn.Close()
+
+	parts, err := conn.ReadPartitions(topics...)
+	if err != nil {
+		return fmt.Errorf("read partitions: %w", err)
+	}
+
+	for _, p := range parts {
+		leader, err := dialer.DialLeader(ctx, "tcp", brokers[0], p.Topic, p.ID)
+		if err != nil {
+			return fmt.Errorf("dial leader %s/%d: %w", p.Topic, p.ID, err)
+		}
+		first, last, err := leader.ReadOffsets()
+		_ = leader.Close()
+		if err != nil {
+			return fmt.Errorf("read offsets %s/%d: %w", p.Topic, p.ID, err)
+		}
+		fn(TopicPartition{Topic: p.Topic, Partition: p.ID}, first, last)
+	}
+	return nil
+}
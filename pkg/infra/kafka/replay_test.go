@@ -0,0 +1,47 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemaining(t *testing.T) {
+	a := TopicPartition{Topic: "bids.placed", Partition: 0}
+	b := TopicPartition{Topic: "bids.placed", Partition: 1}
+
+	got := Remaining(Offsets{a: 10}, Offsets{a: 15, b: 4})
+	if got != 9 {
+		t.Errorf("expected 9 remaining (5 on p0, 4 on unread p1), got %d", got)
+	}
+
+	if got = Remaining(Offsets{a: 20}, Offsets{a: 15}); got != 0 {
+		t.Errorf("expected 0 when already past the end, got %d", got)
+	}
+}
+
+func TestOffsetsOrdered(t *testing.T) {
+	o := Offsets{
+		{Topic: "auction.closed", Partition: 0}: 1,
+		{Topic: "bids.placed", Partition: 1}:    1,
+		{Topic: "bids.placed", Partition: 0}:    1,
+		{Topic: "audit", Partition: 0}:          1,
+		{Topic: "auction.opened", Partition: 0}: 1,
+	}
+
+	got := o.ordered([]string{"auction.opened", "bids.placed", "auction.closed"})
+	want := []TopicPartition{
+		{Topic: "auction.opened", Partition: 0},
+		{Topic: "bids.placed", Partition: 0},
+		{Topic: "bids.placed", Partition: 1},
+		{Topic: "auction.closed", Partition: 0},
+		{Topic: "audit", Partition: 0}, // unlisted topics go last
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ordered:\n got %v\nwant %v", got, want)
+	}
+
+	got = o.ordered(nil)
+	if got[0].Topic != "auction.closed" || got[len(got)-1].Topic != "bids.placed" {
+		t.Errorf("expected name order without topics, got %v", got)
+	}
+}
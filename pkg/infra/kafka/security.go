@@ -0,0 +1,166 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"kei-services/pkg/config"
+	"os"
+	"strings"
+	"time"
+
+	segmentKafka "github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+	"github.com/spf13/viper"
+)
+
+const (
+	SASLPlain       = "PLAIN"
+	SASLScramSHA256 = "SCRAM-SHA-256"
+	SASLScramSHA512 = "SCRAM-SHA-512"
+)
+
+// Security configures TLS and SASL for brokers requiring them, eg managed Kafka with SCRAM-SHA-512 over TLS
+type Security struct {
+	TLS  *TLSConfig
+	SASL *SASLConfig
+}
+
+type TLSConfig struct {
+	IsEnabled          bool
+	CAFile             string // PEM bundle, default: system roots
+	CertFile           string // client certificate for mTLS, with KeyFile
+	KeyFile            string
+	ServerName         string // default: the broker host
+	InsecureSkipVerify bool   // local brokers with self-signed certs only
+}
+
+type SASLConfig struct {
+	Mechanism string // PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512
+	Username  string
+	Password  string `json:"-"`
+}
+
+// BindEnv binds the credentials and file paths of the kafka section viperKey to envPrefix_SASL_MECHANISM,
+// _SASL_USERNAME, _SASL_PASSWORD, _TLS_CA_FILE, _TLS_CERT_FILE and _TLS_KEY_FILE
+func BindEnv(v *viper.Viper, envPrefix, viperKey string) {
+	for key, env := range map[string]string{
+		"sasl.mechanism": "SASL_MECHANISM",
+		"sasl.username":  "SASL_USERNAME",
+		"sasl.password":  "SASL_PASSWORD",
+		"tls.caFile":     "TLS_CA_FILE",
+		"tls.certFile":   "TLS_CERT_FILE",
+		"tls.keyFile":    "TLS_KEY_FILE",
+	} {
+		_ = v.BindEnv(viperKey+".security."+key, envPrefix+"_"+env)
+	}
+}
+
+// Validate builds the TLS config and SASL mechanism, reporting unreadable files and unknown mechanisms
+func (s *Security) Validate() error {
+	var errs []error
+	if _, err := s.TLS.build(); err != nil {
+		errs = append(errs, config.Problem("TLS", "%v", err))
+	}
+	if _, err := s.SASL.build(); err != nil {
+		errs = append(errs, config.Problem("SASL", "%v", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *TLSConfig) build() (*tls.Config, error) {
+	if c == nil || !c.IsEnabled {
+		return nil, nil
+	}
+
+	out := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify, //nolint:gosec // opt-in for local brokers
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read kafka CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in kafka CA bundle %s", c.CAFile)
+		}
+		out.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load kafka client certificate: %w", err)
+		}
+		out.Certificates = []tls.Certificate{cert}
+	}
+	return out, nil
+}
+
+func (c *SASLConfig) build() (sasl.Mechanism, error) {
+	if c == nil || c.Mechanism == "" {
+		return nil, nil
+	}
+	if c.Username == "" {
+		return nil, errors.New("kafka SASL username is required")
+	}
+
+	switch strings.ToUpper(c.Mechanism) {
+	case SASLPlain:
+		return plain.Mechanism{Username: c.Username, Password: c.Password}, nil
+	case SASLScramSHA256:
+		return scram.Mechanism(scram.SHA256, c.Username, c.Password)
+	case SASLScramSHA512:
+		return scram.Mechanism(scram.SHA512, c.Username, c.Password)
+	default:
+		return nil, fmt.Errorf("unknown kafka SASL mechanism %q, expected PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512", c.Mechanism)
+	}
+}
+
+// Dialer returns a dialer with s's TLS and SASL for clients built without a ReaderConfig or WriterConfig,
+// eg replays and admin connections. A nil s dials in plaintext
+func (s *Security) Dialer() (*segmentKafka.Dialer, error) {
+	d := &segmentKafka.Dialer{Timeout: 10 * time.Second, DualStack: true}
+	if s == nil {
+		return d, nil
+	}
+	var err error
+	if d.TLS, err = s.TLS.build(); err != nil {
+		return nil, err
+	}
+	if d.SASLMechanism, err = s.SASL.build(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// connSecurity resolves what a client dials with. TLS and SASL PLAIN set in code take precedence over sec
+func connSecurity(tlsCfg *tls.Config, saslPlain *struct{ Username, Password string }, sec *Security) (*tls.Config,
+	sasl.Mechanism, error) {
+	var mech sasl.Mechanism
+	if saslPlain != nil {
+		mech = plain.Mechanism{Username: saslPlain.Username, Password: saslPlain.Password}
+	}
+	if sec == nil {
+		return tlsCfg, mech, nil
+	}
+
+	if tlsCfg == nil {
+		var err error
+		if tlsCfg, err = sec.TLS.build(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if mech == nil {
+		var err error
+		if mech, err = sec.SASL.build(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return tlsCfg, mech, nil
+}
@@ -0,0 +1,146 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"go.uber.org/zap"
+)
+
+func TestSASLConfig_Mechanisms(t *testing.T) {
+	tests := []struct {
+		mechanism string
+		want      string
+	}{
+		{"PLAIN", "PLAIN"},
+		{"scram-sha-256", "SCRAM-SHA-256"},
+		{"SCRAM-SHA-512", "SCRAM-SHA-512"},
+	}
+	for _, tt := range tests {
+		mech, err := (&SASLConfig{Mechanism: tt.mechanism, Username: "u", Password: "p"}).build()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.mechanism, err)
+		}
+		if got := mech.Name(); got != tt.want {
+			t.Errorf("%s: mechanism = %s, want %s", tt.mechanism, got, tt.want)
+		}
+	}
+}
+
+func TestSASLConfig_Invalid(t *testing.T) {
+	if _, err := (&SASLConfig{Mechanism: "GSSAPI", Username: "u"}).build(); err == nil {
+		t.Error("expected error for unsupported mechanism")
+	}
+	if _, err := (&SASLConfig{Mechanism: SASLScramSHA512}).build(); err == nil {
+		t.Error("expected error for missing username")
+	}
+	if mech, err := (&SASLConfig{}).build(); mech != nil || err != nil {
+		t.Errorf("empty mechanism = %v, %v, want no SASL", mech, err)
+	}
+}
+
+func TestTLSConfig_Files(t *testing.T) {
+	if cfg, err := (&TLSConfig{CAFile: "ignored"}).build(); cfg != nil || err != nil {
+		t.Errorf("disabled TLS = %v, %v, want nil", cfg, err)
+	}
+
+	if _, err := (&TLSConfig{IsEnabled: true, CAFile: "missing.pem"}).build(); err == nil {
+		t.Error("expected error for missing CA file")
+	}
+
+	bad := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(bad, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := (&TLSConfig{IsEnabled: true, CAFile: bad}).build()
+	if err == nil || !strings.Contains(err.Error(), "no certificates") {
+		t.Errorf("err = %v, want no certificates", err)
+	}
+
+	cfg, err := (&TLSConfig{IsEnabled: true, ServerName: "kafka"}).build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ServerName != "kafka" || cfg.MinVersion != tls.VersionTLS12 || cfg.RootCAs != nil {
+		t.Errorf("unexpected tls config %+v", cfg)
+	}
+}
+
+func TestConnSecurity_CodeTakesPrecedence(t *testing.T) {
+	code := &tls.Config{ServerName: "code"}
+	sec := &Security{
+		TLS:  &TLSConfig{IsEnabled: true, ServerName: "config"},
+		SASL: &SASLConfig{Mechanism: SASLScramSHA512, Username: "u", Password: "p"},
+	}
+
+	tlsCfg, mech, err := connSecurity(code, &struct{ Username, Password string }{"plain", "p"}, sec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tlsCfg != code {
+		t.Error("expected TLS set in code")
+	}
+	if _, ok := mech.(plain.Mechanism); !ok {
+		t.Errorf("mechanism = %T, want SASLPlain", mech)
+	}
+
+	tlsCfg, mech, err = connSecurity(nil, nil, sec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tlsCfg.ServerName != "config" || mech.Name() != SASLScramSHA512 {
+		t.Errorf("got %s/%s, want config/SCRAM-SHA-512", tlsCfg.ServerName, mech.Name())
+	}
+}
+
+func TestNewWriter_SecurityTransport(t *testing.T) {
+	w, err := NewWriter(&WriterConfig{
+		Brokers:  []string{"localhost:9092"},
+		Topic:    "t",
+		Security: &Security{SASL: &SASLConfig{Mechanism: SASLScramSHA256, Username: "u", Password: "p"}},
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if w.Transport == nil {
+		t.Fatal("expected a transport carrying SASL")
+	}
+
+	if _, err = NewWriter(&WriterConfig{Brokers: []string{"localhost:9092"},
+		Security: &Security{SASL: &SASLConfig{Mechanism: "nope", Username: "u"}}}, zap.NewNop()); err == nil {
+		t.Error("expected error for unsupported mechanism")
+	}
+}
+
+func TestSecurity_Dialer(t *testing.T) {
+	d, err := (*Security)(nil).Dialer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.TLS != nil || d.SASLMechanism != nil {
+		t.Error("expected a plaintext dialer without security")
+	}
+
+	d, err = (&Security{
+		TLS:  &TLSConfig{IsEnabled: true, ServerName: "kafka.internal"},
+		SASL: &SASLConfig{Mechanism: SASLScramSHA512, Username: "u", Password: "p"},
+	}).Dialer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.TLS == nil || d.TLS.ServerName != "kafka.internal" {
+		t.Errorf("expected TLS from security, got %+v", d.TLS)
+	}
+	if d.SASLMechanism == nil || d.SASLMechanism.Name() != SASLScramSHA512 {
+		t.Errorf("expected SASL from security, got %v", d.SASLMechanism)
+	}
+
+	if _, err = (&Security{SASL: &SASLConfig{Mechanism: "GSSAPI", Username: "u"}}).Dialer(); err == nil {
+		t.Error("expected error for unsupported mechanism")
+	}
+}
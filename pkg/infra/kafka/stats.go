@@ -0,0 +1,181 @@
+package kafka
+
+import (
+	"fmt"
+	"kei-services/pkg/metrics"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	segmentKafka "github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// zapLoggers routes kafka-go's logs to log, its chatter at debug and its errors at warn: they're retried
+// internally or returned to the caller, which logs them with context
+func zapLoggers(log *zap.Logger, client string) (logger, errorLogger segmentKafka.LoggerFunc) {
+	log = log.With(zap.String("client", client))
+	return func(msg string, args ...any) {
+			log.Debug(fmt.Sprintf(msg, args...))
+		}, func(msg string, args ...any) {
+			log.Warn(fmt.Sprintf(msg, args...))
+		}
+}
+
+// summaryTotal accumulates kafka-go's per-snapshot count and sum into a cumulative summary
+type summaryTotal struct {
+	count uint64
+	sum   float64
+}
+
+// statSet exports one client's stats. kafka-go resets counters and summaries on every Stats call, so the
+// set keeps running totals and must be the only caller reading stats for metrics
+type statSet struct {
+	mu        sync.Mutex
+	counters  []*prometheus.Desc
+	gauges    []*prometheus.Desc
+	summaries []*prometheus.Desc
+
+	counterTotals []float64
+	summaryTotals []summaryTotal
+}
+
+func newStatSet(ns, subsystem string, consts prometheus.Labels, counters, gauges, summaries [][2]string) *statSet {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(ns, subsystem, name), help, nil, consts)
+	}
+	s := &statSet{
+		counterTotals: make([]float64, len(counters)),
+		summaryTotals: make([]summaryTotal, len(summaries)),
+	}
+	for _, c := range counters {
+		s.counters = append(s.counters, desc(c[0], c[1]))
+	}
+	for _, g := range gauges {
+		s.gauges = append(s.gauges, desc(g[0], g[1]))
+	}
+	for _, m := range summaries {
+		s.summaries = append(s.summaries, desc(m[0], m[1]))
+	}
+	return s
+}
+
+func (s *statSet) Describe(ch chan<- *prometheus.Desc) {
+	for _, group := range [][]*prometheus.Desc{s.counters, s.gauges, s.summaries} {
+		for _, d := range group {
+			ch <- d
+		}
+	}
+}
+
+// collect adds one snapshot: counter deltas, current gauges and summary (count, sum) deltas
+func (s *statSet) collect(ch chan<- prometheus.Metric, counters, gauges []float64, summaries []summaryTotal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, d := range counters {
+		s.counterTotals[i] += d
+		ch <- prometheus.MustNewConstMetric(s.counters[i], prometheus.CounterValue, s.counterTotals[i])
+	}
+	for i, v := range gauges {
+		ch <- prometheus.MustNewConstMetric(s.gauges[i], prometheus.GaugeValue, v)
+	}
+	for i, d := range summaries {
+		t := &s.summaryTotals[i]
+		t.count += d.count
+		t.sum += d.sum
+		ch <- prometheus.MustNewConstSummary(s.summaries[i], t.count, t.sum, nil)
+	}
+}
+
+func durationDelta(d segmentKafka.DurationStats) summaryTotal {
+	return summaryTotal{count: uint64(max(d.Count, 0)), sum: d.Sum.Seconds()}
+}
+
+func sizeDelta(s segmentKafka.SummaryStats) summaryTotal {
+	return summaryTotal{count: uint64(max(s.Count, 0)), sum: float64(s.Sum)}
+}
+
+// readerCollector exports Reader.Stats on every scrape
+type readerCollector struct {
+	*statSet
+	r *segmentKafka.Reader
+}
+
+func newReaderCollector(r *segmentKafka.Reader, reg *metrics.Registry, group, topic string) *readerCollector {
+	return &readerCollector{
+		r: r,
+		statSet: newStatSet(reg.DefaultNS, "kafka_reader", prometheus.Labels{"group": group, "topic": topic},
+			[][2]string{
+				{"dials_total", "Connections opened to brokers."},
+				{"fetches_total", "Fetch requests sent."},
+				{"messages_total", "Messages read."},
+				{"bytes_total", "Message bytes read."},
+				{"rebalances_total", "Consumer group rebalances."},
+				{"timeouts_total", "Fetch requests that timed out."},
+				{"errors_total", "Errors reading from brokers."},
+			},
+			[][2]string{
+				{"lag", "Messages behind the high watermark of the partition last read."},
+				{"offset", "Offset of the last message read."},
+				{"queue_length", "Messages fetched and waiting to be read."},
+				{"queue_capacity", "Capacity of the fetched messages queue."},
+			},
+			[][2]string{
+				{"dial_seconds", "Time to open broker connections."},
+				{"read_seconds", "Time to read fetch responses."},
+				{"wait_seconds", "Time waiting for fetch responses."},
+				{"fetch_size", "Messages per fetch."},
+				{"fetch_bytes", "Bytes per fetch."},
+			}),
+	}
+}
+
+func (c *readerCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.r.Stats()
+	c.collect(ch,
+		[]float64{float64(s.Dials), float64(s.Fetches), float64(s.Messages), float64(s.Bytes),
+			float64(s.Rebalances), float64(s.Timeouts), float64(s.Errors)},
+		[]float64{float64(s.Lag), float64(s.Offset), float64(s.QueueLength), float64(s.QueueCapacity)},
+		[]summaryTotal{durationDelta(s.DialTime), durationDelta(s.ReadTime), durationDelta(s.WaitTime),
+			sizeDelta(s.FetchSize), sizeDelta(s.FetchBytes)},
+	)
+}
+
+// writerCollector exports Writer.Stats on every scrape
+type writerCollector struct {
+	*statSet
+	w *segmentKafka.Writer
+}
+
+func newWriterCollector(w *segmentKafka.Writer, reg *metrics.Registry, topic string) *writerCollector {
+	return &writerCollector{
+		w: w,
+		statSet: newStatSet(reg.DefaultNS, "kafka_writer", prometheus.Labels{"topic": topic},
+			[][2]string{
+				{"writes_total", "Produce requests sent."},
+				{"messages_total", "Messages written."},
+				{"bytes_total", "Message bytes written."},
+				{"errors_total", "Failed writes."},
+				{"retries_total", "Write attempts retried."},
+			},
+			nil,
+			[][2]string{
+				{"batch_seconds", "Time to fill a batch."},
+				{"batch_queue_seconds", "Time a batch waited to be written."},
+				{"write_seconds", "Time to write a batch."},
+				{"wait_seconds", "Time waiting for acks."},
+				{"batch_size", "Messages per batch."},
+				{"batch_bytes", "Bytes per batch."},
+			}),
+	}
+}
+
+func (c *writerCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.w.Stats()
+	c.collect(ch,
+		[]float64{float64(s.Writes), float64(s.Messages), float64(s.Bytes), float64(s.Errors), float64(s.Retries)},
+		nil,
+		[]summaryTotal{durationDelta(s.BatchTime), durationDelta(s.BatchQueueTime), durationDelta(s.WriteTime),
+			durationDelta(s.WaitTime), sizeDelta(s.BatchSize), sizeDelta(s.BatchBytes)},
+	)
+}
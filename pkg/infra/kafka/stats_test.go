@@ -0,0 +1,62 @@
+package kafka
+
+import (
+	"kei-services/pkg/metrics"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+)
+
+func collectAll(s *statSet, counters, gauges []float64, summaries []summaryTotal) []*dto.Metric {
+	ch := make(chan prometheus.Metric, 16)
+	s.collect(ch, counters, gauges, summaries)
+	close(ch)
+
+	var out []*dto.Metric
+	for m := range ch {
+		pb := &dto.Metric{}
+		_ = m.Write(pb)
+		out = append(out, pb)
+	}
+	return out
+}
+
+func TestStatSet_AccumulatesSnapshots(t *testing.T) {
+	s := newStatSet("test", "kafka_reader", nil,
+		[][2]string{{"messages_total", ""}}, [][2]string{{"lag", ""}}, [][2]string{{"wait_seconds", ""}})
+
+	collectAll(s, []float64{3}, []float64{10}, []summaryTotal{{count: 2, sum: 0.5}})
+	got := collectAll(s, []float64{4}, []float64{7}, []summaryTotal{{count: 1, sum: 0.25}})
+
+	if v := got[0].GetCounter().GetValue(); v != 7 {
+		t.Errorf("counter = %v, want running total 7", v)
+	}
+	if v := got[1].GetGauge().GetValue(); v != 7 {
+		t.Errorf("gauge = %v, want last snapshot 7", v)
+	}
+	if c, sum := got[2].GetSummary().GetSampleCount(), got[2].GetSummary().GetSampleSum(); c != 3 || sum != 0.75 {
+		t.Errorf("summary = %d/%v, want 3/0.75", c, sum)
+	}
+}
+
+func TestNewWriter_RegistersMetrics(t *testing.T) {
+	reg := metrics.New(metrics.Options{Namespace: "test"})
+	w, err := NewWriter(&WriterConfig{Brokers: []string{"localhost:9092"}, Topic: "t", Metrics: reg}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	families, err := reg.Reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() == "test_kafka_writer_messages_total" {
+			return
+		}
+	}
+	t.Fatal("test_kafka_writer_messages_total not registered")
+}
@@ -2,7 +2,9 @@ package kafka
 
 import (
 	"crypto/tls"
-	"fmt"
+	"errors"
+	"kei-services/pkg/config"
+	"kei-services/pkg/metrics"
 	"time"
 
 	segmentKafka "github.com/segmentio/kafka-go"
@@ -13,14 +15,23 @@ type WriterConfig struct {
 	Brokers     []string
 	Topic       string // leave empty for multi-topic writer
 	ClientID    string
-	Acks        segmentKafka.RequiredAcks // eg kafka.RequireAll
-	Compression segmentKafka.Compression  // eg kafka.Snappy
-	SASLPlain   *struct{ Username, Password string }
-	TLS         *tls.Config
+	Acks        segmentKafka.RequiredAcks            // eg kafka.RequireAll
+	Compression segmentKafka.Compression             // eg kafka.Snappy
+	SASLPlain   *struct{ Username, Password string } // takes precedence over Security.SASL
+	TLS         *tls.Config                          // built in code, takes precedence over Security.TLS
+	Security    *Security
 	Balancer    segmentKafka.Balancer // eg &kafka.Hash{}, &kafka.LeastBytes{}
+
+	// optional, exports Stats() as <ns>_kafka_writer_* on every scrape
+	Metrics *metrics.Registry `json:"-" mapstructure:"-"`
 }
 
-func NewWriter(cfg *WriterConfig, log *zap.Logger) *segmentKafka.Writer {
+func NewWriter(cfg *WriterConfig, log *zap.Logger) (*segmentKafka.Writer, error) {
+	tlsCfg, mech, err := connSecurity(cfg.TLS, cfg.SASLPlain, cfg.Security)
+	if err != nil {
+		return nil, err
+	}
+
 	w := &segmentKafka.Writer{
 		Addr:         segmentKafka.TCP(cfg.Brokers...),
 		Topic:        cfg.Topic,
@@ -33,19 +44,32 @@ func NewWriter(cfg *WriterConfig, log *zap.Logger) *segmentKafka.Writer {
 	}
 	w.AllowAutoTopicCreation = true
 
-	// todo: add metrics hook
+	if tlsCfg != nil || mech != nil || cfg.ClientID != "" {
+		w.Transport = &segmentKafka.Transport{
+			DialTimeout: 10 * time.Second,
+			ClientID:    cfg.ClientID,
+			TLS:         tlsCfg,
+			SASL:        mech,
+		}
+	}
 
-	w.Logger = segmentKafka.LoggerFunc(func(msg string, args ...interface{}) {
-		log.Debug("kafka writer", zap.String("msg", fmt.Sprintf(msg, args...)))
-	})
-	w.ErrorLogger = segmentKafka.LoggerFunc(func(msg string, args ...interface{}) {
-		log.Error("kafka writer error", zap.String("msg", fmt.Sprintf(msg, args...)))
-	})
+	w.Logger, w.ErrorLogger = zapLoggers(log, "kafka writer")
 
-	// todo WriterTransport tls
-	//w.Addr = kafka.TCP(cfg.Brokers...)
+	if cfg.Metrics != nil {
+		topic := cfg.Topic
+		if topic == "" {
+			topic = "*" // multi-topic writer
+		}
+		if err := cfg.Metrics.Reg.Register(newWriterCollector(w, cfg.Metrics, topic)); err != nil {
+			log.Warn("register kafka writer metrics", zap.String("topic", topic), zap.Error(err))
+		}
+	}
+
+	return w, nil
+}
 
-	return w
+func (cfg *WriterConfig) Validate() error {
+	return errors.Join(checkBrokers(cfg.Brokers), config.Optional("Security", cfg.Security))
 }
 
 func balancerOrDefault(b segmentKafka.Balancer) segmentKafka.Balancer {
@@ -15,7 +15,10 @@ func TestNewWriter_Basic(t *testing.T) {
 	}
 
 	logger := zap.NewNop()
-	writer := NewWriter(cfg, logger)
+	writer, err := NewWriter(cfg, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if writer == nil {
 		t.Fatal("expected non-nil writer")
@@ -33,7 +36,10 @@ func TestNewWriter_MultiTopic(t *testing.T) {
 	}
 
 	logger := zap.NewNop()
-	writer := NewWriter(cfg, logger)
+	writer, err := NewWriter(cfg, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if writer == nil {
 		t.Fatal("expected non-nil writer")
@@ -56,7 +62,10 @@ func TestNewWriter_WithAcks(t *testing.T) {
 	}
 
 	logger := zap.NewNop()
-	writer := NewWriter(cfg, logger)
+	writer, err := NewWriter(cfg, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if writer == nil {
 		t.Fatal("expected non-nil writer")
@@ -79,7 +88,10 @@ func TestNewWriter_WithCompression(t *testing.T) {
 	}
 
 	logger := zap.NewNop()
-	writer := NewWriter(cfg, logger)
+	writer, err := NewWriter(cfg, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if writer == nil {
 		t.Fatal("expected non-nil writer")
@@ -102,7 +114,10 @@ func TestNewWriter_WithBalancer(t *testing.T) {
 	}
 
 	logger := zap.NewNop()
-	writer := NewWriter(cfg, logger)
+	writer, err := NewWriter(cfg, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if writer == nil {
 		t.Fatal("expected non-nil writer")
@@ -120,7 +135,10 @@ func TestNewWriter_DefaultValues(t *testing.T) {
 	}
 
 	logger := zap.NewNop()
-	writer := NewWriter(cfg, logger)
+	writer, err := NewWriter(cfg, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if writer == nil {
 		t.Fatal("expected non-nil writer")
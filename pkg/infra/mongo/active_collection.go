@@ -0,0 +1,139 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// MetaCollection holds one document per logical collection naming the physical collection currently
+// served. Rebuilds write into a fresh collection and switch the pointer once caught up (blue/green)
+const MetaCollection = "read_model_meta"
+
+// DefaultRefreshInterval is how often readers and writers poll the pointer, a rebuild waits a few
+// intervals after switching before assuming everyone moved over
+const DefaultRefreshInterval = 5 * time.Second
+
+type activeDoc struct {
+	Logical    string    `bson:"_id"`
+	Collection string    `bson:"collection"`
+	SwitchedAt time.Time `bson:"switchedAt"`
+}
+
+// CollectionSource returns the collection to use right now
+type CollectionSource interface {
+	Collection() *mongo.Collection
+}
+
+type staticCollection struct{ c *mongo.Collection }
+
+func (s staticCollection) Collection() *mongo.Collection { return s.c }
+
+// Static is a CollectionSource that never switches
+func Static(c *mongo.Collection) CollectionSource { return staticCollection{c: c} }
+
+// ActiveCollection follows the pointer of a logical collection, falls back to the logical name itself
+// when no pointer exists (never rebuilt)
+type ActiveCollection struct {
+	db      *mongo.Database
+	logical string
+	log     *zap.Logger
+
+	mu   sync.RWMutex
+	coll *mongo.Collection
+}
+
+var _ CollectionSource = (*ActiveCollection)(nil)
+
+func NewActiveCollection(db *mongo.Database, logical string, log *zap.Logger) *ActiveCollection {
+	return &ActiveCollection{
+		db:      db,
+		logical: logical,
+		log:     log,
+		coll:    db.Collection(logical),
+	}
+}
+
+func (a *ActiveCollection) Collection() *mongo.Collection {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.coll
+}
+
+// Refresh reads the pointer and switches to it. prepare (optional) runs against the new collection before
+// it's served, eg to build indexes, a failing prepare keeps the current collection
+func (a *ActiveCollection) Refresh(ctx context.Context, prepare func(context.Context, *mongo.Collection) error) error {
+	name, err := ActiveCollectionName(ctx, a.db, a.logical)
+	if err != nil {
+		return err
+	}
+	if name == a.Collection().Name() {
+		return nil
+	}
+
+	next := a.db.Collection(name)
+	if prepare != nil {
+		if err = prepare(ctx, next); err != nil {
+			return fmt.Errorf("prepare %s: %w", name, err)
+		}
+	}
+
+	a.mu.Lock()
+	prev := a.coll.Name()
+	a.coll = next
+	a.mu.Unlock()
+
+	a.log.Info("active collection switched",
+		zap.String("logical", a.logical), zap.String("from", prev), zap.String("to", name))
+	return nil
+}
+
+// Watch refreshes every interval until ctx is done
+func (a *ActiveCollection) Watch(ctx context.Context, interval time.Duration,
+	prepare func(context.Context, *mongo.Collection) error) {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := a.Refresh(ctx, prepare); err != nil && ctx.Err() == nil {
+				a.log.Warn("refresh active collection", zap.String("logical", a.logical), zap.Error(err))
+			}
+		}
+	}
+}
+
+// ActiveCollectionName returns the physical collection behind logical
+func ActiveCollectionName(ctx context.Context, db *mongo.Database, logical string) (string, error) {
+	var doc activeDoc
+	err := db.Collection(MetaCollection).FindOne(ctx, bson.M{"_id": logical}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return logical, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return doc.Collection, nil
+}
+
+// SwitchActiveCollection points logical at physical
+func SwitchActiveCollection(ctx context.Context, db *mongo.Database, logical, physical string) error {
+	_, err := db.Collection(MetaCollection).UpdateOne(ctx,
+		bson.M{"_id": logical},
+		bson.M{"$set": bson.M{"collection": physical, "switchedAt": time.Now().UTC()}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
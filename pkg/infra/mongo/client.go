@@ -3,7 +3,9 @@ package mongo
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"kei-services/pkg/config"
 	"strings"
 	"time"
 
@@ -88,3 +90,17 @@ func NewClient(cfg *Config, log *zap.Logger) (*Client, error) {
 	log.Info("Connected to MongoDB")
 	return mc, nil
 }
+
+func (c *Config) Validate() error {
+	var errs []error
+	if !strings.HasPrefix(c.URI, "mongodb://") && !strings.HasPrefix(c.URI, "mongodb+srv://") {
+		errs = append(errs, config.Problem("URI", "must start with mongodb:// or mongodb+srv://")) // may hold credentials
+	}
+	if c.DBName == "" {
+		errs = append(errs, config.Problem("DBName", "is required"))
+	}
+	if c.MaxPoolSize > 0 && c.MinPoolSize > c.MaxPoolSize {
+		errs = append(errs, config.Problem("MinPoolSize", "%d exceeds maxPoolSize %d", c.MinPoolSize, c.MaxPoolSize))
+	}
+	return errors.Join(errs...)
+}
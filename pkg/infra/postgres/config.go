@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"errors"
 	"fmt"
+	"kei-services/pkg/config"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -40,3 +42,22 @@ func BindPostgresDb(v *viper.Viper, envPrefix, viperPrefix string) *Config {
 	_ = v.UnmarshalKey(viperPrefix, &db)
 	return &db
 }
+
+func (c *Config) Validate() error {
+	var errs []error
+	for _, f := range [][2]string{{"Host", c.Host}, {"User", c.User}, {"DBName", c.DBName}} {
+		if f[1] == "" {
+			errs = append(errs, config.Problem(f[0], "is required"))
+		}
+	}
+	errs = append(errs, config.CheckPort("Port", c.Port))
+	switch c.SSLMode {
+	case "", "disable", "require", "verify-ca", "verify-full":
+	default:
+		errs = append(errs, config.Problem("SSLMode", "%q, expected disable, require, verify-ca or verify-full", c.SSLMode))
+	}
+	if c.MaxIdleConns < 0 || c.MaxOpenConns < 0 {
+		errs = append(errs, config.Problem("MaxOpenConns", "pool sizes must not be negative"))
+	}
+	return errors.Join(errs...)
+}
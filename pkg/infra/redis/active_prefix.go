@@ -0,0 +1,101 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// DefaultRefreshInterval is how often readers and writers poll the pointer, a rebuild waits a few
+// intervals after switching before assuming everyone moved over
+const DefaultRefreshInterval = 5 * time.Second
+
+// PrefixSource returns the key prefix to use right now
+type PrefixSource interface {
+	Prefix() string
+}
+
+type staticPrefix string
+
+func (s staticPrefix) Prefix() string { return string(s) }
+
+// Static is a PrefixSource that never switches
+func Static(prefix string) PrefixSource { return staticPrefix(prefix) }
+
+// ActivePrefix follows a pointer key naming the key namespace currently served. Rebuilds write into a
+// fresh namespace and switch the pointer once caught up (blue/green). Falls back to the default prefix
+// when the pointer doesn't exist (never rebuilt)
+type ActivePrefix struct {
+	r          redis.UniversalClient
+	pointerKey string
+	log        *zap.Logger
+
+	mu     sync.RWMutex
+	prefix string
+}
+
+var _ PrefixSource = (*ActivePrefix)(nil)
+
+func NewActivePrefix(r redis.UniversalClient, pointerKey, defaultPrefix string, log *zap.Logger) *ActivePrefix {
+	return &ActivePrefix{
+		r:          r,
+		pointerKey: pointerKey,
+		log:        log,
+		prefix:     defaultPrefix,
+	}
+}
+
+func (a *ActivePrefix) Prefix() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.prefix
+}
+
+func (a *ActivePrefix) Refresh(ctx context.Context) error {
+	next, err := a.r.Get(ctx, a.pointerKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	prev := a.prefix
+	a.prefix = next
+	a.mu.Unlock()
+
+	if prev != next {
+		a.log.Info("active key prefix switched",
+			zap.String("pointer", a.pointerKey), zap.String("from", prev), zap.String("to", next))
+	}
+	return nil
+}
+
+// Watch refreshes every interval until ctx is done
+func (a *ActivePrefix) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := a.Refresh(ctx); err != nil && ctx.Err() == nil {
+				a.log.Warn("refresh active key prefix", zap.String("pointer", a.pointerKey), zap.Error(err))
+			}
+		}
+	}
+}
+
+// SwitchActivePrefix points pointerKey at prefix
+func SwitchActivePrefix(ctx context.Context, r redis.UniversalClient, pointerKey, prefix string) error {
+	return r.Set(ctx, pointerKey, prefix, 0).Err()
+}
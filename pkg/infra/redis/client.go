@@ -5,43 +5,52 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
-	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
 
-type Config struct {
-	Addr     string
-	Password string `json:"-"`
-	PoolSize int
-	Port     string
-}
-
-func BindEnv(v *viper.Viper) {
-	_ = v.BindEnv("redis.password", "REDIS_PASSWORD")
-}
+// Client connects in cfg's mode. Callers depend on redis.UniversalClient so the same code runs against a
+// single node, a sentinel-managed primary or a cluster
+func Client(cfg *Config, log *zap.Logger) (redis.UniversalClient, error) {
+	opts, err := cfg.options()
+	if err != nil {
+		return nil, fmt.Errorf("redis options: %w", err)
+	}
 
-func Client(cfg *Config, log *zap.Logger) (*redis.Client, error) {
-	log.Info("Connecting to Redis...")
+	log.Info("Connecting to Redis...", zap.String("mode", cfg.mode()))
 	log.Debug("Connection parameters",
-		zap.String("Host", cfg.Addr),
-		zap.String("Port", cfg.Port),
-		zap.Int("PoolSize", cfg.PoolSize))
+		zap.Strings("Addrs", opts.Addrs),
+		zap.String("MasterName", cfg.MasterName),
+		zap.Int("DB", cfg.DB),
+		zap.Int("PoolSize", cfg.PoolSize),
+		zap.Bool("TLS", opts.TLSConfig != nil))
+
+	var client redis.UniversalClient
+	switch cfg.mode() {
+	case ModeSentinel:
+		client = redis.NewFailoverClient(opts.Failover())
+	case ModeCluster:
+		client = redis.NewClusterClient(opts.Cluster())
+	default:
+		client = redis.NewClient(opts.Simple())
+	}
 
-	client := redis.NewClient(&redis.Options{
-		Addr:     cfg.Addr + ":" + cfg.Port,
-		Password: cfg.Password,
-		PoolSize: cfg.PoolSize,
-	})
+	// spans go to the global tracer provider, a no-op until tracing is enabled
+	if err := redisotel.InstrumentTracing(client, redisotel.WithDBStatement(false)); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("redis tracing: %w", err)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	// ping
 	if err := client.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("redis cluster ping failed: %w", err)
+		_ = client.Close()
+		return nil, fmt.Errorf("redis %s ping failed: %w", cfg.mode(), err)
 	}
 
-	log.Info("Connected to Redis cluster")
+	log.Info("Connected to Redis", zap.String("mode", cfg.mode()))
 	return client, nil
 }
@@ -0,0 +1,186 @@
+package redis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"kei-services/pkg/config"
+	"net"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+)
+
+const (
+	ModeStandalone = "standalone"
+	ModeSentinel   = "sentinel"
+	ModeCluster    = "cluster"
+)
+
+// Config selects the deployment with Mode. Standalone dials Addr:Port, sentinel asks the Addrs sentinels
+// for MasterName's primary, cluster discovers the nodes from the Addrs seeds
+type Config struct {
+	Mode     string // standalone (default), sentinel or cluster
+	Addr     string // standalone host
+	Port     string
+	Addrs    []string // host:port of the sentinels or cluster seed nodes
+	Username string   // ACL user, default: the default user
+	Password string   `json:"-"`
+	DB       int      // standalone and sentinel, cluster only has 0
+
+	MasterName       string // sentinel
+	SentinelUsername string
+	SentinelPassword string `json:"-"`
+
+	PoolSize       int
+	DialTimeoutMs  int // default: 5000
+	ReadTimeoutMs  int // default: 3000, -1 blocks without timeout
+	WriteTimeoutMs int // default: ReadTimeoutMs
+
+	TLS *TLSConfig
+}
+
+type TLSConfig struct {
+	IsEnabled          bool
+	CAFile             string // PEM bundle, default: system roots
+	CertFile           string // client certificate for mTLS, with KeyFile
+	KeyFile            string
+	ServerName         string // default: the node host
+	InsecureSkipVerify bool   // local servers with self-signed certs only
+}
+
+// BindEnv binds the credentials and TLS files to REDIS_USERNAME, REDIS_PASSWORD, REDIS_SENTINEL_PASSWORD,
+// REDIS_TLS_CA_FILE, REDIS_TLS_CERT_FILE and REDIS_TLS_KEY_FILE
+func BindEnv(v *viper.Viper) {
+	for key, env := range map[string]string{
+		"username":         "USERNAME",
+		"password":         "PASSWORD",
+		"sentinelPassword": "SENTINEL_PASSWORD",
+		"tls.caFile":       "TLS_CA_FILE",
+		"tls.certFile":     "TLS_CERT_FILE",
+		"tls.keyFile":      "TLS_KEY_FILE",
+	} {
+		_ = v.BindEnv("redis."+key, "REDIS_"+env)
+	}
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+	switch c.mode() {
+	case ModeStandalone:
+		if c.Addr == "" {
+			errs = append(errs, config.Problem("Addr", "is required"))
+		}
+		errs = append(errs, config.CheckPortString("Port", c.Port))
+	case ModeSentinel, ModeCluster:
+		if len(c.Addrs) == 0 {
+			errs = append(errs, config.Problem("Addrs", "at least one address is required in %s mode", c.Mode))
+		}
+		for _, a := range c.Addrs {
+			if _, port, err := net.SplitHostPort(a); err != nil {
+				errs = append(errs, config.Problem("Addrs", "%q, expected host:port", a))
+			} else {
+				errs = append(errs, config.CheckPortString("Addrs", port))
+			}
+		}
+	default:
+		errs = append(errs, config.Problem("Mode", "%q, expected standalone, sentinel or cluster", c.Mode))
+	}
+	if c.mode() == ModeSentinel && c.MasterName == "" {
+		errs = append(errs, config.Problem("MasterName", "is required in sentinel mode"))
+	}
+	if c.mode() == ModeCluster && c.DB != 0 {
+		errs = append(errs, config.Problem("DB", "cluster mode only has DB 0"))
+	}
+	if c.DB < 0 {
+		errs = append(errs, config.Problem("DB", "must not be negative"))
+	}
+	if c.PoolSize < 0 {
+		errs = append(errs, config.Problem("PoolSize", "must not be negative"))
+	}
+	if c.DialTimeoutMs < 0 {
+		errs = append(errs, config.Problem("DialTimeoutMs", "must not be negative"))
+	}
+	if c.ReadTimeoutMs < -1 || c.WriteTimeoutMs < -1 {
+		errs = append(errs, config.Problem("ReadTimeoutMs", "read and write timeouts must be -1 or more"))
+	}
+	if _, err := c.TLS.build(); err != nil {
+		errs = append(errs, config.Problem("TLS", "%v", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Config) mode() string {
+	if c.Mode == "" {
+		return ModeStandalone
+	}
+	return c.Mode
+}
+
+// options maps c onto go-redis' options, Client picks the client type by Mode
+func (c *Config) options() (*redis.UniversalOptions, error) {
+	tlsCfg, err := c.TLS.build()
+	if err != nil {
+		return nil, err
+	}
+	addrs := c.Addrs
+	if c.mode() == ModeStandalone {
+		addrs = []string{net.JoinHostPort(c.Addr, c.Port)}
+	}
+	return &redis.UniversalOptions{
+		Addrs:            addrs,
+		Username:         c.Username,
+		Password:         c.Password,
+		DB:               c.DB,
+		MasterName:       c.MasterName,
+		SentinelUsername: c.SentinelUsername,
+		SentinelPassword: c.SentinelPassword,
+		PoolSize:         c.PoolSize,
+		DialTimeout:      millis(c.DialTimeoutMs),
+		ReadTimeout:      millis(c.ReadTimeoutMs),
+		WriteTimeout:     millis(c.WriteTimeoutMs),
+		TLSConfig:        tlsCfg,
+	}, nil
+}
+
+// millis keeps 0 for go-redis' default and -1 for no timeout
+func millis(ms int) time.Duration {
+	if ms < 0 {
+		return -1
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+func (c *TLSConfig) build() (*tls.Config, error) {
+	if c == nil || !c.IsEnabled {
+		return nil, nil
+	}
+
+	out := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify, //nolint:gosec // opt-in for local servers
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read redis CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in redis CA bundle %s", c.CAFile)
+		}
+		out.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load redis client certificate: %w", err)
+		}
+		out.Certificates = []tls.Certificate{cert}
+	}
+	return out, nil
+}
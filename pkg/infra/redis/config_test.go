@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfig_ValidateModes(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want []string // substrings of the problems, none when valid
+	}{
+		{"standalone", Config{Addr: "redis", Port: "6379"}, nil},
+		{"standalone without addr", Config{Port: "6379"}, []string{"Addr: is required"}},
+		{"sentinel", Config{Mode: ModeSentinel, Addrs: []string{"s1:26379", "s2:26379"}, MasterName: "mymaster"}, nil},
+		{"sentinel without master", Config{Mode: ModeSentinel, Addrs: []string{"s1:26379"}},
+			[]string{"MasterName: is required"}},
+		{"cluster", Config{Mode: ModeCluster, Addrs: []string{"n1:6379"}}, nil},
+		{"cluster with db", Config{Mode: ModeCluster, Addrs: []string{"n1:6379"}, DB: 2}, []string{"DB: cluster"}},
+		{"cluster bad addr", Config{Mode: ModeCluster, Addrs: []string{"n1"}}, []string{`Addrs: "n1"`}},
+		{"cluster without addrs", Config{Mode: ModeCluster}, []string{"Addrs: at least one"}},
+		{"unknown mode", Config{Mode: "replicated"}, []string{`Mode: "replicated"`}},
+		{"bad timeout", Config{Addr: "redis", Port: "6379", ReadTimeoutMs: -2}, []string{"ReadTimeoutMs"}},
+		{"missing CA", Config{Addr: "redis", Port: "6379", TLS: &TLSConfig{IsEnabled: true, CAFile: "missing.pem"}},
+			[]string{"TLS: read redis CA bundle"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected problems: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected problems %v", tt.want)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("problems %q don't mention %q", err, w)
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_Options(t *testing.T) {
+	standalone := Config{Addr: "redis", Port: "6379", DB: 3, DialTimeoutMs: 1500, ReadTimeoutMs: -1}
+	opts, err := standalone.options()
+	if err != nil {
+		t.Fatal(err)
+	}
+	simple := opts.Simple()
+	if simple.Addr != "redis:6379" || simple.DB != 3 {
+		t.Errorf("standalone = %s db %d, want redis:6379 db 3", simple.Addr, simple.DB)
+	}
+	if simple.DialTimeout != 1500*time.Millisecond || simple.ReadTimeout != -1 {
+		t.Errorf("timeouts = %v, %v, want 1.5s and none", simple.DialTimeout, simple.ReadTimeout)
+	}
+	if simple.TLSConfig != nil {
+		t.Error("TLS set without being enabled")
+	}
+
+	sentinel := Config{Mode: ModeSentinel, Addrs: []string{"s1:26379"}, MasterName: "mymaster", Password: "p",
+		SentinelPassword: "sp", TLS: &TLSConfig{IsEnabled: true, ServerName: "redis.internal"}}
+	if opts, err = sentinel.options(); err != nil {
+		t.Fatal(err)
+	}
+	failover := opts.Failover()
+	if failover.MasterName != "mymaster" || failover.SentinelAddrs[0] != "s1:26379" ||
+		failover.Password != "p" || failover.SentinelPassword != "sp" {
+		t.Errorf("sentinel options = %+v", failover)
+	}
+	if failover.TLSConfig == nil || failover.TLSConfig.ServerName != "redis.internal" {
+		t.Errorf("sentinel TLS = %+v, want server name redis.internal", failover.TLSConfig)
+	}
+}
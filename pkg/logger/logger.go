@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"errors"
 	"kei-services/pkg/config"
 	"os"
 	"path/filepath"
@@ -19,7 +20,13 @@ type Config struct {
 }
 
 func Init(loggerCfg *Config, appCfg *config.App) *zap.Logger {
-	level := getLogLevel(loggerCfg)
+	log, _ := InitWithLevel(loggerCfg, appCfg)
+	return log
+}
+
+// InitWithLevel is Init returning the level too, SetLevel on it changes the logger's level at runtime
+func InitWithLevel(loggerCfg *Config, appCfg *config.App) (*zap.Logger, zap.AtomicLevel) {
+	level := zap.NewAtomicLevelAt(getLogLevel(loggerCfg))
 	encoder := getLogEncoder(loggerCfg)
 
 	var cores []zapcore.Core
@@ -56,13 +63,13 @@ func Init(loggerCfg *Config, appCfg *config.App) *zap.Logger {
 		return zap.New(
 			zapcore.NewTee(cores...),
 			zap.AddCaller(),
-			zap.AddStacktrace(zapcore.ErrorLevel))
+			zap.AddStacktrace(zapcore.ErrorLevel)), level
 	} else {
 		return zap.New(
 			zapcore.NewTee(cores...),
 			zap.AddCaller(),
 			zap.AddStacktrace(zapcore.ErrorLevel),
-			zap.Development())
+			zap.Development()), level
 	}
 }
 
@@ -93,3 +100,23 @@ func getLogEncoder(cfg *Config) zapcore.Encoder {
 
 	return nil
 }
+
+func (c *Config) Validate() error {
+	var errs []error
+	if _, err := zapcore.ParseLevel(c.Level); err != nil {
+		errs = append(errs, config.Problem("Level", "%v", err))
+	}
+	if f := strings.ToLower(c.Format); f != "json" && f != "console" {
+		errs = append(errs, config.Problem("Format", "%q, expected json or console", c.Format))
+	}
+	switch c.Output {
+	case "stdout":
+	case "file", "both":
+		if c.FilePath == "" {
+			errs = append(errs, config.Problem("FilePath", "is required for output %s", c.Output))
+		}
+	default:
+		errs = append(errs, config.Problem("Output", "%q, expected stdout, file or both", c.Output))
+	}
+	return errors.Join(errs...)
+}
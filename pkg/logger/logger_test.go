@@ -4,6 +4,7 @@ import (
 	"kei-services/pkg/config"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"go.uber.org/zap/zapcore"
@@ -258,3 +259,33 @@ func TestInit_InvalidLogDir(t *testing.T) {
 
 	Init(loggerCfg, appCfg)
 }
+
+func TestInitWithLevel_SetLevel(t *testing.T) {
+	logger, level := InitWithLevel(&Config{Level: "info", Format: "json", Output: "stdout"},
+		&config.App{Environment: config.Dev})
+
+	if logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Error("expected debug disabled at info level")
+	}
+
+	level.SetLevel(zapcore.DebugLevel)
+	if !logger.Core().Enabled(zapcore.DebugLevel) {
+		t.Error("expected debug enabled after SetLevel")
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	if err := (&Config{Level: "info", Format: "json", Output: "stdout"}).Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	err := (&Config{Level: "loud", Format: "xml", Output: "file"}).Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{"Level", "Format", "FilePath"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %s in:\n%v", want, err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[string, int](2)
+	later := time.Now().Add(time.Hour)
+	c.Add("a", 1, later)
+	c.Add("b", 2, later)
+	if _, ok := c.Get("a"); !ok { // a is now the most recently used
+		t.Fatal("a missing")
+	}
+	c.Add("c", 3, later)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("a = %v, %v, want 1, true", v, ok)
+	}
+	if v, ok := c.Get("c"); !ok || v != 3 {
+		t.Errorf("c = %v, %v, want 3, true", v, ok)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+}
+
+func TestCache_Expires(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := New[string, int](10)
+	c.now = func() time.Time { return now }
+
+	c.Add("a", 1, now.Add(time.Second))
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a missing before expiry")
+	}
+	now = now.Add(time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Error("a served at its expiry")
+	}
+	if c.Len() != 0 {
+		t.Errorf("expired entry kept, Len = %d", c.Len())
+	}
+}
+
+func TestCache_AddReplaces(t *testing.T) {
+	c := New[string, int](2)
+	later := time.Now().Add(time.Hour)
+	c.Add("a", 1, later)
+	c.Add("a", 2, later)
+
+	if v, _ := c.Get("a"); v != 2 {
+		t.Errorf("a = %d, want 2", v)
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len = %d, want 1", c.Len())
+	}
+}
+
+func TestCache_RemoveAndPurge(t *testing.T) {
+	c := New[string, int](10)
+	later := time.Now().Add(time.Hour)
+	c.Add("a", 1, later)
+	c.Add("b", 2, later)
+
+	if !c.Remove("a") || c.Remove("a") {
+		t.Error("Remove should report a present once")
+	}
+	c.Purge()
+	if _, ok := c.Get("b"); ok || c.Len() != 0 {
+		t.Error("Purge left entries")
+	}
+	c.Add("c", 3, later) // still usable after Purge
+	if _, ok := c.Get("c"); !ok {
+		t.Error("c missing after Purge")
+	}
+}
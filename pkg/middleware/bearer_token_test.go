@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestBearerToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{"valid token", "s3cret", "Bearer s3cret", http.StatusOK},
+		{"wrong token", "s3cret", "Bearer nope", http.StatusUnauthorized},
+		{"missing header", "s3cret", "", http.StatusUnauthorized},
+		{"basic scheme", "s3cret", "Basic s3cret", http.StatusUnauthorized},
+		{"no token configured", "", "Bearer ", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.WarnLevel)
+			r := gin.New()
+			r.GET("/admin", BearerToken(tt.token, zap.New(core)), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("got %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected a WWW-Authenticate challenge")
+			}
+
+			rejected := logs.FilterMessage("unauthorized request rejected").All()
+			if tt.want == http.StatusOK {
+				if len(rejected) != 0 {
+					t.Errorf("accepted request logged as rejected: %v", rejected)
+				}
+				return
+			}
+			if len(rejected) != 1 {
+				t.Fatalf("got %d rejection logs, want 1", len(rejected))
+			}
+			fields := rejected[0].ContextMap()
+			if fields["path"] != "/admin" || fields["clientIp"] == "" {
+				t.Errorf("rejection log lacks the route or client ip: %v", fields)
+			}
+			cred := tt.header[strings.LastIndex(tt.header, " ")+1:]
+			for k, v := range fields {
+				if s, ok := v.(string); ok && cred != "" && strings.Contains(s, cred) {
+					t.Errorf("rejection log field %q carries the presented credentials", k)
+				}
+			}
+		})
+	}
+}
@@ -5,12 +5,43 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+// Origins is a CORS allow list that can be swapped while requests are served
+type Origins struct {
+	list atomic.Pointer[[]string]
+}
+
+func NewOrigins(list []string) *Origins {
+	o := &Origins{}
+	o.Set(list)
+	return o
+}
+
+func (o *Origins) Set(list []string) {
+	list = append([]string(nil), list...)
+	o.list.Store(&list)
+}
+
+func (o *Origins) Allowed(origin string) bool {
+	for _, x := range *o.list.Load() {
+		if strings.EqualFold(x, origin) {
+			return true
+		}
+	}
+	return false
+}
+
 func Cors(cfg *config.Cors, log *zap.Logger) gin.HandlerFunc {
+	return CorsWithOrigins(cfg, NewOrigins(cfg.AllowOrigins), log)
+}
+
+// CorsWithOrigins is Cors checking origins instead of cfg.AllowOrigins, for allow lists changed at runtime
+func CorsWithOrigins(cfg *config.Cors, origins *Origins, log *zap.Logger) gin.HandlerFunc {
 	if !cfg.IsEnabled {
 		return func(c *gin.Context) {
 			// still handle preflight
@@ -26,15 +57,6 @@ func Cors(cfg *config.Cors, log *zap.Logger) gin.HandlerFunc {
 	allowedMethods := strings.Join(cfg.AllowMethods, ",")
 	expHeaders := strings.Join(cfg.ExposeHeaders, ",")
 
-	in := func(list []string, v string) bool {
-		for _, x := range list {
-			if strings.EqualFold(x, v) {
-				return true
-			}
-		}
-		return false
-	}
-
 	addVary := func(c *gin.Context, v string) { c.Writer.Header().Add("Vary", v) }
 
 	return func(c *gin.Context) {
@@ -53,7 +75,7 @@ func Cors(cfg *config.Cors, log *zap.Logger) gin.HandlerFunc {
 			return
 		}
 
-		originAllowed := in(cfg.AllowOrigins, origin)
+		originAllowed := origins.Allowed(origin)
 
 		if cfg.AllowCredentials {
 			if !originAllowed {
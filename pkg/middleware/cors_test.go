@@ -328,3 +328,37 @@ func TestCors_ExposeHeaders(t *testing.T) {
 		t.Error("expected Expose-Headers to be set")
 	}
 }
+
+func TestCorsWithOrigins_Set(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Cors{IsEnabled: true, AllowOrigins: []string{"http://localhost:3000"}}
+	origins := NewOrigins(cfg.AllowOrigins)
+
+	r := gin.New()
+	r.Use(CorsWithOrigins(cfg, origins, zap.NewNop()))
+	r.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(origin string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Origin", origin)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := request("https://example.com"); code != http.StatusForbidden {
+		t.Errorf("expected status 403 before Set, got %d", code)
+	}
+
+	origins.Set([]string{"https://example.com"})
+
+	if code := request("https://example.com"); code != http.StatusOK {
+		t.Errorf("expected status 200 after Set, got %d", code)
+	}
+	if code := request("http://localhost:3000"); code != http.StatusForbidden {
+		t.Errorf("expected status 403 for removed origin, got %d", code)
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"kei-services/pkg/config"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// idleClient is how long a client's bucket is kept after its last request
+const idleClient = 5 * time.Minute
+
+// RateLimiter limits requests per client IP, Update swaps the limits while requests are served
+type RateLimiter struct {
+	mu        sync.Mutex
+	cfg       config.RateLimit
+	clients   map[string]*rateClient
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type rateClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func NewRateLimiter(cfg *config.RateLimit) *RateLimiter {
+	l := &RateLimiter{now: time.Now}
+	l.Update(cfg)
+	return l
+}
+
+// Update applies cfg to every client, nil disables limiting
+func (l *RateLimiter) Update(cfg *config.RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cfg = config.RateLimit{}
+	if cfg != nil {
+		l.cfg = *cfg
+	}
+	l.clients = make(map[string]*rateClient) // buckets restart full with the new limits
+	l.lastSweep = l.now()
+}
+
+// Allow reports whether key may make a request now
+func (l *RateLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.cfg.IsEnabled {
+		return true
+	}
+
+	now := l.now()
+	if now.Sub(l.lastSweep) > idleClient {
+		for k, c := range l.clients {
+			if now.Sub(c.lastSeen) > idleClient {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &rateClient{limiter: rate.NewLimiter(rate.Limit(l.cfg.RequestsPerSec), max(l.cfg.Burst, 1))}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	return c.limiter.AllowN(now, 1)
+}
+
+// Middleware responds 429 to clients over their limit
+func (l *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !l.Allow(c.ClientIP()) {
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
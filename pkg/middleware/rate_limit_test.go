@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"kei-services/pkg/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimiter_Burst(t *testing.T) {
+	l := NewRateLimiter(&config.RateLimit{IsEnabled: true, RequestsPerSec: 1, Burst: 2})
+	now := time.Unix(1000, 0)
+	l.now = func() time.Time { return now }
+
+	if !l.Allow("a") || !l.Allow("a") {
+		t.Fatal("expected the burst to be allowed")
+	}
+	if l.Allow("a") {
+		t.Error("expected request over the burst to be limited")
+	}
+	if !l.Allow("b") {
+		t.Error("expected clients to be limited separately")
+	}
+
+	now = now.Add(time.Second)
+	if !l.Allow("a") {
+		t.Error("expected a token after one second")
+	}
+}
+
+func TestRateLimiter_Update(t *testing.T) {
+	l := NewRateLimiter(&config.RateLimit{IsEnabled: true, RequestsPerSec: 1, Burst: 1})
+	l.Allow("a")
+	if l.Allow("a") {
+		t.Fatal("expected limit")
+	}
+
+	l.Update(&config.RateLimit{IsEnabled: false})
+	if !l.Allow("a") {
+		t.Error("expected no limit once disabled")
+	}
+
+	l.Update(nil)
+	if !l.Allow("a") {
+		t.Error("expected nil config to disable limiting")
+	}
+}
+
+func TestRateLimiter_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(NewRateLimiter(&config.RateLimit{IsEnabled: true, RequestsPerSec: 0.001, Burst: 1}).Middleware())
+	r.GET("/test", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+		if w.Code != want {
+			t.Errorf("request %d: expected status %d, got %d", i, want, w.Code)
+		}
+	}
+}
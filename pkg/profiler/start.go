@@ -2,6 +2,7 @@ package profiler
 
 import (
 	"fmt"
+	"kei-services/pkg/config"
 	"net/http"
 	"net/http/pprof"
 	"time"
@@ -48,3 +49,10 @@ func Start(cfg *Config, log *zap.Logger) error {
 	log.Info("pprof listening", zap.String("addr", addr))
 	return srv.ListenAndServe()
 }
+
+func (c *Config) Validate() error {
+	if !c.IsEnabled {
+		return nil
+	}
+	return config.CheckPort("Port", c.Port)
+}
@@ -0,0 +1,155 @@
+package projector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	kafkaInfra "kei-services/pkg/infra/kafka"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type deadLetterView struct {
+	DLQPartition    int               `json:"dlqPartition"`
+	DLQOffset       int64             `json:"dlqOffset"`
+	SourceTopic     string            `json:"sourceTopic"`
+	SourcePartition int               `json:"sourcePartition"`
+	SourceOffset    int64             `json:"sourceOffset"`
+	Stage           string            `json:"stage"`
+	Error           string            `json:"error"`
+	Attempts        int               `json:"attempts"`
+	FailedAt        time.Time         `json:"failedAt"`
+	Origin          string            `json:"origin,omitempty"`
+	Key             string            `json:"key"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	Value           json.RawMessage   `json:"value,omitempty"`
+	RawValue        string            `json:"rawValue,omitempty"` // when value isn't valid json
+}
+
+// ListDeadLetters writes every retained dead-letter record to w as one JSON object per line
+func ListDeadLetters(ctx context.Context, brokers []string, sec *kafkaInfra.Security, topic string,
+	w io.Writer) (int, error) {
+	enc := json.NewEncoder(w)
+	n := 0
+	err := kafkaInfra.ReadDeadLetters(ctx, brokers, sec, topic, func(rec kafkaInfra.DeadLetterRecord) error {
+		v := deadLetterView{
+			DLQPartition:    rec.Partition,
+			DLQOffset:       rec.Offset,
+			SourceTopic:     rec.Original.Topic,
+			SourcePartition: rec.Original.Partition,
+			SourceOffset:    rec.Original.Offset,
+			Stage:           rec.Stage,
+			Error:           rec.Error,
+			Attempts:        rec.Attempts,
+			FailedAt:        rec.FailedAt,
+			Origin:          rec.Origin,
+			Key:             string(rec.Original.Key),
+		}
+		if len(rec.Original.Headers) > 0 {
+			v.Headers = make(map[string]string, len(rec.Original.Headers))
+			for _, h := range rec.Original.Headers {
+				v.Headers[h.Key] = string(h.Value)
+			}
+		}
+		if json.Valid(rec.Original.Value) {
+			v.Value = rec.Original.Value
+		} else {
+			v.RawValue = string(rec.Original.Value)
+		}
+		n++
+		return enc.Encode(v)
+	})
+	return n, err
+}
+
+// Redrive drains the dead-letter topic back through the router using its own consumer group, so each
+// record is re-driven once. Only records present when it starts are consumed, records failing again are
+// re-published with attempts+1 and left for the next run. Records a rebuild dead-lettered are skipped, they
+// stay listable. Returns once caught up or nothing arrives for idle
+func (p *Projector) Redrive(ctx context.Context, brokers []string, sec *kafkaInfra.Security,
+	cfg *kafkaInfra.DeadLetterConfig, idle time.Duration) (redriven, failed int, err error) {
+	if p.dlq == nil || cfg == nil {
+		return 0, 0, errors.New("no dead-letter topic configured")
+	}
+
+	ends, err := kafkaInfra.EndOffsets(ctx, brokers, sec, []string{cfg.Topic})
+	if err != nil {
+		return 0, 0, fmt.Errorf("end offsets: %w", err)
+	}
+
+	reader, err := kafkaInfra.NewReader(&kafkaInfra.ReaderConfig{
+		Brokers:  brokers,
+		Topic:    cfg.Topic,
+		GroupID:  cfg.RedriveGroup(),
+		Offset:   kafkaInfra.OffsetFirst,
+		Security: sec,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer reader.Close()
+
+	log := p.log.With(zap.String("dlqTopic", cfg.Topic), zap.String("groupID", cfg.RedriveGroup()))
+	log.Info("dead-letter redrive starting", zap.Any("endOffsets", ends))
+
+	done := make(map[kafkaInfra.TopicPartition]bool, len(ends))
+	for tp, end := range ends {
+		if end == 0 {
+			done[tp] = true
+		}
+	}
+
+	for len(done) < len(ends) {
+		fctx, cancel := context.WithTimeout(ctx, idle)
+		msg, err := reader.FetchMessage(fctx)
+		cancel()
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				break // nothing left to redrive on the remaining partitions
+			}
+			return redriven, failed, err
+		}
+
+		// published after we started, most likely by this run, leave it uncommitted for the next one
+		tp := kafkaInfra.TopicPartition{Topic: msg.Topic, Partition: msg.Partition}
+		if msg.Offset >= ends[tp] {
+			done[tp] = true
+			continue
+		}
+
+		rec, err := kafkaInfra.ParseDeadLetter(msg)
+		if err != nil {
+			// can't be re-driven, it stays listable
+			log.Error("skipping malformed dead-letter record",
+				zap.Int("partition", msg.Partition), zap.Int64("offset", msg.Offset), zap.Error(err))
+			failed++
+		} else if rec.Origin == kafkaInfra.OriginRebuild {
+			log.Info("skipping dead-letter record from a rebuild",
+				zap.Int("partition", msg.Partition), zap.Int64("offset", msg.Offset))
+		} else if stage, err := p.process(ctx, rec.Original); err != nil {
+			if err = p.deadLetter(ctx, rec.Original, stage, err, rec.Attempts+1); err != nil {
+				return redriven, failed, err
+			}
+			failed++
+		} else {
+			log.Info("dead-letter record re-driven",
+				zap.String("sourceTopic", rec.Original.Topic),
+				zap.Int("sourcePartition", rec.Original.Partition),
+				zap.Int64("sourceOffset", rec.Original.Offset))
+			redriven++
+		}
+
+		if err = reader.CommitMessages(ctx, msg); err != nil {
+			return redriven, failed, fmt.Errorf("commit: %w", err)
+		}
+		if msg.Offset >= ends[tp]-1 {
+			done[tp] = true
+		}
+	}
+
+	log.Info("dead-letter redrive finished", zap.Int("redriven", redriven), zap.Int("failed", failed))
+	return redriven, failed, nil
+}
@@ -0,0 +1,93 @@
+package projector
+
+import (
+	"context"
+	"kei-services/pkg/metrics"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/segmentio/kafka-go"
+)
+
+// Metrics are the projector's Prometheus collectors. A nil *Metrics records nothing, so projectors
+// built for one-off commands (rebuild, redrive) don't need a registry
+type Metrics struct {
+	handled       *prometheus.CounterVec
+	failed        *prometheus.CounterVec
+	calls         *prometheus.CounterVec
+	latency       *prometheus.HistogramVec
+	lag           *prometheus.GaugeVec
+	lastProcessed *prometheus.GaugeVec
+}
+
+func NewMetrics(reg *metrics.Registry) *Metrics {
+	return &Metrics{
+		handled: metrics.CCounter(reg.Reg, reg.DefaultNS, "projector_messages_handled_total",
+			"Messages projected by every handler of their topic.", nil,
+			[]string{"topic"}),
+		failed: metrics.CCounter(reg.Reg, reg.DefaultNS, "projector_messages_failed_total",
+			"Messages dead-lettered by topic and the stage they failed at (decode, handle).", nil,
+			[]string{"topic", "stage"}),
+		calls: metrics.CCounter(reg.Reg, reg.DefaultNS, "projector_handler_calls_total",
+			"Projector handler calls by topic, handler and result (ok, error).", nil,
+			[]string{"topic", "handler", "result"}),
+		latency: metrics.CHistogram(reg.Reg, reg.DefaultNS, "projector_handler_duration_seconds",
+			"Projector handler latency by topic and handler.", nil,
+			[]string{"topic", "handler"}, prometheus.DefBuckets),
+		lag: metrics.CGauge(reg.Reg, reg.DefaultNS, "projector_consumer_lag",
+			"Messages behind the partition's high watermark as of the last fetch.", nil,
+			[]string{"topic", "partition"}),
+		lastProcessed: metrics.CGauge(reg.Reg, reg.DefaultNS, "projector_last_processed_timestamp_seconds",
+			"Unix time the last message of the partition was projected or dead-lettered.", nil,
+			[]string{"topic", "partition"}),
+	}
+}
+
+// Middleware counts handler calls by topic, handler and result and observes their latency
+func (m *Metrics) Middleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		if m == nil {
+			return next
+		}
+		return func(ctx context.Context, e Event) error {
+			start := time.Now()
+			err := next(ctx, e)
+			m.latency.WithLabelValues(e.Msg.Topic, e.Handler).Observe(time.Since(start).Seconds())
+			result := "ok"
+			if err != nil {
+				result = "error"
+			}
+			m.calls.WithLabelValues(e.Msg.Topic, e.Handler, result).Inc()
+			return err
+		}
+	}
+}
+
+// fetched records the lag reported with msg, the reader's own Stats reset on every call
+func (m *Metrics) fetched(msg kafka.Message) {
+	if m == nil || msg.HighWaterMark == 0 {
+		return
+	}
+	m.lag.WithLabelValues(msg.Topic, strconv.Itoa(msg.Partition)).Set(float64(max(msg.HighWaterMark-msg.Offset-1, 0)))
+}
+
+func (m *Metrics) handledOK(msg kafka.Message) {
+	if m == nil {
+		return
+	}
+	m.handled.WithLabelValues(msg.Topic).Inc()
+	m.processed(msg)
+}
+
+func (m *Metrics) deadLettered(msg kafka.Message, stage string) {
+	if m == nil {
+		return
+	}
+	m.failed.WithLabelValues(msg.Topic, stage).Inc()
+	m.processed(msg)
+}
+
+func (m *Metrics) processed(msg kafka.Message) {
+	m.lastProcessed.WithLabelValues(msg.Topic, strconv.Itoa(msg.Partition)).SetToCurrentTime()
+}
@@ -0,0 +1,34 @@
+package projector
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Logging logs every handler call at debug, the projector logs retries and dead-letters itself
+func Logging(log *zap.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, e Event) error {
+			start := time.Now()
+			err := next(ctx, e)
+			fields := []zap.Field{
+				zap.String("topic", e.Msg.Topic),
+				zap.Int("partition", e.Msg.Partition),
+				zap.Int64("offset", e.Msg.Offset),
+				zap.String("handler", e.Handler),
+				zap.Duration("took", time.Since(start)),
+			}
+			if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+				fields = append(fields, zap.String("traceID", sc.TraceID().String()))
+			}
+			if err != nil {
+				fields = append(fields, zap.Error(err))
+			}
+			log.Debug("handler called", fields...)
+			return err
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package projector
+
+import (
+	"context"
+	"kei-services/pkg/health"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OpsHandler serves the ops endpoints: /metrics and h's probes. p's partition assignment is added to h as
+// a startup and readiness check, so the projector is ready only once it consumes
+func OpsHandler(p *Projector, metricsHandler http.Handler, h *health.Health) http.Handler {
+	h.Add(health.Check{
+		Name:     "kafka-assignment",
+		Ping:     func(context.Context) error { return p.Ready() },
+		Startup:  true,
+		CacheTTL: health.NoCache,
+	})
+
+	r := gin.New()
+	r.GET("/metrics", gin.WrapH(metricsHandler))
+	h.Register(r)
+	return r
+}
@@ -0,0 +1,57 @@
+package projector
+
+import (
+	"context"
+	"errors"
+	"kei-services/pkg/health"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func probe(t *testing.T, h http.Handler, path string) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code
+}
+
+func TestOpsHandler_Readiness(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	p := New(nil, NewRouter(), Options{}, nil)
+	var storeErr error
+	hc := health.New(health.BuildInfo{})
+	hc.Add(health.Check{Name: "store", CacheTTL: health.NoCache,
+		Ping: func(context.Context) error { return storeErr }})
+	hc.MarkStarted()
+	h := OpsHandler(p, http.NotFoundHandler(), hc)
+
+	if code := probe(t, h, "/readyz"); code != http.StatusServiceUnavailable {
+		t.Fatalf("before assignment: got %d, want 503", code)
+	}
+
+	p.assigned.Store(true)
+	if code := probe(t, h, "/readyz"); code != http.StatusOK {
+		t.Fatalf("assigned: got %d, want 200", code)
+	}
+	if code := probe(t, h, "/startupz"); code != http.StatusOK {
+		t.Fatalf("startup once assigned: got %d, want 200", code)
+	}
+
+	storeErr = errors.New("connection refused")
+	if code := probe(t, h, "/readyz"); code != http.StatusServiceUnavailable {
+		t.Fatalf("store down: got %d, want 503", code)
+	}
+}
+
+func TestOpsHandler_Healthz(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := OpsHandler(New(nil, NewRouter(), Options{}, nil), http.NotFoundHandler(), health.New(health.BuildInfo{}))
+	for _, path := range []string{"/healthz", "/livez"} {
+		if code := probe(t, h, path); code != http.StatusOK {
+			t.Fatalf("%s: got %d, want 200 before assignment", path, code)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+// Package projector runs Kafka consumers projecting events into read models. Services register typed
+// handlers per topic on a Router, the Projector takes care of ordering, retries, dead-letters and commits
+package projector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	kafkaInfra "kei-services/pkg/infra/kafka"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// stage a message failed at, recorded in the dead-letter headers
+const (
+	stageDecode = "decode"
+	stageHandle = "handle"
+)
+
+// Projector fetches messages and fans them out to workers sharded by key, so messages of one key (auction)
+// are handled in order while different keys are handled in parallel. Offsets are committed per partition
+// only once every earlier message is done (at-least-once). Handler errors are retried with backoff,
+// messages that still fail or can't be decoded are dead-lettered and committed
+type Projector struct {
+	reader    *kafka.Reader
+	router    *Router
+	dlq       *kafkaInfra.DeadLetter
+	retry     RetryPolicy
+	workers   int
+	queueSize int
+	metrics   *Metrics
+	log       *zap.Logger
+
+	assigned atomic.Bool // partitions assigned and Run still fetching
+
+	mu        sync.Mutex
+	stopFetch context.CancelFunc // set while Run runs, see Shutdown
+	abandon   context.CancelFunc
+	done      chan struct{}
+}
+
+type Options struct {
+	DeadLetter *kafkaInfra.DeadLetter // nil drops failed messages
+	Retry      RetryPolicy
+	Workers    int      // default: 1
+	QueueSize  int      // per worker, fetching blocks when a worker's queue is full, default: 64
+	Metrics    *Metrics // nil records nothing
+}
+
+func New(reader *kafka.Reader, router *Router, opts Options, log *zap.Logger) *Projector {
+	return &Projector{
+		reader:    reader,
+		router:    router,
+		dlq:       opts.DeadLetter,
+		retry:     opts.Retry.withDefaults(),
+		workers:   max(opts.Workers, 1),
+		queueSize: defaultInt(opts.QueueSize, 64),
+		metrics:   opts.Metrics,
+		log:       log,
+	}
+}
+
+func defaultInt(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+// Run fetches and handles messages until ctx is cancelled, which abandons the messages in flight, or
+// Shutdown drains them
+func (p *Projector) Run(ctx context.Context) error {
+	p.log.Info("projector starting")
+
+	ctx, abandon := context.WithCancel(ctx)
+	defer abandon()
+	fetchCtx, stopFetch := context.WithCancel(ctx)
+	defer stopFetch()
+	done := make(chan struct{})
+	defer close(done)
+	p.mu.Lock()
+	p.stopFetch, p.abandon, p.done = stopFetch, abandon, done
+	p.mu.Unlock()
+
+	if err := p.waitForAssignment(fetchCtx, 600*time.Second); err != nil {
+		if fetchCtx.Err() != nil && ctx.Err() == nil {
+			return nil // shut down before the assignment
+		}
+		return fmt.Errorf("no partition assignment, %w", err)
+	} else {
+		p.log.Info("partitions assigned")
+	}
+	p.assigned.Store(true)
+	defer p.assigned.Store(false)
+
+	p.log.Info("starting workers", zap.Int("workers", p.workers), zap.Int("queueSize", p.queueSize))
+
+	marks := newWatermarks()
+	commits := make(chan kafka.Message, p.workers*p.queueSize)
+	committed := make(chan struct{})
+	go func() {
+		defer close(committed)
+		p.commitLoop(ctx, commits)
+	}()
+
+	var wg sync.WaitGroup
+	queues := make([]chan kafka.Message, p.workers)
+	for i := range queues {
+		queues[i] = make(chan kafka.Message, p.queueSize)
+		wg.Add(1)
+		go func(in <-chan kafka.Message) {
+			defer wg.Done()
+			p.work(ctx, in, marks, commits)
+		}(queues[i])
+	}
+
+	// stop fetching, let workers finish or abandon what they hold, then flush the last watermarks
+	defer func() {
+		for _, q := range queues {
+			close(q)
+		}
+		wg.Wait()
+		close(commits)
+		<-committed
+	}()
+
+	for {
+		msg, err := p.reader.FetchMessage(fetchCtx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				p.log.Info("fetching stopped")
+				return nil // normal shutdown
+			}
+			p.log.Error("FetchMessage", zap.Error(err))
+			return err
+		}
+
+		p.metrics.fetched(msg)
+		marks.track(msg)
+		select {
+		case queues[p.shard(msg)] <- msg:
+		case <-fetchCtx.Done():
+			p.log.Info("fetching stopped")
+			return nil
+		}
+	}
+}
+
+// Shutdown stops fetching and waits for Run to handle the messages it already fetched and commit their
+// offsets. Handlers still running when ctx ends are cancelled and their messages stay uncommitted, to be
+// redelivered. The reader's pending commits are flushed by closing it afterwards
+func (p *Projector) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	stopFetch, abandon, done := p.stopFetch, p.abandon, p.done
+	p.mu.Unlock()
+	if done == nil {
+		return nil // never ran
+	}
+
+	stopFetch()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		p.log.Warn("drain deadline reached, abandoning messages in flight")
+		abandon()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// shard picks the worker for msg, the same key always maps to the same worker. Keyless messages are
+// sharded by partition to keep partition order
+func (p *Projector) shard(msg kafka.Message) int {
+	if p.workers == 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	if len(msg.Key) > 0 {
+		_, _ = h.Write(msg.Key)
+	} else {
+		_, _ = h.Write([]byte(msg.Topic + "/" + strconv.Itoa(msg.Partition)))
+	}
+	return int(h.Sum32() % uint32(p.workers))
+}
+
+func (p *Projector) work(ctx context.Context, in <-chan kafka.Message, marks *watermarks,
+	commits chan<- kafka.Message) {
+	for msg := range in {
+		// on shutdown drain without handling, the offsets stay uncommitted and are redelivered
+		if ctx.Err() != nil {
+			continue
+		}
+		hctx, span := startSpan(ctx, msg)
+		err := p.handle(hctx, msg)
+		endSpan(span, err)
+		if err != nil {
+			p.log.Info("context canceled, message left uncommitted",
+				zap.String("topic", msg.Topic), zap.Int("partition", msg.Partition), zap.Int64("offset", msg.Offset))
+			continue
+		}
+		if commit, ok := marks.done(msg); ok {
+			commits <- commit
+		}
+	}
+}
+
+// commitLoop commits watermarks one at a time. Workers can hand them over out of order, lower offsets than
+// the last commit of a partition are skipped so the committed offset never moves backwards
+func (p *Projector) commitLoop(ctx context.Context, commits <-chan kafka.Message) {
+	highest := make(map[topicPartition]int64)
+	for msg := range commits {
+		tp := topicPartition{topic: msg.Topic, partition: msg.Partition}
+		if h, ok := highest[tp]; ok && msg.Offset <= h {
+			continue
+		}
+		highest[tp] = msg.Offset
+
+		// outlives ctx so watermarks reached during shutdown are still committed
+		cctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		err := p.reader.CommitMessages(cctx, msg)
+		cancel()
+		if err != nil {
+			// redelivered after a rebalance or restart, handlers are idempotent
+			p.log.Warn("CommitMessages", zap.Error(err),
+				zap.String("topic", msg.Topic), zap.Int("partition", msg.Partition), zap.Int64("offset", msg.Offset))
+		}
+	}
+}
+
+// handle retries transient handler errors with backoff and dead-letters what still fails. It returns
+// nil once msg can be committed, or ctx's error if shutdown interrupted it before that
+func (p *Projector) handle(ctx context.Context, msg kafka.Message) error {
+	d, err := p.router.route(msg)
+	if err != nil {
+		// decode errors never succeed on retry
+		return p.deadLetterUntilDone(ctx, msg, stageDecode, err, 1)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := d.run(ctx)
+		if err == nil {
+			p.metrics.handledOK(msg)
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if attempt >= p.retry.MaxAttempts {
+			return p.deadLetterUntilDone(ctx, msg, stageHandle, err, attempt)
+		}
+
+		wait := p.retry.backoff(attempt)
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt), attribute.String("error", err.Error())))
+		p.log.Warn("handler failed, retrying",
+			zap.String("topic", msg.Topic),
+			zap.Int("partition", msg.Partition),
+			zap.Int64("offset", msg.Offset),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", wait),
+			zap.Error(err),
+		)
+		if err = sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// deadLetterUntilDone keeps publishing until the dead-letter write succeeds, committing past a message
+// that is neither projected nor dead-lettered would lose it
+func (p *Projector) deadLetterUntilDone(ctx context.Context, msg kafka.Message, stage string, cause error,
+	attempts int) error {
+	// the message's span, if any, ends as failed even once the dead-letter is written
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(cause)
+	span.SetStatus(codes.Error, stage+": "+cause.Error())
+
+	for i := 1; ; i++ {
+		if err := p.deadLetter(ctx, msg, stage, cause, attempts); err == nil {
+			p.metrics.deadLettered(msg, stage)
+			return nil
+		}
+		if err := sleep(ctx, p.retry.backoff(i)); err != nil {
+			return err
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// process routes and handles msg once, returning the stage that failed
+func (p *Projector) process(ctx context.Context, msg kafka.Message) (stage string, err error) {
+	d, err := p.router.route(msg)
+	if err != nil {
+		return stageDecode, err
+	}
+	if err = d.run(ctx); err != nil {
+		return stageHandle, err
+	}
+	return "", nil
+}
+
+// deadLetter publishes msg to the dead-letter topic. Publishing outlives ctx so a message failing
+// during shutdown is still recorded
+func (p *Projector) deadLetter(ctx context.Context, msg kafka.Message, stage string, cause error,
+	attempts int) error {
+	log := p.log.With(
+		zap.String("topic", msg.Topic),
+		zap.Int("partition", msg.Partition),
+		zap.Int64("offset", msg.Offset),
+		zap.String("stage", stage),
+		zap.Int("attempts", attempts),
+		zap.NamedError("cause", cause),
+	)
+
+	if p.dlq == nil {
+		log.Error("message dropped, no dead-letter topic configured")
+		return nil
+	}
+
+	pctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if err := p.dlq.Publish(pctx, msg, stage, cause, attempts); err != nil {
+		log.Error("dead-letter publish failed, message dropped", zap.Error(err))
+		return err
+	}
+
+	log.Warn("message dead-lettered", zap.String("dlqTopic", p.dlq.Topic()))
+	return nil
+}
+
+// Ready reports whether Run has its partitions assigned and is still fetching
+func (p *Projector) Ready() error {
+	if !p.assigned.Load() {
+		return errors.New("waiting for partition assignment")
+	}
+	return nil
+}
+
+// waitForAssignment polls reader stats until the first fetch. Stats resets the reader's counters, which only
+// costs the reader metrics (see kafkaInfra.ReaderConfig.Metrics) the fetches seen before assignment
+func (p *Projector) waitForAssignment(ctx context.Context, maxWait time.Duration) error {
+	deadline := time.Now().Add(maxWait)
+	for {
+		s := p.reader.Stats()
+		if s.Fetches > 0 || s.Messages > 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("still waiting for group assignment")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+func EnsureTopics(ctx context.Context, brokers []string, sec *kafkaInfra.Security, topics []string,
+	numPartitions, replicationFactor int) error {
+	if len(brokers) == 0 {
+		return fmt.Errorf("no brokers provided")
+	}
+	d, err := sec.Dialer()
+	if err != nil {
+		return err
+	}
+	conn, err := d.DialContext(ctx, "tcp", brokers[0])
+	if err != nil {
+		return fmt.Errorf("dial broker: %w", err)
+	}
+	defer conn.Close()
+
+	existing := map[string]bool{}
+	// check if already exists
+	parts, err := conn.ReadPartitions()
+	if err != nil {
+		return fmt.Errorf("read partitions: %w", err)
+	}
+	for _, p := range parts {
+		existing[p.Topic] = true
+	}
+
+	// create topics if doesnt exist
+	var toCreate []kafka.TopicConfig
+	for _, t := range topics {
+		if t == "" {
+			continue
+		}
+		if existing[t] {
+			continue
+		}
+		toCreate = append(toCreate, kafka.TopicConfig{
+			Topic:             t,
+			NumPartitions:     numPartitions,
+			ReplicationFactor: replicationFactor,
+		})
+	}
+
+	if len(toCreate) == 0 {
+		return nil
+	}
+	if err = conn.CreateTopics(toCreate...); err != nil {
+		return fmt.Errorf("create topics: %w", err)
+	}
+	return nil
+}
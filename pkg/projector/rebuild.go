@@ -0,0 +1,142 @@
+package projector
+
+import (
+	"context"
+	"errors"
+	kafkaInfra "kei-services/pkg/infra/kafka"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// RebuildPlan describes a blue/green rebuild: the router writes to a fresh target, Rebuild replays the topics
+// into it until it has caught up with the live projection, then Switch points readers at it
+type RebuildPlan struct {
+	// Topics are replayed one after the other in this order, list a topic before the topics whose events
+	// depend on it, eg auction.opened before bids.placed before auction.closed
+	Topics []string
+	From   kafkaInfra.Offsets // where the replay starts, nil replays from the first retained offset
+
+	// Switch atomically points readers and the live projectors at the rebuilt target
+	Switch func(ctx context.Context) error
+
+	// CatchUpThreshold is the backlog small enough to switch on, default: 1000
+	CatchUpThreshold int64
+
+	// Grace is how long readers and live projectors take to follow the switch. Events the live projectors
+	// wrote to the old target meanwhile are replayed into the new one after it, default: 15s
+	Grace time.Duration
+}
+
+// maxCatchUpPasses bounds the catch-up loop when producers outpace the replay
+const maxCatchUpPasses = 10
+
+// Rebuild replays plan.Topics through the router until the backlog drops below the threshold, switches,
+// waits out the grace period and replays what was written in between. Handlers must be idempotent,
+// the live projectors keep running on the same topics throughout.
+//
+// Messages that fail are dead-lettered with kafkaInfra.OriginRebuild, redrive skips them since the live
+// projection handled them on its own
+func (p *Projector) Rebuild(ctx context.Context, brokers []string, sec *kafkaInfra.Security, plan RebuildPlan) error {
+	if plan.Switch == nil {
+		return errors.New("rebuild plan has no switch")
+	}
+	p.dlq = p.dlq.WithOrigin(kafkaInfra.OriginRebuild)
+	threshold := plan.CatchUpThreshold
+	if threshold <= 0 {
+		threshold = 1000
+	}
+	grace := plan.Grace
+	if grace <= 0 {
+		grace = 15 * time.Second
+	}
+
+	from := plan.From
+	if from == nil {
+		var err error
+		if from, err = kafkaInfra.FirstOffsets(ctx, brokers, sec, plan.Topics); err != nil {
+			return err
+		}
+	}
+
+	for pass := 1; ; pass++ {
+		to, err := kafkaInfra.EndOffsets(ctx, brokers, sec, plan.Topics)
+		if err != nil {
+			return err
+		}
+		remaining := kafkaInfra.Remaining(from, to)
+		if remaining < threshold || pass > maxCatchUpPasses {
+			p.log.Info("rebuild caught up, switching", zap.Int("passes", pass-1), zap.Int64("remaining", remaining))
+			break
+		}
+		p.log.Info("rebuild pass starting", zap.Int("pass", pass), zap.Int64("remaining", remaining))
+		if err = p.Replay(ctx, brokers, sec, plan.Topics, from, to); err != nil {
+			return err
+		}
+		from = to
+	}
+
+	if err := plan.Switch(ctx); err != nil {
+		return err
+	}
+
+	p.log.Info("rebuild switched, waiting for readers to follow", zap.Duration("grace", grace))
+	if err := sleep(ctx, grace); err != nil {
+		return err
+	}
+
+	to, err := kafkaInfra.EndOffsets(ctx, brokers, sec, plan.Topics)
+	if err != nil {
+		return err
+	}
+	return p.Replay(ctx, brokers, sec, plan.Topics, from, to)
+}
+
+// Replay handles every message between from and to, topic by topic in the order of topics and in partition
+// order, with the projector's retry and dead-letter policy, logging progress. It doesn't join the consumer
+// group or commit offsets
+func (p *Projector) Replay(ctx context.Context, brokers []string, sec *kafkaInfra.Security, topics []string,
+	from, to kafkaInfra.Offsets) error {
+	total := kafkaInfra.Remaining(from, to)
+	p.log.Info("replay starting", zap.Int64("messages", total))
+
+	start := time.Now()
+	lastLog := start
+	var done int64
+	err := kafkaInfra.Replay(ctx, brokers, sec, topics, from, to, func(msg kafka.Message) error {
+		if err := p.handle(ctx, msg); err != nil {
+			return err
+		}
+		done++
+		if now := time.Now(); now.Sub(lastLog) >= 5*time.Second {
+			lastLog = now
+			p.logProgress("replay progress", done, total, now.Sub(start))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	p.logProgress("replay done", done, total, time.Since(start))
+	return nil
+}
+
+func (p *Projector) logProgress(msg string, done, total int64, elapsed time.Duration) {
+	percent := 100.0
+	if total > 0 {
+		percent = float64(done) * 100 / float64(total)
+	}
+	var rate float64
+	if s := elapsed.Seconds(); s > 0 {
+		rate = float64(done) / s
+	}
+	p.log.Info(msg,
+		zap.Int64("done", done),
+		zap.Int64("total", total),
+		zap.Float64("percent", percent),
+		zap.Float64("perSecond", rate),
+		zap.Duration("elapsed", elapsed),
+	)
+}
@@ -0,0 +1,36 @@
+package projector
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy bounds how long a failing handler is retried before the message is dead-lettered
+type RetryPolicy struct {
+	MaxAttempts    int           // including the first, default: 5
+	InitialBackoff time.Duration // default: 200ms
+	MaxBackoff     time.Duration // default: 10s
+}
+
+func (r RetryPolicy) withDefaults() RetryPolicy {
+	if r.MaxAttempts <= 0 {
+		r.MaxAttempts = 5
+	}
+	if r.InitialBackoff <= 0 {
+		r.InitialBackoff = 200 * time.Millisecond
+	}
+	if r.MaxBackoff < r.InitialBackoff {
+		r.MaxBackoff = max(10*time.Second, r.InitialBackoff)
+	}
+	return r
+}
+
+// backoff doubles per attempt up to MaxBackoff, with equal jitter so replicas retrying the same outage spread out
+func (r RetryPolicy) backoff(attempt int) time.Duration {
+	d := r.InitialBackoff
+	for i := 1; i < attempt && d < r.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, r.MaxBackoff)
+	return d/2 + rand.N(d/2+1)
+}
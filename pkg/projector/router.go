@@ -0,0 +1,138 @@
+package projector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Event is a decoded message on its way to one registered handler
+type Event struct {
+	Msg     kafka.Message
+	Value   any    // decoded payload, the type the topic was registered with
+	Handler string // name the handler was registered under
+}
+
+// HandlerFunc handles one event for one handler
+type HandlerFunc func(ctx context.Context, e Event) error
+
+// Middleware wraps every handler call, eg for logging, metrics or tracing
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Router maps a topic to its decoder and the handlers (projections) registered for it. Handlers of a
+// topic run in registration order and stop at the first error, a retry resumes at the failed handler
+type Router struct {
+	routes     map[string]*route
+	middleware []Middleware
+}
+
+type route struct {
+	decode   func(kafka.Message) (any, error)
+	handlers []namedHandler
+}
+
+type namedHandler struct {
+	name string
+	fn   HandlerFunc
+}
+
+func NewRouter() *Router {
+	return &Router{routes: make(map[string]*route)}
+}
+
+// Use appends middleware, the first one added is the outermost
+func (r *Router) Use(mw ...Middleware) *Router {
+	r.middleware = append(r.middleware, mw...)
+	return r
+}
+
+// Topics returns the registered topics, sorted
+func (r *Router) Topics() []string {
+	out := make([]string, 0, len(r.routes))
+	for t := range r.routes {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// TopicRoute registers handlers for the events of one topic
+type TopicRoute[E any] struct {
+	r *route
+}
+
+// Topic registers topic with the decoder of its events, registering a topic twice panics
+func Topic[E any](r *Router, topic string, decode func(kafka.Message) (E, error)) *TopicRoute[E] {
+	if _, ok := r.routes[topic]; ok {
+		panic(fmt.Sprintf("projector: topic %q registered twice", topic))
+	}
+	rt := &route{decode: func(msg kafka.Message) (any, error) { return decode(msg) }}
+	r.routes[topic] = rt
+	return &TopicRoute[E]{r: rt}
+}
+
+// Handle adds a handler for the topic's events, name labels its logs and metrics
+func (t *TopicRoute[E]) Handle(name string, h func(ctx context.Context, e E) error) *TopicRoute[E] {
+	t.r.handlers = append(t.r.handlers, namedHandler{
+		name: name,
+		fn: func(ctx context.Context, e Event) error {
+			return h(ctx, e.Value.(E))
+		},
+	})
+	return t
+}
+
+// As adapts a codec decoding messages of several types to a typed decoder for Topic
+func As[E any](decode func(msg kafka.Message) (any, error)) func(kafka.Message) (E, error) {
+	return func(msg kafka.Message) (E, error) {
+		var zero E
+		v, err := decode(msg)
+		if err != nil {
+			return zero, err
+		}
+		e, ok := v.(E)
+		if !ok {
+			return zero, fmt.Errorf("decoded %T, expected %T", v, zero)
+		}
+		return e, nil
+	}
+}
+
+// dispatch is one decoded message with the handlers left to run
+type dispatch struct {
+	msg      kafka.Message
+	value    any
+	handlers []namedHandler
+	mw       []Middleware
+	next     int
+}
+
+// route decodes msg, an unregistered topic is a decode error
+func (r *Router) route(msg kafka.Message) (*dispatch, error) {
+	rt, ok := r.routes[msg.Topic]
+	if !ok {
+		return nil, fmt.Errorf("unknown topic %s", msg.Topic)
+	}
+	v, err := rt.decode(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &dispatch{msg: msg, value: v, handlers: rt.handlers, mw: r.middleware}, nil
+}
+
+// run calls the handlers from the first one that hasn't succeeded yet
+func (d *dispatch) run(ctx context.Context) error {
+	for ; d.next < len(d.handlers); d.next++ {
+		h := d.handlers[d.next]
+		fn := h.fn
+		for i := len(d.mw) - 1; i >= 0; i-- {
+			fn = d.mw[i](fn)
+		}
+		if err := fn(ctx, Event{Msg: d.msg, Value: d.value, Handler: h.name}); err != nil {
+			return fmt.Errorf("%s: %w", h.name, err)
+		}
+	}
+	return nil
+}
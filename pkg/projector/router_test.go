@@ -0,0 +1,127 @@
+package projector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type testEvent struct {
+	ID string `json:"id"`
+}
+
+func decodeTestEvent(msg kafka.Message) (testEvent, error) {
+	var e testEvent
+	err := json.Unmarshal(msg.Value, &e)
+	return e, err
+}
+
+func TestRouter_RunsHandlersInOrder(t *testing.T) {
+	var calls []string
+	r := NewRouter()
+	Topic(r, "t", decodeTestEvent).
+		Handle("a", func(_ context.Context, e testEvent) error { calls = append(calls, "a:"+e.ID); return nil }).
+		Handle("b", func(_ context.Context, e testEvent) error { calls = append(calls, "b:"+e.ID); return nil })
+
+	d, err := r.route(kafka.Message{Topic: "t", Value: []byte(`{"id":"1"}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = d.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a:1", "b:1"}; !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRouter_RetryResumesAtFailedHandler(t *testing.T) {
+	var aCalls, bCalls int
+	r := NewRouter()
+	Topic(r, "t", decodeTestEvent).
+		Handle("a", func(context.Context, testEvent) error { aCalls++; return nil }).
+		Handle("b", func(context.Context, testEvent) error {
+			bCalls++
+			if bCalls == 1 {
+				return errors.New("transient")
+			}
+			return nil
+		})
+
+	d, err := r.route(kafka.Message{Topic: "t", Value: []byte(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = d.run(context.Background()); err == nil {
+		t.Fatal("first run must fail")
+	}
+	if err = d.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if aCalls != 1 || bCalls != 2 {
+		t.Fatalf("a=%d b=%d, want a=1 b=2", aCalls, bCalls)
+	}
+}
+
+func TestRouter_UnknownTopicAndDecodeErrors(t *testing.T) {
+	r := NewRouter()
+	Topic(r, "t", decodeTestEvent)
+
+	if _, err := r.route(kafka.Message{Topic: "other"}); err == nil {
+		t.Fatal("unknown topic must fail")
+	}
+	if _, err := r.route(kafka.Message{Topic: "t", Value: []byte(`{`)}); err == nil {
+		t.Fatal("invalid payload must fail")
+	}
+}
+
+func TestRouter_MiddlewareOrder(t *testing.T) {
+	var calls []string
+	mw := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(ctx context.Context, e Event) error {
+				calls = append(calls, name+">"+e.Handler)
+				return next(ctx, e)
+			}
+		}
+	}
+	r := NewRouter().Use(mw("outer"), mw("inner"))
+	Topic(r, "t", decodeTestEvent).
+		Handle("h", func(context.Context, testEvent) error { calls = append(calls, "h"); return nil })
+
+	d, _ := r.route(kafka.Message{Topic: "t", Value: []byte(`{}`)})
+	if err := d.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"outer>h", "inner>h", "h"}; !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestAs(t *testing.T) {
+	decode := As[testEvent](func(msg kafka.Message) (any, error) {
+		switch msg.Topic {
+		case "t":
+			return testEvent{ID: string(msg.Value)}, nil
+		case "wrong":
+			return 42, nil
+		default:
+			return nil, fmt.Errorf("unknown topic %s", msg.Topic)
+		}
+	})
+
+	if e, err := decode(kafka.Message{Topic: "t", Value: []byte("x")}); err != nil || e.ID != "x" {
+		t.Fatalf("got %+v, %v", e, err)
+	}
+	if _, err := decode(kafka.Message{Topic: "wrong"}); err == nil {
+		t.Fatal("type mismatch must fail")
+	}
+	if _, err := decode(kafka.Message{Topic: "nope"}); err == nil {
+		t.Fatal("codec error must propagate")
+	}
+}
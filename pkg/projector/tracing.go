@@ -0,0 +1,54 @@
+package projector
+
+import (
+	"context"
+	"kei-services/pkg/tracing"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("kei-services/pkg/projector")
+
+// startSpan continues the producer's trace from msg's headers with a consumer span covering every handler,
+// retry and dead-letter of msg
+func startSpan(ctx context.Context, msg kafka.Message) (context.Context, trace.Span) {
+	return tracer.Start(tracing.Extract(ctx, msg), msg.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", msg.Topic),
+			attribute.Int("messaging.kafka.partition", msg.Partition),
+			attribute.Int64("messaging.kafka.offset", msg.Offset),
+			attribute.String("messaging.kafka.message.key", string(msg.Key)),
+		))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Tracing gives every handler call a child span of the message's consumer span. Calls outside a traced
+// message, eg rebuild replays, aren't traced
+func Tracing() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, e Event) error {
+			if !trace.SpanContextFromContext(ctx).IsValid() {
+				return next(ctx, e)
+			}
+			ctx, span := tracer.Start(ctx, e.Handler, trace.WithAttributes(
+				attribute.String("messaging.destination.name", e.Msg.Topic),
+				attribute.String("projector.handler", e.Handler),
+			))
+			err := next(ctx, e)
+			endSpan(span, err)
+			return err
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package projector
+
+import (
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type topicPartition struct {
+	topic     string
+	partition int
+}
+
+// partitionMarks holds the offsets of one partition in fetch order, done ones are popped from the front
+type partitionMarks struct {
+	pending []int64
+	done    map[int64]bool
+}
+
+// watermarks tracks in-flight offsets per partition. With parallel workers messages finish out of order,
+// an offset is only safe to commit once every earlier offset of its partition is done
+type watermarks struct {
+	mu         sync.Mutex
+	partitions map[topicPartition]*partitionMarks
+}
+
+func newWatermarks() *watermarks {
+	return &watermarks{partitions: make(map[topicPartition]*partitionMarks)}
+}
+
+// track registers a fetched message, must be called in fetch order
+func (w *watermarks) track(msg kafka.Message) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	tp := topicPartition{topic: msg.Topic, partition: msg.Partition}
+	pm := w.partitions[tp]
+	// first message, or the reader rewound to the committed offset after a rebalance
+	if pm == nil || (len(pm.pending) > 0 && msg.Offset <= pm.pending[len(pm.pending)-1]) {
+		pm = &partitionMarks{done: make(map[int64]bool)}
+		w.partitions[tp] = pm
+	}
+	pm.pending = append(pm.pending, msg.Offset)
+}
+
+// done marks msg as handled and returns the highest offset of its partition that can now be committed,
+// ok is false while an earlier offset is still in flight
+func (w *watermarks) done(msg kafka.Message) (commit kafka.Message, ok bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	pm := w.partitions[topicPartition{topic: msg.Topic, partition: msg.Partition}]
+	// untracked, or left over from before a rewind
+	if pm == nil || len(pm.pending) == 0 ||
+		msg.Offset < pm.pending[0] || msg.Offset > pm.pending[len(pm.pending)-1] {
+		return kafka.Message{}, false
+	}
+	pm.done[msg.Offset] = true
+
+	for len(pm.pending) > 0 && pm.done[pm.pending[0]] {
+		commit = kafka.Message{Topic: msg.Topic, Partition: msg.Partition, Offset: pm.pending[0]}
+		delete(pm.done, pm.pending[0])
+		pm.pending = pm.pending[1:]
+		ok = true
+	}
+	return commit, ok
+}
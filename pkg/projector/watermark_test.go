@@ -0,0 +1,78 @@
+package projector
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func msgAt(partition int, offset int64) kafka.Message {
+	return kafka.Message{Topic: "bids.placed", Partition: partition, Offset: offset}
+}
+
+func TestWatermarks_OutOfOrderCompletion(t *testing.T) {
+	w := newWatermarks()
+	for _, o := range []int64{10, 11, 12} {
+		w.track(msgAt(0, o))
+	}
+
+	if _, ok := w.done(msgAt(0, 12)); ok {
+		t.Fatal("12 must wait for 10 and 11")
+	}
+	if _, ok := w.done(msgAt(0, 11)); ok {
+		t.Fatal("11 must wait for 10")
+	}
+
+	commit, ok := w.done(msgAt(0, 10))
+	if !ok || commit.Offset != 12 {
+		t.Fatalf("10 unblocks everything up to 12, got %d ok=%v", commit.Offset, ok)
+	}
+}
+
+func TestWatermarks_InOrderCompletion(t *testing.T) {
+	w := newWatermarks()
+	w.track(msgAt(0, 1))
+	w.track(msgAt(0, 2))
+
+	for _, o := range []int64{1, 2} {
+		commit, ok := w.done(msgAt(0, o))
+		if !ok || commit.Offset != o {
+			t.Fatalf("done(%d): got %d ok=%v", o, commit.Offset, ok)
+		}
+	}
+}
+
+func TestWatermarks_PartitionsAreIndependent(t *testing.T) {
+	w := newWatermarks()
+	w.track(msgAt(0, 5))
+	w.track(msgAt(1, 7))
+
+	commit, ok := w.done(msgAt(1, 7))
+	if !ok || commit.Partition != 1 || commit.Offset != 7 {
+		t.Fatalf("partition 1 is not blocked by partition 0, got %d/%d ok=%v", commit.Partition, commit.Offset, ok)
+	}
+}
+
+func TestWatermarks_RewindResetsPartition(t *testing.T) {
+	w := newWatermarks()
+	w.track(msgAt(0, 20))
+	w.track(msgAt(0, 21))
+
+	// rebalance, the reader starts again from the committed offset
+	w.track(msgAt(0, 20))
+
+	commit, ok := w.done(msgAt(0, 20))
+	if !ok || commit.Offset != 20 {
+		t.Fatalf("got %d ok=%v, want 20", commit.Offset, ok)
+	}
+	if _, ok = w.done(msgAt(0, 21)); ok {
+		t.Fatal("21 was dropped with the old generation and is not tracked")
+	}
+}
+
+func TestWatermarks_UnknownPartition(t *testing.T) {
+	w := newWatermarks()
+	if _, ok := w.done(msgAt(3, 1)); ok {
+		t.Fatal("untracked partition must not commit")
+	}
+}
@@ -0,0 +1,205 @@
+// Package schema decodes versioned event payloads. Producers tag messages with content-type, schema and
+// schema-version headers, consumers register the current version of each schema and upcasters lifting older
+// versions to it, so the payload can change without breaking consumers that haven't been redeployed yet.
+// JSON and protobuf payloads are both accepted, so producers and consumers can switch encodings independently
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	HeaderContentType   = "content-type"
+	HeaderSchema        = "schema"
+	HeaderSchemaVersion = "schema-version"
+)
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+var (
+	ErrUnknownSchema      = errors.New("unknown_schema")
+	ErrUnknownVersion     = errors.New("unknown_schema_version")
+	ErrUnknownContentType = errors.New("unknown_content_type")
+)
+
+// Upcaster lifts a version N document to version N+1
+type Upcaster func(doc map[string]any) (map[string]any, error)
+
+// Registry holds the schemas a consumer understands
+type Registry struct {
+	schemas map[string]*entry
+}
+
+type entry struct {
+	current     int
+	decode      func(payload []byte) (any, error)
+	decodeProto func(payload []byte) (any, error) // nil when only JSON is accepted
+	upcasters   map[int]Upcaster                  // by the version they lift from
+}
+
+func NewRegistry() *Registry {
+	return &Registry{schemas: make(map[string]*entry)}
+}
+
+// Register adds schema name decoding into E at version current. upcasters maps a version to the upcaster
+// lifting it to the next one, older versions without a complete chain up to current are rejected.
+// Registering a name twice panics
+func Register[E any](r *Registry, name string, current int, upcasters map[int]Upcaster) {
+	if _, ok := r.schemas[name]; ok {
+		panic(fmt.Sprintf("schema: %q registered twice", name))
+	}
+	if current < 1 {
+		panic(fmt.Sprintf("schema: %q current version must be >= 1", name))
+	}
+	r.schemas[name] = &entry{
+		current: current,
+		decode: func(payload []byte) (any, error) {
+			var e E
+			if err := json.Unmarshal(payload, &e); err != nil {
+				return nil, err
+			}
+			return e, nil
+		},
+		upcasters: upcasters,
+	}
+}
+
+// RegisterProto also accepts protobuf payloads of name, decoded into M and converted to the registered type.
+// Protobuf payloads are only accepted at the current version, field numbers keep additive changes compatible
+// and breaking changes get a new message. Panics when name isn't registered
+func RegisterProto[E any, M proto.Message](r *Registry, name string, newMsg func() M, convert func(M) E) {
+	e, ok := r.schemas[name]
+	if !ok {
+		panic(fmt.Sprintf("schema: register %q before its protobuf decoder", name))
+	}
+	e.decodeProto = func(payload []byte) (any, error) {
+		m := newMsg()
+		if err := proto.Unmarshal(payload, m); err != nil {
+			return nil, err
+		}
+		return convert(m), nil
+	}
+}
+
+// Current returns the current version of name, 0 when not registered
+func (r *Registry) Current(name string) int {
+	if e, ok := r.schemas[name]; ok {
+		return e.current
+	}
+	return 0
+}
+
+// Decode decodes a JSON payload of schema name at version into the registered type, upcasting older versions
+func (r *Registry) Decode(name string, version int, payload []byte) (any, error) {
+	return r.DecodeAs(ContentTypeJSON, name, version, payload)
+}
+
+// DecodeAs decodes payload encoded as contentType, an empty content type is JSON
+func (r *Registry) DecodeAs(contentType, name string, version int, payload []byte) (any, error) {
+	e, ok := r.schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSchema, name)
+	}
+	if version < 1 || version > e.current {
+		return nil, fmt.Errorf("%w: %s v%d, current is v%d", ErrUnknownVersion, name, version, e.current)
+	}
+
+	switch mediaType(contentType) {
+	case "", ContentTypeJSON:
+	case ContentTypeProtobuf:
+		if e.decodeProto == nil {
+			return nil, fmt.Errorf("%w: %s not accepted for %s", ErrUnknownContentType, contentType, name)
+		}
+		if version != e.current {
+			return nil, fmt.Errorf("%w: %s v%d as protobuf, current is v%d", ErrUnknownVersion, name, version, e.current)
+		}
+		return e.decodeProto(payload)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownContentType, contentType)
+	}
+
+	if version == e.current {
+		return e.decode(payload)
+	}
+
+	doc, err := decodeDoc(payload)
+	if err != nil {
+		return nil, err
+	}
+	for v := version; v < e.current; v++ {
+		up, ok := e.upcasters[v]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s v%d, no upcaster to v%d", ErrUnknownVersion, name, v, v+1)
+		}
+		if doc, err = up(doc); err != nil {
+			return nil, fmt.Errorf("upcast %s v%d to v%d: %w", name, v, v+1, err)
+		}
+	}
+
+	upcast, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return e.decode(upcast)
+}
+
+// DecodeMessage decodes msg by its content-type and schema headers. Messages from producers that predate the
+// headers are read as JSON, schema = topic, version 1
+func (r *Registry) DecodeMessage(msg kafka.Message) (any, error) {
+	name := Header(msg, HeaderSchema)
+	if name == "" {
+		if _, ok := r.schemas[msg.Topic]; !ok {
+			return nil, fmt.Errorf("%w: unknown topic %s", ErrUnknownSchema, msg.Topic)
+		}
+		name = msg.Topic
+	}
+
+	version := 1
+	if s := Header(msg, HeaderSchemaVersion); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s version %q", ErrUnknownVersion, name, s)
+		}
+		version = v
+	}
+
+	return r.DecodeAs(Header(msg, HeaderContentType), name, version, msg.Value)
+}
+
+// mediaType drops parameters, eg "application/json; charset=utf-8"
+func mediaType(contentType string) string {
+	mt, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mt))
+}
+
+// Header returns the value of the last header named key, "" when missing
+func Header(msg kafka.Message, key string) string {
+	for i := len(msg.Headers) - 1; i >= 0; i-- {
+		if msg.Headers[i].Key == key {
+			return string(msg.Headers[i].Value)
+		}
+	}
+	return ""
+}
+
+// decodeDoc keeps numbers as json.Number so upcasting doesn't round large integers
+func decodeDoc(payload []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
@@ -0,0 +1,156 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// price was called amount in v1 and was a string until v3
+type priced struct {
+	ID    string  `json:"id"`
+	Price float64 `json:"price"`
+}
+
+func newTestRegistry() *Registry {
+	r := NewRegistry()
+	Register[priced](r, "priced", 3, map[int]Upcaster{
+		1: func(doc map[string]any) (map[string]any, error) {
+			doc["price"] = doc["amount"]
+			delete(doc, "amount")
+			return doc, nil
+		},
+		2: func(doc map[string]any) (map[string]any, error) {
+			s, ok := doc["price"].(string)
+			if !ok {
+				return nil, errors.New("price is not a string")
+			}
+			doc["price"] = json.Number(s)
+			return doc, nil
+		},
+	})
+	return r
+}
+
+func msg(topic string, headers ...string) kafka.Message {
+	m := kafka.Message{Topic: topic}
+	for i := 0; i+1 < len(headers); i += 2 {
+		m.Headers = append(m.Headers, kafka.Header{Key: headers[i], Value: []byte(headers[i+1])})
+	}
+	return m
+}
+
+func TestDecode_Upcasts(t *testing.T) {
+	r := newTestRegistry()
+
+	cases := []struct {
+		version int
+		payload string
+	}{
+		{1, `{"id":"a","amount":"12.5"}`},
+		{2, `{"id":"a","price":"12.5"}`},
+		{3, `{"id":"a","price":12.5}`},
+	}
+	for _, c := range cases {
+		v, err := r.Decode("priced", c.version, []byte(c.payload))
+		if err != nil {
+			t.Fatalf("v%d: %v", c.version, err)
+		}
+		if got := v.(priced); got != (priced{ID: "a", Price: 12.5}) {
+			t.Fatalf("v%d: got %+v", c.version, got)
+		}
+	}
+}
+
+func TestDecode_UnknownVersions(t *testing.T) {
+	r := newTestRegistry()
+
+	for _, version := range []int{0, 4} {
+		if _, err := r.Decode("priced", version, []byte(`{}`)); !errors.Is(err, ErrUnknownVersion) {
+			t.Fatalf("v%d: got %v, want ErrUnknownVersion", version, err)
+		}
+	}
+	if _, err := r.Decode("other", 1, []byte(`{}`)); !errors.Is(err, ErrUnknownSchema) {
+		t.Fatalf("got %v, want ErrUnknownSchema", err)
+	}
+}
+
+func TestDecode_MissingUpcaster(t *testing.T) {
+	r := NewRegistry()
+	Register[priced](r, "priced", 2, nil)
+
+	if _, err := r.Decode("priced", 1, []byte(`{}`)); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("got %v, want ErrUnknownVersion", err)
+	}
+}
+
+func TestDecodeMessage_Headers(t *testing.T) {
+	r := NewRegistry()
+	Register[priced](r, "priced", 1, nil)
+
+	m := msg("prices", HeaderSchema, "priced", HeaderSchemaVersion, "1")
+	m.Value = []byte(`{"id":"a","price":1}`)
+	if _, err := r.DecodeMessage(m); err != nil {
+		t.Fatal(err)
+	}
+
+	// no headers: schema is the topic, version 1
+	m = msg("priced")
+	m.Value = []byte(`{"id":"a","price":1}`)
+	if _, err := r.DecodeMessage(m); err != nil {
+		t.Fatal(err)
+	}
+
+	m = msg("priced", HeaderSchemaVersion, "v2")
+	if _, err := r.DecodeMessage(m); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("got %v, want ErrUnknownVersion", err)
+	}
+
+	m = msg("prices")
+	if _, err := r.DecodeMessage(m); !errors.Is(err, ErrUnknownSchema) {
+		t.Fatalf("got %v, want ErrUnknownSchema", err)
+	}
+}
+
+func TestDecodeMessage_ContentType(t *testing.T) {
+	r := NewRegistry()
+	Register[priced](r, "priced", 1, nil)
+	RegisterProto(r, "priced", func() *wrapperspb.StringValue { return &wrapperspb.StringValue{} },
+		func(m *wrapperspb.StringValue) priced { return priced{ID: m.GetValue()} })
+
+	payload, err := proto.Marshal(wrapperspb.String("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := msg("priced", HeaderContentType, ContentTypeProtobuf, HeaderSchemaVersion, "1")
+	m.Value = payload
+	v, err := r.DecodeMessage(m)
+	if err != nil || v.(priced).ID != "a" {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+
+	m = msg("priced", HeaderContentType, "application/json; charset=utf-8")
+	m.Value = []byte(`{"id":"b"}`)
+	if v, err = r.DecodeMessage(m); err != nil || v.(priced).ID != "b" {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+
+	m = msg("priced", HeaderContentType, "application/avro")
+	if _, err = r.DecodeMessage(m); !errors.Is(err, ErrUnknownContentType) {
+		t.Fatalf("got %v, want ErrUnknownContentType", err)
+	}
+}
+
+func TestDecode_ProtobufNotRegistered(t *testing.T) {
+	r := NewRegistry()
+	Register[priced](r, "priced", 1, nil)
+
+	if _, err := r.DecodeAs(ContentTypeProtobuf, "priced", 1, nil); !errors.Is(err, ErrUnknownContentType) {
+		t.Fatalf("got %v, want ErrUnknownContentType", err)
+	}
+}
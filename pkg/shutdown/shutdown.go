@@ -0,0 +1,100 @@
+// Package shutdown stops a service in phases: stop accepting work, drain what is in flight, flush and close
+// the Kafka clients, then close the stores the earlier phases still needed
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultTimeout stays below kubernetes' default 30s grace period so the last phases run before SIGKILL
+const DefaultTimeout = 25 * time.Second
+
+// Coordinator runs the registered phases once, last registered first like defers: a client registered right
+// after it is created is closed after everything created later, which may still use it, has stopped.
+// Every phase shares one deadline, a phase failing or running out of time is logged and the next phases
+// still run, closing clients doesn't need the time
+type Coordinator struct {
+	timeout time.Duration
+	log     *zap.Logger
+
+	mu     sync.Mutex
+	phases []phase
+	once   sync.Once
+	err    error
+}
+
+type phase struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+func New(timeout time.Duration, log *zap.Logger) *Coordinator {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Coordinator{timeout: timeout, log: log}
+}
+
+// Add registers a phase, run before the phases registered earlier
+func (c *Coordinator) Add(name string, run func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.phases = append(c.phases, phase{name: name, run: run})
+}
+
+// AddCloser registers closing cl as a phase
+func (c *Coordinator) AddCloser(name string, cl io.Closer) {
+	c.Add(name, func(context.Context) error { return cl.Close() })
+}
+
+// Shutdown runs the phases within the timeout, later calls wait for the first and return its result
+func (c *Coordinator) Shutdown() error {
+	c.once.Do(func() { c.err = c.run() })
+	return c.err
+}
+
+func (c *Coordinator) run() error {
+	c.mu.Lock()
+	phases := make([]phase, 0, len(c.phases))
+	for i := len(c.phases) - 1; i >= 0; i-- {
+		phases = append(phases, c.phases[i])
+	}
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	c.log.Info("shutdown starting", zap.Int("phases", len(phases)), zap.Duration("timeout", c.timeout))
+
+	var errs []error
+	for i, p := range phases {
+		log := c.log.With(zap.String("phase", p.name), zap.Int("step", i+1))
+		log.Info("shutdown phase starting")
+
+		phaseStart := time.Now()
+		err := p.run(ctx)
+		took := time.Since(phaseStart)
+		if err != nil {
+			log.Error("shutdown phase failed", zap.Duration("took", took), zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+			continue
+		}
+		log.Info("shutdown phase done", zap.Duration("took", took))
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		c.log.Warn("shutdown finished with errors", zap.Duration("took", time.Since(start)), zap.Error(err))
+	} else {
+		c.log.Info("shutdown finished", zap.Duration("took", time.Since(start)))
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestShutdown_RunsPhasesInReverse(t *testing.T) {
+	c := New(time.Second, zap.NewNop())
+	var order []string
+	c.Add("redis", func(context.Context) error {
+		order = append(order, "redis")
+		return nil
+	})
+	c.AddCloser("kafka", closerFunc(func() error {
+		order = append(order, "kafka")
+		return nil
+	}))
+	c.Add("http", func(context.Context) error {
+		order = append(order, "http")
+		return errors.New("boom")
+	})
+
+	err := c.Shutdown()
+	if want := []string{"http", "kafka", "redis"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("phases ran %v, want %v", order, want)
+	}
+	if err == nil || err.Error() != "http: boom" {
+		t.Errorf("got error %v, want the failed phase's", err)
+	}
+
+	if again := c.Shutdown(); again != err || len(order) != 3 {
+		t.Errorf("second Shutdown ran phases again or returned %v", again)
+	}
+}
+
+func TestShutdown_SharedDeadline(t *testing.T) {
+	c := New(20*time.Millisecond, zap.NewNop())
+	closed := false
+	c.Add("close", func(ctx context.Context) error {
+		if ctx.Err() == nil {
+			t.Error("expected the deadline to have passed")
+		}
+		closed = true
+		return nil
+	})
+	c.Add("drain", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if err := c.Shutdown(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want deadline exceeded", err)
+	}
+	if !closed {
+		t.Error("phases after the deadline must still run")
+	}
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
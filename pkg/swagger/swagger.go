@@ -1,6 +1,8 @@
 package server
 
 import (
+	"kei-services/pkg/config"
+
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/gin-gonic/gin"
 	swgui "github.com/swaggest/swgui/v4"
@@ -14,6 +16,13 @@ type Config struct {
 	OpenApiName string `json:"OpenApiName"`
 }
 
+func (c *Config) Validate() error {
+	if c.IsEnabled && c.OpenApiName == "" {
+		return config.Problem("OpenApiName", "is required when enabled")
+	}
+	return nil
+}
+
 func Serve(getSwagger func() (*openapi3.T, error), r *gin.Engine, cfg *Config, log *zap.Logger) {
 	if cfg.IsEnabled {
 		log.Info("Serving Swagger UI")
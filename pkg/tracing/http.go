@@ -0,0 +1,12 @@
+package tracing
+
+import "net/http"
+
+// TraceRequest filters out scrapes and probes, they would drown the request traces
+func TraceRequest(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/metrics", "/healthz", "/livez", "/readyz", "/startupz":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// HeaderCarrier reads and writes trace context (traceparent, tracestate, baggage) in kafka message headers
+type HeaderCarrier struct {
+	Headers *[]kafka.Header
+}
+
+var _ propagation.TextMapCarrier = HeaderCarrier{}
+
+// Get returns the value of the last header named key
+func (c HeaderCarrier) Get(key string) string {
+	h := *c.Headers
+	for i := len(h) - 1; i >= 0; i-- {
+		if h[i].Key == key {
+			return string(h[i].Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces every header named key
+func (c HeaderCarrier) Set(key, value string) {
+	h := (*c.Headers)[:0:0]
+	for _, kv := range *c.Headers {
+		if kv.Key != key {
+			h = append(h, kv)
+		}
+	}
+	*c.Headers = append(h, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.Headers))
+	for _, h := range *c.Headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
+
+// Inject writes the trace context of ctx into msg's headers
+func Inject(ctx context.Context, msg *kafka.Message) {
+	otel.GetTextMapPropagator().Inject(ctx, HeaderCarrier{Headers: &msg.Headers})
+}
+
+// Extract returns ctx carrying the remote trace context found in msg's headers, ctx when there is none
+func Extract(ctx context.Context, msg kafka.Message) context.Context {
+	headers := msg.Headers
+	return otel.GetTextMapPropagator().Extract(ctx, HeaderCarrier{Headers: &headers})
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DBTX is the database/sql surface sqlc generated queries run on, *sql.DB and *sql.Tx implement it
+type DBTX interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+	PrepareContext(context.Context, string) (*sql.Stmt, error)
+	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}
+
+// SQL wraps db so every statement gets a client span, system is the db.system attribute, eg postgresql
+func SQL(db DBTX, system string) DBTX {
+	return tracedDB{db: db, system: system, tracer: Tracer("kei-services/pkg/tracing/sql")}
+}
+
+type tracedDB struct {
+	db     DBTX
+	system string
+	tracer trace.Tracer
+}
+
+func (t tracedDB) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, queryName(query),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", t.system),
+			attribute.String("db.statement", query),
+		))
+}
+
+func (t tracedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := t.start(ctx, query)
+	res, err := t.db.ExecContext(ctx, query, args...)
+	end(span, err)
+	return res, err
+}
+
+func (t tracedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ctx, span := t.start(ctx, query)
+	stmt, err := t.db.PrepareContext(ctx, query)
+	end(span, err)
+	return stmt, err
+}
+
+func (t tracedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := t.start(ctx, query)
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	end(span, err)
+	return rows, err
+}
+
+func (t tracedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := t.start(ctx, query)
+	row := t.db.QueryRowContext(ctx, query, args...)
+	end(span, row.Err()) // query errors only, no rows surfaces at Scan
+	return row
+}
+
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// queryName names the span after the sqlc query, "-- name: InsertBid :one" gives "InsertBid"
+func queryName(query string) string {
+	if rest, ok := strings.CutPrefix(strings.TrimSpace(query), "-- name: "); ok {
+		if name, _, ok := strings.Cut(rest, " "); ok && name != "" {
+			return name
+		}
+	}
+	return "db.query"
+}
@@ -0,0 +1,161 @@
+// Package tracing sets up OpenTelemetry tracing and propagates W3C trace context through Kafka headers and
+// database calls, so one bid can be followed from the HTTP request to the projections
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"kei-services/pkg/config"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+type Config struct {
+	IsEnabled   bool
+	Exporter    string  // otlp (default), stdout or file
+	Endpoint    string  // OTLP/HTTP collector host:port, default: OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318
+	Insecure    bool    // plain http to the collector
+	FilePath    string  // file exporter, default: ./log/traces.json
+	SampleRatio float64 // of root spans, spans with a sampled parent are always kept, default: 1
+}
+
+// Init installs the global tracer provider and the W3C trace context propagator. Context is propagated even
+// when tracing is disabled, so services in the middle of a trace don't break it. The returned func flushes
+// pending spans and must be called on shutdown
+func Init(ctx context.Context, cfg *Config, service string, app *config.App, log *zap.Logger) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	if cfg == nil || !cfg.IsEnabled {
+		log.Info("tracing is disabled")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exp, closeOut, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("service.name", service)}
+	if app != nil {
+		attrs = append(attrs,
+			attribute.String("service.version", app.Version),
+			attribute.String("deployment.environment", string(app.Environment)))
+	}
+
+	ratio := cfg.SampleRatio
+	if ratio <= 0 {
+		ratio = 1
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(resource.NewSchemaless(attrs...)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Warn("otel", zap.Error(err))
+	}))
+
+	log.Info("tracing enabled", zap.String("exporter", exporterName(cfg)), zap.Float64("sampleRatio", ratio))
+
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if closeOut != nil {
+			_ = closeOut.Close()
+		}
+		return err
+	}, nil
+}
+
+func exporterName(cfg *Config) string {
+	if cfg.Exporter == "" {
+		return ExporterOTLP
+	}
+	return cfg.Exporter
+}
+
+func newExporter(ctx context.Context, cfg *Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch exporterName(cfg) {
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exp, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("otlp exporter: %w", err)
+		}
+		return exp, nil, nil
+	case ExporterStdout:
+		exp, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		return exp, nil, err
+	case ExporterFile:
+		path := cfg.FilePath
+		if path == "" {
+			path = "./log/traces.json"
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, nil, fmt.Errorf("trace file dir: %w", err)
+		}
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("open trace file: %w", err)
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, nil, err
+		}
+		return exp, f, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown trace exporter %q, expected otlp, stdout or file", cfg.Exporter)
+	}
+}
+
+// Tracer returns a tracer of the global provider, name is the instrumented package
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+func (c *Config) Validate() error {
+	if !c.IsEnabled {
+		return nil
+	}
+	var errs []error
+	switch exporterName(c) {
+	case ExporterOTLP, ExporterStdout:
+	case ExporterFile:
+		if c.FilePath == "" {
+			errs = append(errs, config.Problem("FilePath", "is required for the file exporter"))
+		}
+	default:
+		errs = append(errs, config.Problem("Exporter", "%q, expected otlp, stdout or file", c.Exporter))
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		errs = append(errs, config.Problem("SampleRatio", "%v, expected 0-1", c.SampleRatio))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectExtract_RoundTrip(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	msg := kafka.Message{Headers: []kafka.Header{{Key: "schema", Value: []byte("bids.placed")}}}
+	Inject(ctx, &msg)
+	Inject(ctx, &msg) // re-publishing replaces the header instead of adding another
+
+	if n := len(msg.Headers); n != 2 {
+		t.Fatalf("headers = %v, want schema and traceparent", msg.Headers)
+	}
+
+	got := trace.SpanContextFromContext(Extract(context.Background(), msg))
+	if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() || !got.IsRemote() {
+		t.Fatalf("got %+v, want remote %+v", got, sc)
+	}
+}
+
+func TestExtract_NoHeaders(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	if sc := trace.SpanContextFromContext(Extract(context.Background(), kafka.Message{})); sc.IsValid() {
+		t.Fatalf("got %+v, want no span context", sc)
+	}
+}
+
+func TestQueryName(t *testing.T) {
+	cases := map[string]string{
+		"-- name: InsertBid :one\nINSERT INTO bids": "InsertBid",
+		"\n-- name: LatestForUpdate :one\nSELECT":   "LatestForUpdate",
+		"SELECT 1": "db.query",
+	}
+	for query, want := range cases {
+		if got := queryName(query); got != want {
+			t.Fatalf("queryName(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+#!/bin/bash
+
+set -e
+
+go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.36.8
+
+echo "Generating protobuf code..."
+protoc -I ../proto --go_out=.. --go_opt=module=kei-services ../proto/events/v1/*.proto
+echo "Protobuf code gen complete"
@@ -2,24 +2,41 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"kei-services/pkg/config"
+	"kei-services/pkg/health"
 	kafkaInfra "kei-services/pkg/infra/kafka"
 	redisInfra "kei-services/pkg/infra/redis"
 	"kei-services/pkg/logger"
+	"kei-services/pkg/metrics"
+	"kei-services/pkg/projector"
+	"kei-services/pkg/shutdown"
+	"kei-services/pkg/tracing"
 	"kei-services/services/auction-projector/internal/cfg"
 	"kei-services/services/auction-projector/internal/events"
 	redisProjection "kei-services/services/auction-projector/internal/projections/redis"
-	"kei-services/services/auction-projector/internal/projector"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
+	goRedis "github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
+var (
+	dlqCmd = flag.String("dlq", "",
+		"dead-letter command, runs and exits: list prints the dead-letter topic as JSON lines, redrive re-runs it through the router")
+	rebuildCmd = flag.Bool("rebuild", false,
+		"rebuild auction metadata under a new key prefix from the earliest offset, switch readers over once caught up and exit")
+	checkConfig = flag.Bool("check-config", false, "validate the config, print every problem found and exit")
+)
+
 func main() {
 	flag.Parse() // parse -config flag
 	_ = godotenv.Load(".env")
@@ -27,6 +44,7 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	config.MustValidate(config.Path(), cfg, *checkConfig)
 
 	log := logger.Init(cfg.Logger, cfg.App)
 	if log == nil {
@@ -36,28 +54,111 @@ func main() {
 
 	log.Info("Starting App", zap.String("version", cfg.App.Version))
 
+	// phases run in reverse, clients registered as they are created close after what uses them stopped
+	sd := shutdown.New(shutdown.DefaultTimeout, log)
+	defer func() { _ = sd.Shutdown() }()
+
+	// tracing, flushed on exit
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, "auction-projector", cfg.App, log)
+	if err != nil {
+		log.Fatal("init tracing", zap.Error(err))
+	}
+	sd.Add("tracing", shutdownTracing)
+
 	ctx, cancel := signal.NotifyContext(context.Background(),
 		os.Interrupt, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
 	defer cancel()
 
+	switch *dlqCmd {
+	case "", "list", "redrive":
+	default:
+		log.Fatal("unknown dlq command, expected list or redrive", zap.String("dlq", *dlqCmd))
+	}
+
+	if *dlqCmd == "list" {
+		if cfg.DeadLetter == nil {
+			log.Fatal("dlq list: no dead-letter topic configured")
+		}
+		n, err := projector.ListDeadLetters(ctx, cfg.KafkaReader.Brokers, cfg.KafkaReader.Security,
+			cfg.DeadLetter.Topic, os.Stdout)
+		if err != nil {
+			log.Fatal("dlq list", zap.Error(err))
+		}
+		log.Info("dlq list done", zap.Int("records", n))
+		return
+	}
+
 	// redis
 	redisClient, err := redisInfra.Client(cfg.Redis, log)
 	if err != nil {
 		log.Fatal("connect to redis", zap.Error(err))
 	}
-	defer func() { _ = redisClient.Close() }()
+	sd.AddCloser("redis", redisClient)
 
 	// setup kafka reader
 	// ensure topics
-	if err = projector.EnsureTopics(ctx, cfg.KafkaReader.Brokers, cfg.KafkaReader.GroupTopics, 1, 1); err != nil {
-		log.Warn("ensure topics", zap.Strings("topics", cfg.KafkaReader.GroupTopics), zap.Error(err))
+	topics := cfg.KafkaReader.GroupTopics
+	if cfg.DeadLetter != nil {
+		topics = append(append([]string(nil), topics...), cfg.DeadLetter.Topic)
 	}
+	if err = projector.EnsureTopics(ctx, cfg.KafkaReader.Brokers, cfg.KafkaReader.Security, topics, 1, 1); err != nil {
+		log.Warn("ensure topics", zap.Strings("topics", topics), zap.Error(err))
+	}
+
+	// dead-letter topic, failed messages are dropped without one
+	var dlq *kafkaInfra.DeadLetter
+	if cfg.DeadLetter != nil {
+		if dlq, err = kafkaInfra.NewDeadLetter(cfg.KafkaReader.Brokers, cfg.KafkaReader.Security, cfg.DeadLetter, log); err != nil {
+			log.Fatal("dead-letter writer", zap.Error(err))
+		}
+		sd.AddCloser("dead-letter writer", dlq)
+	}
+
+	// rebuild runs next to the live projectors and never joins their group
+	if *rebuildCmd {
+		prefix, err := rebuild(ctx, redisClient, cfg, dlq, log)
+		if err != nil {
+			log.Fatal("rebuild", zap.Error(err))
+		}
+		log.Info("rebuild switched auction metadata, keys under the previous prefix expire with their auctions",
+			zap.String("prefix", prefix))
+		return
+	}
+
+	// wire projector, writes follow the key prefix a rebuild switched to
+	activePrefix := redisInfra.NewActivePrefix(redisClient, redisProjection.ActivePrefixKey,
+		redisProjection.DefaultKeyPrefix, log)
+	if err = activePrefix.Refresh(ctx); err != nil {
+		log.Warn("resolve active auction metadata prefix", zap.String("prefix", activePrefix.Prefix()), zap.Error(err))
+	}
+	go activePrefix.Watch(ctx, redisInfra.DefaultRefreshInterval)
+
+	met := metrics.New(metrics.Options{
+		Namespace:   "auctionprojector",
+		ConstLabels: prometheus.Labels{"service": "auctionprojector", "env": string(cfg.App.Environment)},
+	})
+	projectorMx := projector.NewMetrics(met)
+
+	router := newRouter(redisClient, activePrefix, projectorMx, log)
 
+	// redrive runs before the group reader exists, joining the live group would rebalance the running projectors
+	if *dlqCmd == "redrive" {
+		redriven, failed, err := projector.New(nil, router, projectorOptions(cfg, dlq), log).
+			Redrive(ctx, cfg.KafkaReader.Brokers, cfg.KafkaReader.Security, cfg.DeadLetter, 10*time.Second)
+		if err != nil {
+			log.Fatal("dlq redrive", zap.Error(err))
+		}
+		log.Info("dlq redrive done", zap.Int("redriven", redriven), zap.Int("failed", failed))
+		return
+	}
+
+	cfg.KafkaReader.Metrics = met
+	cfg.KafkaReader.Log = log
 	auctionReader, err := kafkaInfra.NewReader(cfg.KafkaReader)
 	if err != nil {
 		log.Fatal("kafka reader", zap.Error(err))
 	}
-	defer auctionReader.Close()
+	sd.AddCloser("kafka reader", auctionReader) // closing commits the final offsets
 
 	log.Info("kafka reader configured",
 		zap.Strings("brokers", cfg.KafkaReader.Brokers),
@@ -67,25 +168,110 @@ func main() {
 		zap.String("startOffset", string(cfg.KafkaReader.Offset)),
 	)
 
-	// wire projector
-	cache := redisProjection.NewAuctionMetadataProjection(redisClient, log)
-	redisProjection := redisProjection.NewProjection(cache, log, 15*time.Minute)
+	opts := projectorOptions(cfg, dlq)
+	opts.Metrics = projectorMx
+	p := projector.New(auctionReader, router, opts, log)
 
-	router := &projector.Router{
-		Codec:    &events.Codec{},
-		Handlers: redisProjection,
+	// probes: dependencies plus the reader's partition assignment, see projector.OpsHandler
+	transport, err := cfg.KafkaReader.Transport()
+	if err != nil {
+		log.Fatal("kafka transport", zap.Error(err))
 	}
+	hc := health.New(health.NewBuildInfo("auction-projector", cfg.App))
+	hc.Add(health.Redis(redisClient), health.Kafka(cfg.KafkaReader.Brokers, transport))
+	hc.MarkStarted()
 
-	p := projector.New(auctionReader, router, log)
-
-	// run projector
+	// ops server: prometheus scrape, liveness and readiness probes, up until the projector drained
+	opsCtx, stopOps := context.WithCancel(context.Background())
+	sd.Add("ops server", func(context.Context) error {
+		stopOps()
+		return nil
+	})
 	go func() {
-		if err = p.Run(ctx); err != nil {
-			log.Error("projector stopped", zap.Error(err))
+		addr := opsAddr(cfg.Network)
+		log.Info("ops server listening", zap.String("address", addr))
+		err := metrics.Serve(opsCtx, addr, projector.OpsHandler(p, met.Handler, hc))
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("ops server", zap.Error(err))
 		}
 	}()
 
-	// block until signal
-	<-ctx.Done()
-	log.Info("shutdown signal received")
+	// run projector, a signal doesn't cancel it but starts the shutdown, which drains it first
+	sd.Add("projector", p.Shutdown)
+	runErr := make(chan error, 1)
+	go func() { runErr <- p.Run(context.Background()) }()
+
+	// block until signal, or the projector failing on its own, which must restart the pod instead of
+	// leaving it up and consuming nothing
+	select {
+	case <-ctx.Done():
+		log.Info("shutdown signal received")
+		_ = sd.Shutdown()
+	case err = <-runErr:
+		log.Error("projector stopped, shutting down", zap.Error(err))
+		_ = sd.Shutdown()
+		log.Fatal("projector failed", zap.Error(err))
+	}
+}
+
+// newRouter wires the projections, mx may be nil
+func newRouter(r goRedis.UniversalClient, prefix redisInfra.PrefixSource, mx *projector.Metrics,
+	log *zap.Logger) *projector.Router {
+	cache := redisProjection.NewAuctionMetadataProjection(r, prefix, log)
+	metadata := redisProjection.NewProjection(cache, log, 15*time.Minute)
+
+	codec := &events.Codec{}
+	router := projector.NewRouter().Use(projector.Tracing(), projector.Logging(log), mx.Middleware())
+	projector.Topic(router, events.TopicAuctionOpened, projector.As[events.AuctionOpened](codec.DecodeMessage)).
+		Handle("auction-metadata", metadata.OnAuctionOpened)
+	projector.Topic(router, events.TopicAuctionClosed, projector.As[events.AuctionClosed](codec.DecodeMessage)).
+		Handle("auction-metadata", metadata.OnAuctionClosed)
+	projector.Topic(router, events.TopicBidsPlaced, projector.As[events.BidPlaced](codec.DecodeMessage)).
+		Handle("auction-price", metadata.OnBidPlaced)
+	return router
+}
+
+// rebuildTopics is the replay order: an auction's metadata exists before its bids raise the price, and closing
+// comes last so a replayed bid can't reopen the price of a closed auction
+var rebuildTopics = []string{events.TopicAuctionOpened, events.TopicBidsPlaced, events.TopicAuctionClosed}
+
+// rebuild replays the auction topics into a fresh key prefix and switches the pointer to it once caught up
+func rebuild(ctx context.Context, r goRedis.UniversalClient, c *cfg.Config, dlq *kafkaInfra.DeadLetter,
+	log *zap.Logger) (string, error) {
+	prefix := fmt.Sprintf("auction:r%s:", time.Now().UTC().Format("20060102T150405Z"))
+	log = log.With(zap.String("prefix", prefix))
+	log.Info("rebuild starting")
+
+	p := projector.New(nil, newRouter(r, redisInfra.Static(prefix), nil, log), projectorOptions(c, dlq), log)
+	err := p.Rebuild(ctx, c.KafkaReader.Brokers, c.KafkaReader.Security, projector.RebuildPlan{
+		Topics: rebuildTopics,
+		Switch: func(ctx context.Context) error {
+			return redisInfra.SwitchActivePrefix(ctx, r, redisProjection.ActivePrefixKey, prefix)
+		},
+		Grace: 3 * redisInfra.DefaultRefreshInterval,
+	})
+	return prefix, err
+}
+
+func projectorOptions(c *cfg.Config, dlq *kafkaInfra.DeadLetter) projector.Options {
+	opts := projector.Options{DeadLetter: dlq}
+	if r := c.Retry; r != nil {
+		opts.Retry = projector.RetryPolicy{
+			MaxAttempts:    r.MaxAttempts,
+			InitialBackoff: time.Duration(r.InitialBackoffMs) * time.Millisecond,
+			MaxBackoff:     time.Duration(r.MaxBackoffMs) * time.Millisecond,
+		}
+	}
+	if w := c.Workers; w != nil {
+		opts.Workers = w.Count
+		opts.QueueSize = w.QueueSize
+	}
+	return opts
+}
+
+func opsAddr(n *config.Network) string {
+	if n.IsLocalHost {
+		return fmt.Sprintf("127.0.0.1:%d", n.Port)
+	}
+	return fmt.Sprintf(":%d", n.Port)
 }
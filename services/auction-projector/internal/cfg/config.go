@@ -1,10 +1,12 @@
 package cfg
 
 import (
+	"errors"
 	"kei-services/pkg/config"
 	"kei-services/pkg/infra/kafka"
 	"kei-services/pkg/infra/redis"
 	"kei-services/pkg/logger"
+	"kei-services/pkg/tracing"
 )
 
 type Config struct {
@@ -14,7 +16,62 @@ type Config struct {
 
 	Logger *logger.Config
 
+	Tracing *tracing.Config
+
 	Redis *redis.Config
 
 	KafkaReader *kafka.ReaderConfig
+
+	DeadLetter *kafka.DeadLetterConfig
+
+	Retry *RetryConfig
+
+	Workers *WorkersConfig
+}
+
+// Validate reports every problem in c at once, required sections that are missing included
+func (c *Config) Validate() error {
+	return errors.Join(
+		config.Required("App", c.App),
+		config.Required("Network", c.Network),
+		config.Required("Logger", c.Logger),
+		config.Optional("Tracing", c.Tracing),
+		config.Required("Redis", c.Redis),
+		config.Required("KafkaReader", c.KafkaReader),
+		config.Optional("DeadLetter", c.DeadLetter),
+		config.Optional("Retry", c.Retry),
+		config.Optional("Workers", c.Workers),
+	)
+}
+
+// RetryConfig bounds handler retries before a message is dead-lettered
+type RetryConfig struct {
+	MaxAttempts      int // including the first, default: 5
+	InitialBackoffMs int // default: 200
+	MaxBackoffMs     int // default: 10000
+}
+
+func (c *RetryConfig) Validate() error {
+	var errs []error
+	if c.MaxAttempts < 0 || c.InitialBackoffMs < 0 || c.MaxBackoffMs < 0 {
+		errs = append(errs, config.Problem("MaxAttempts", "attempts and backoffs must not be negative"))
+	}
+	if c.MaxBackoffMs > 0 && c.InitialBackoffMs > c.MaxBackoffMs {
+		errs = append(errs, config.Problem("InitialBackoffMs", "%d exceeds maxBackoffMs %d", c.InitialBackoffMs, c.MaxBackoffMs))
+	}
+	return errors.Join(errs...)
+}
+
+// WorkersConfig sets how many messages are handled in parallel, messages with the same key (auction ID)
+// always go to the same worker so per-auction ordering is kept
+type WorkersConfig struct {
+	Count     int // default: 1
+	QueueSize int // per worker, default: 64
+}
+
+func (c *WorkersConfig) Validate() error {
+	if c.Count < 0 || c.QueueSize < 0 {
+		return config.Problem("Count", "workers and queue size must not be negative")
+	}
+	return nil
 }
@@ -3,6 +3,7 @@ package cfg
 import (
 	"fmt"
 	"kei-services/pkg/config"
+	"kei-services/pkg/infra/kafka"
 	"kei-services/pkg/infra/redis"
 	"log"
 	"os"
@@ -23,6 +24,7 @@ func Load(path string) (*Config, error) {
 	// env bindings
 	config.BindSsl(v)
 	redis.BindEnv(v)
+	kafka.BindEnv(v, "KAFKA", "kafkaReader")
 
 	if err := v.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("read config from %q: %w", path, err)
@@ -1,9 +1,12 @@
 package events
 
 import (
-	"encoding/json"
-	"fmt"
+	"kei-services/pkg/eventspb"
+	"kei-services/pkg/schema"
 	"time"
+
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // AuctionOpened is a domain event emitted by the auction service when an auction is opened
@@ -22,23 +25,87 @@ type AuctionClosed struct {
 	Version   int       `json:"version"`
 }
 
+// BidPlaced is a domain event emitted by the bid command service when a bid is accepted, projected to keep
+// the auction's current price up to date
+type BidPlaced struct {
+	AuctionID string    `json:"auctionId"`
+	BidID     string    `json:"bidId"`
+	BidderID  string    `json:"bidderId"`
+	Amount    float64   `json:"amount"`
+	At        time.Time `json:"at"`
+	Seq       int64     `json:"seq,omitempty"` // bids table sequence, 0 from producers before it was added
+}
+
+const (
+	TopicAuctionOpened = "auction.opened"
+	TopicAuctionClosed = "auction.closed"
+	TopicBidsPlaced    = "bids.placed"
+)
+
+// schemas are the event versions this service understands. Bump a schema's current version together with
+// its struct and register an upcaster lifting the previous version, eg
+//
+//	schema.Register[BidPlaced](r, TopicBidsPlaced, 2, map[int]schema.Upcaster{1: bidPlacedV1ToV2})
+var schemas = func() *schema.Registry {
+	r := schema.NewRegistry()
+	schema.Register[AuctionOpened](r, TopicAuctionOpened, 1, nil)
+	schema.Register[AuctionClosed](r, TopicAuctionClosed, 1, nil)
+	schema.Register[BidPlaced](r, TopicBidsPlaced, 1, nil)
+	schema.RegisterProto(r, TopicAuctionOpened, func() *eventspb.AuctionOpened { return &eventspb.AuctionOpened{} },
+		auctionOpenedFromProto)
+	schema.RegisterProto(r, TopicAuctionClosed, func() *eventspb.AuctionClosed { return &eventspb.AuctionClosed{} },
+		auctionClosedFromProto)
+	schema.RegisterProto(r, TopicBidsPlaced, func() *eventspb.BidPlaced { return &eventspb.BidPlaced{} },
+		bidPlacedFromProto)
+	return r
+}()
+
+// Codec decodes JSON or protobuf events by their content-type, schema and schema-version headers, upcasting
+// older versions. Unknown content types, schemas or versions fail to decode and are dead-lettered
 type Codec struct{}
 
+// Decode decodes payload as the topic's schema at version 1, for messages without schema headers
 func (c *Codec) Decode(topic string, payload []byte) (any, error) {
-	switch topic {
-	case "auction.opened":
-		var e AuctionOpened
-		if err := json.Unmarshal(payload, &e); err != nil {
-			return nil, err
-		}
-		return e, nil
-	case "auction.closed":
-		var e AuctionClosed
-		if err := json.Unmarshal(payload, &e); err != nil {
-			return nil, err
-		}
-		return e, nil
-	default:
-		return nil, fmt.Errorf("unknown topic %s", topic)
+	return c.DecodeMessage(kafka.Message{Topic: topic, Value: payload})
+}
+
+func (c *Codec) DecodeMessage(msg kafka.Message) (any, error) {
+	return schemas.DecodeMessage(msg)
+}
+
+func auctionOpenedFromProto(m *eventspb.AuctionOpened) AuctionOpened {
+	return AuctionOpened{
+		AuctionID:     m.GetAuctionId(),
+		EndsAt:        asTime(m.GetEndsAt()),
+		StartingPrice: m.GetStartingPrice(),
+		MinIncrement:  m.GetMinIncrement(),
+		Version:       int(m.GetVersion()),
+	}
+}
+
+func auctionClosedFromProto(m *eventspb.AuctionClosed) AuctionClosed {
+	return AuctionClosed{
+		AuctionID: m.GetAuctionId(),
+		ClosedAt:  asTime(m.GetClosedAt()),
+		Version:   int(m.GetVersion()),
+	}
+}
+
+func bidPlacedFromProto(m *eventspb.BidPlaced) BidPlaced {
+	return BidPlaced{
+		AuctionID: m.GetAuctionId(),
+		BidID:     m.GetBidId(),
+		BidderID:  m.GetBidderId(),
+		Amount:    m.GetAmount(),
+		At:        asTime(m.GetAt()),
+		Seq:       m.GetSeq(),
+	}
+}
+
+// asTime keeps a missing timestamp as the zero time, like a missing JSON field
+func asTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
 	}
+	return ts.AsTime()
 }
@@ -76,6 +76,29 @@ func TestCodec_Decode_AuctionClosed(t *testing.T) {
 	})
 }
 
+func TestCodec_Decode_BidPlaced(t *testing.T) {
+	codec := &Codec{}
+
+	t.Run("valid bid placed event", func(t *testing.T) {
+		payload := []byte(`{"auctionId":"auction-1","bidId":"bid-1","bidderId":"bidder-1","amount":120.5,"at":"2024-01-01T12:00:00Z","seq":42}`)
+
+		decoded, err := codec.Decode("bids.placed", payload)
+		assert.NoError(t, err)
+
+		evt, ok := decoded.(BidPlaced)
+		assert.True(t, ok)
+		assert.Equal(t, "auction-1", evt.AuctionID)
+		assert.Equal(t, 120.5, evt.Amount)
+		assert.Equal(t, int64(42), evt.Seq)
+	})
+
+	t.Run("missing seq decodes as zero", func(t *testing.T) {
+		decoded, err := codec.Decode("bids.placed", []byte(`{"auctionId":"auction-1","amount":10}`))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), decoded.(BidPlaced).Seq)
+	})
+}
+
 func TestCodec_Decode_UnknownTopic(t *testing.T) {
 	codec := &Codec{}
 
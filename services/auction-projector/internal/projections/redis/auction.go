@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	redisInfra "kei-services/pkg/infra/redis"
 	"time"
 
 	goRedis "github.com/redis/go-redis/v9"
@@ -24,25 +25,38 @@ type AuctionMetadata struct {
 	StartingPrice float64       `json:"startingPrice"`
 	CurrentPrice  float64       `json:"currentPrice"`
 	MinIncrement  float64       `json:"minIncrement"`
-	Version       int           `json:"version"`
+	Version       int           `json:"version"` // latest bid seq, the auction version until the first bid
+
+	// AuctionVersion orders auction.opened/closed, bids don't change it. Docs written before it existed
+	// fall back to Version
+	AuctionVersion int `json:"auctionVersion"`
 }
 
+const (
+	// DefaultKeyPrefix namespaces auction metadata until the first rebuild switches the pointer
+	DefaultKeyPrefix = "auction:"
+	// ActivePrefixKey points at the key prefix currently served, bid-command follows it too
+	ActivePrefixKey = "auction-meta:active-prefix"
+	// InvalidateChannel gets the key of every metadata write, bid-command's in-memory cache drops it
+	InvalidateChannel = "auction-meta:invalidate"
+)
+
 type AuctionMetadataProjection struct {
-	keyPrefix string
-	redis     *goRedis.Client
+	keyPrefix redisInfra.PrefixSource
+	redis     goRedis.UniversalClient
 	log       *zap.Logger
 }
 
-func NewAuctionMetadataProjection(r *goRedis.Client, log *zap.Logger) *AuctionMetadataProjection {
+func NewAuctionMetadataProjection(r goRedis.UniversalClient, prefix redisInfra.PrefixSource, log *zap.Logger) *AuctionMetadataProjection {
 	return &AuctionMetadataProjection{
-		keyPrefix: "auction:",
+		keyPrefix: prefix,
 		redis:     r,
 		log:       log,
 	}
 }
 
 // key returns the Redis key for a given auction ID
-func (p *AuctionMetadataProjection) key(id string) string { return p.keyPrefix + id }
+func (p *AuctionMetadataProjection) key(id string) string { return p.keyPrefix.Prefix() + id }
 
 // Get returns auction metadata from cache
 func (p *AuctionMetadataProjection) Get(ctx context.Context, auctionID string) (*AuctionMetadata, error) {
@@ -75,7 +89,8 @@ func (p *AuctionMetadataProjection) Get(ctx context.Context, auctionID string) (
 //	return p.redis.Set(ctx, p.key(auctionID), raw, ttl).Err()
 //}
 
-// SetIfNewer ensures monotonic version updates
+// SetIfNewer ensures monotonic auction version updates. The current price and bid version already
+// projected from bids.placed are kept, bids can be projected before the auction event that precedes them
 func (p *AuctionMetadataProjection) SetIfNewer(ctx context.Context, auctionID string, auction AuctionMetadata, ttl time.Duration) error {
 	raw, err := json.Marshal(auction)
 	if err != nil {
@@ -83,29 +98,105 @@ func (p *AuctionMetadataProjection) SetIfNewer(ctx context.Context, auctionID st
 		return err
 	}
 
-	p.log.Debug("setting auction metadata in cache if newer", zap.String("auctionID", auctionID), zap.Int("version", auction.Version))
+	p.log.Debug("setting auction metadata in cache if newer", zap.String("auctionID", auctionID), zap.Int("version", auction.AuctionVersion))
 	ttlSec := int(ttl / time.Second)
-	_, err = setIfNewerLua.Run(ctx, p.redis, []string{p.key(auctionID)}, auction.Version, string(raw), ttlSec).Result()
+	_, err = setIfNewerLua.Run(ctx, p.redis, []string{p.key(auctionID)}, auction.AuctionVersion, string(raw), ttlSec,
+		InvalidateChannel).Result()
 	return err
 }
 
-// setIfNewerLua sets the key only if the new version is greater than the existing version
+// ApplyBid raises the current price to amount and the version to seq. Bids of an auction only go up, so a
+// bid at or below the current price (redelivered or out of order) is skipped, seq breaks ties between
+// equal amounts. Without metadata yet a placeholder keeps the price until auction.opened merges into it
+func (p *AuctionMetadataProjection) ApplyBid(ctx context.Context, auctionID string, amount float64, seq int64,
+	placeholderTTL time.Duration) (bool, error) {
+	applied, err := applyBidLua.Run(ctx, p.redis, []string{p.key(auctionID)},
+		auctionID, amount, seq, int(placeholderTTL/time.Second), InvalidateChannel).Int()
+	if err != nil {
+		return false, err
+	}
+	return applied == 1, nil
+}
+
+// setIfNewerLua sets the key only if the new auction version is greater or equal than the existing one,
+// keeping a higher current price and bid version from the existing value, and publishes the key once set
 var setIfNewerLua = goRedis.NewScript(`
 local key = KEYS[1]
 local version = tonumber(ARGV[1])
 local value = ARGV[2]
 local ttlsec = tonumber(ARGV[3])
+local channel = ARGV[4]
 
 local cur = redis.call('GET', key)
 if cur then
   local ok, obj = pcall(cjson.decode, cur)
-  if ok and obj['version'] and tonumber(obj['version']) > version then
-    return 0
+  if ok then
+    local curVersion = tonumber(obj['auctionVersion'] or obj['version']) or 0
+    if curVersion > version then
+      return 0
+    end
+    local curPrice = tonumber(obj['currentPrice']) or 0
+    local new = cjson.decode(value)
+    if curPrice > (tonumber(new['currentPrice']) or 0) then
+      new['currentPrice'] = curPrice
+      new['version'] = math.max(tonumber(obj['version']) or 0, tonumber(new['version']) or 0)
+      value = cjson.encode(new)
+    end
   end
 end
 redis.call('SET', key, value)
 if ttlsec > 0 then
   redis.call('EXPIRE', key, ttlsec)
 end
+redis.call('PUBLISH', channel, key)
+return 1
+`)
+
+// applyBidLua raises currentPrice and version if the bid is newer, keeping the key's TTL, and publishes the
+// key when it did
+var applyBidLua = goRedis.NewScript(`
+local key = KEYS[1]
+local auctionId = ARGV[1]
+local amount = tonumber(ARGV[2])
+local seq = tonumber(ARGV[3])
+local ttlsec = tonumber(ARGV[4])
+local channel = ARGV[5]
+
+local obj
+local cur = redis.call('GET', key)
+if cur then
+  local ok, decoded = pcall(cjson.decode, cur)
+  if not ok then
+    return redis.error_reply('auction metadata is not valid json')
+  end
+  obj = decoded
+else
+  obj = {auctionID = auctionId, status = '', version = 0, auctionVersion = 0}
+end
+
+-- pin the auction version before version starts tracking bids
+if obj['auctionVersion'] == nil then
+  obj['auctionVersion'] = tonumber(obj['version']) or 0
+end
+
+local price = tonumber(obj['currentPrice']) or 0
+local version = tonumber(obj['version']) or 0
+if amount < price or (amount == price and (seq == 0 or seq <= version)) then
+  return 0
+end
+
+obj['currentPrice'] = amount
+if seq > version then
+  obj['version'] = seq
+end
+
+if cur then
+  redis.call('SET', key, cjson.encode(obj), 'KEEPTTL')
+elseif ttlsec > 0 then
+  redis.call('SET', key, cjson.encode(obj), 'EX', ttlsec)
+else
+  redis.call('SET', key, cjson.encode(obj))
+end
+redis.call('PUBLISH', channel, key)
 return 1
 `)
@@ -31,6 +31,8 @@ func (p *Projection) OnAuctionOpened(ctx context.Context, e events.AuctionOpened
 		CurrentPrice:  0,
 		MinIncrement:  e.MinIncrement,
 		Version:       e.Version,
+
+		AuctionVersion: e.Version,
 	}
 	ttl := ttlFromEnd(e.EndsAt, p.ttlBuffer)
 	return p.cache.SetIfNewer(ctx, e.AuctionID, meta, ttl)
@@ -71,9 +73,30 @@ func (p *Projection) OnAuctionClosed(ctx context.Context, e events.AuctionClosed
 			}
 			return 0
 		}(),
-		Version: e.Version,
+		Version: func() int {
+			if cur != nil && cur.Version > e.Version {
+				return cur.Version
+			}
+			return e.Version
+		}(),
+		AuctionVersion: e.Version,
 	}
 
 	// keep closed auctions for 1 more hr
 	return p.cache.SetIfNewer(ctx, e.AuctionID, meta, 1*time.Hour)
 }
+
+// OnBidPlaced raises the auction's current price so bid-command rejects low bids before taking a row lock
+func (p *Projection) OnBidPlaced(ctx context.Context, e events.BidPlaced) error {
+	// without metadata the placeholder only has to outlive a late auction.opened
+	applied, err := p.cache.ApplyBid(ctx, e.AuctionID, e.Amount, e.Seq, 1*time.Hour)
+	if err != nil {
+		p.log.Warn("failed to apply bid to auction metadata", zap.String("auctionID", e.AuctionID), zap.Error(err))
+		return err
+	}
+	if !applied {
+		p.log.Debug("bid not newer than current price, skipping",
+			zap.String("auctionID", e.AuctionID), zap.String("bidID", e.BidID), zap.Int64("seq", e.Seq))
+	}
+	return nil
+}
@@ -9,6 +9,9 @@ import (
 	"kei-services/pkg/infra/postgres"
 	"kei-services/pkg/infra/redis"
 	"kei-services/pkg/logger"
+	"kei-services/pkg/metrics"
+	"kei-services/pkg/shutdown"
+	"kei-services/pkg/tracing"
 	"kei-services/services/bid-command/internal/cfg"
 	"kei-services/services/bid-command/internal/server"
 	"kei-services/services/bid-command/sqlc"
@@ -16,28 +19,54 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
+var checkConfig = flag.Bool("check-config", false, "validate the config, print every problem found and exit")
+
 func main() {
 	flag.Parse() // parse -config flag
 	_ = godotenv.Load(".env")
-	cfg, err := cfg.Load(config.Path())
+	reloader, err := cfg.NewReloader(config.Path())
 	if err != nil {
 		panic(err)
 	}
+	cfg := reloader.Current()
+	config.MustValidate(config.Path(), cfg, *checkConfig)
 
-	log := logger.Init(cfg.Logger, cfg.App)
+	log, level := logger.InitWithLevel(cfg.Logger, cfg.App)
 	if log == nil {
 		panic("initialize logger")
 	}
 	defer func() { _ = log.Sync() }()
 
+	// runtime-tunable settings follow the config file
+	followLogLevel(reloader, level)
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go func() {
+		if err := reloader.Watch(watchCtx, log); err != nil {
+			log.Warn("config hot reload disabled", zap.Error(err))
+		}
+	}()
+
 	log.Info("Starting App", zap.String("version", cfg.App.Version))
 
+	// phases run in reverse, clients registered as they are created close after what uses them stopped
+	sd := shutdown.New(shutdown.DefaultTimeout, log)
+	defer func() { _ = sd.Shutdown() }()
+
+	// tracing, flushed on exit
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, "bid-command", cfg.App, log)
+	if err != nil {
+		log.Fatal("init tracing", zap.Error(err))
+	}
+	sd.Add("tracing", shutdownTracing)
+
 	//// Connect to infrastructures
 	// postgres
 	db, err := postgres.Client(cfg.Postgres, &cfg.Network.Ssl, log)
@@ -48,25 +77,32 @@ func main() {
 	if err := sqlc.EnsureSchema(context.Background(), sqlDB); err != nil {
 		log.Fatal("apply schema", zap.Error(err))
 	}
-	defer func() {
-		if sqlDB, derr := db.DB(); derr == nil {
-			_ = sqlDB.Close()
-		}
-	}()
+	sd.AddCloser("postgres", sqlDB)
 
 	// Redis
 	redisClient, err := redis.Client(cfg.Redis, log)
 	if err != nil {
 		log.Fatal("connect to redis", zap.Error(err))
 	}
-	defer func() { _ = redisClient.Close() }()
+	sd.AddCloser("redis", redisClient)
+
+	met := metrics.New(metrics.Options{
+		Namespace:   "bidcommand",
+		ConstLabels: prometheus.Labels{"service": "bidcommand", "env": string(cfg.App.Environment)},
+	})
 
 	// Kafka
-	writer := kafkaInfra.NewWriter(cfg.KafkaWriter, log)
-	defer writer.Close()
+	cfg.KafkaWriter.Metrics = met
+	writer, err := kafkaInfra.NewWriter(cfg.KafkaWriter, log)
+	if err != nil {
+		log.Fatal("create kafka writer", zap.Error(err))
+	}
+	sd.AddCloser("kafka writer", writer) // closing flushes the pending messages
 
 	//// Create and start server
-	s := server.New(db, redisClient, writer, cfg, log)
+	s := server.New(db, redisClient, writer, met, reloader, log)
+
+	sd.Add("http server", func(ctx context.Context) error { return server.Shutdown(ctx, s, log) })
 
 	errCh := make(chan error, 1)
 	go func() {
@@ -83,13 +119,19 @@ func main() {
 		log.Info("shutdown signal received")
 	case err = <-errCh:
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_ = sd.Shutdown()
 			log.Fatal("http: server error", zap.Error(err))
 		}
 	}
 
-	// graceful shutdown
-	//stopBG() // stop subscriber
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	_ = server.Shutdown(shutdownCtx, s, log)
+	_ = sd.Shutdown()
+}
+
+// followLogLevel applies reloaded log levels, the reloader validated them
+func followLogLevel(rl *cfg.Reloader, level zap.AtomicLevel) {
+	rl.Subscribe(func(c *cfg.Config) {
+		if l, err := zapcore.ParseLevel(c.Logger.Level); err == nil {
+			level.SetLevel(l)
+		}
+	})
 }
@@ -0,0 +1,32 @@
+package auction_admin
+
+import (
+	"context"
+	"kei-services/services/bid-command/internal/domain"
+	"time"
+)
+
+// IService inspects and repairs the cached auction metadata bids are validated against
+type IService interface {
+	Inspect(ctx context.Context, auctionID string) (*Snapshot, error)
+	Refresh(ctx context.Context, auctionID string) (*Change, error)
+	Evict(ctx context.Context, auctionID string) (*Change, error)
+	Reseed(ctx context.Context, auctionID string) (*Change, error)
+}
+
+// Snapshot is the cached metadata of an auction next to the latest bid in Postgres
+type Snapshot struct {
+	Key       string
+	Cached    *domain.AuctionMetadata // nil when not cached
+	TTL       time.Duration           // negative when not cached or without expiry
+	LatestBid *domain.LatestBid       // nil without bids
+	Stale     bool                    // the cache is behind the latest bid
+}
+
+// Change is the cached metadata before and after a repair, nil when missing
+type Change struct {
+	Key    string
+	Before *domain.AuctionMetadata
+	After  *domain.AuctionMetadata
+	TTL    time.Duration // of After, negative when it was kept or After is nil
+}
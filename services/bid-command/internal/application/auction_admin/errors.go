@@ -0,0 +1,5 @@
+package auction_admin
+
+import "errors"
+
+var ErrNoAuctionEvents = errors.New("no_auction_events") // none retained in the auction topics
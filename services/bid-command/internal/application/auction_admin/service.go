@@ -0,0 +1,152 @@
+package auction_admin
+
+import (
+	"context"
+	"errors"
+	"kei-services/pkg/middleware"
+	"kei-services/services/bid-command/internal/domain"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// endsAtTTLBuffer and closedTTL match the TTLs auction-projector gives open and closed auctions
+	endsAtTTLBuffer = 15 * time.Minute
+	closedTTL       = time.Hour
+)
+
+type Service struct {
+	cache  domain.IAuctionMetadataCache
+	bids   domain.IBidReader
+	events domain.IAuctionEventSource
+	clock  domain.IClock
+	replay time.Duration
+	log    *zap.Logger
+}
+
+var _ IService = (*Service)(nil)
+
+type Deps struct {
+	Cache  domain.IAuctionMetadataCache
+	Bids   domain.IBidReader
+	Events domain.IAuctionEventSource
+	Clock  domain.IClock
+
+	ReseedTimeout time.Duration // bounds the scan of the auction topics, 0 leaves it to ctx
+}
+
+func NewService(d Deps, log *zap.Logger) *Service {
+	return &Service{
+		cache:  d.Cache,
+		bids:   d.Bids,
+		events: d.Events,
+		clock:  d.Clock,
+		replay: d.ReseedTimeout,
+		log:    log,
+	}
+}
+
+func (s *Service) Inspect(ctx context.Context, auctionID string) (*Snapshot, error) {
+	cached, err := s.cached(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := s.bids.Latest(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Snapshot{Key: s.cache.Key(auctionID), Cached: cached, TTL: -1, LatestBid: latest}
+	if cached != nil {
+		if out.TTL, err = s.cache.TTL(ctx, auctionID); err != nil {
+			return nil, err
+		}
+		out.Stale = latest != nil && (cached.CurrentPrice < latest.Amount || int64(cached.Version) < latest.Seq)
+	}
+	return out, nil
+}
+
+// Refresh resyncs the cached current price and version with the latest bid in Postgres, the auction fields
+// and the TTL are kept. Metadata that isn't cached can't be refreshed, see Reseed. Fails with
+// domain.ErrMetadataChanged when a newer bid or auction event was cached meanwhile
+func (s *Service) Refresh(ctx context.Context, auctionID string) (*Change, error) {
+	cached, err := s.cached(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if cached == nil {
+		return nil, domain.ErrAuctionNotCached
+	}
+	latest, err := s.bids.Latest(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	after := cached.WithLatestBid(latest)
+	if err = s.cache.Replace(ctx, auctionID, after); err != nil {
+		return nil, err
+	}
+	return &Change{Key: s.cache.Key(auctionID), Before: cached, After: &after, TTL: -1}, nil
+}
+
+// Evict deletes the cached metadata, bids for the auction are rejected until auction-projector or Reseed
+// writes it again
+func (s *Service) Evict(ctx context.Context, auctionID string) (*Change, error) {
+	cached, err := s.cached(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.cache.Delete(ctx, auctionID); err != nil {
+		return nil, err
+	}
+	return &Change{Key: s.cache.Key(auctionID), Before: cached, TTL: -1}, nil
+}
+
+// Reseed rebuilds the metadata from the latest auction events still retained and the latest bid in Postgres,
+// replacing what is cached unless it is newer (domain.ErrMetadataChanged)
+func (s *Service) Reseed(ctx context.Context, auctionID string) (*Change, error) {
+	log := middleware.LoggerFrom(ctx, s.log)
+
+	scanCtx, cancel := ctx, context.CancelFunc(func() {})
+	if s.replay > 0 {
+		scanCtx, cancel = context.WithTimeout(ctx, s.replay)
+	}
+	opened, closed, err := s.events.LatestEvents(scanCtx, auctionID)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	meta := domain.MetadataFromEvents(opened, closed)
+	if meta == nil {
+		return nil, ErrNoAuctionEvents
+	}
+	latest, err := s.bids.Latest(ctx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	after := meta.WithLatestBid(latest)
+
+	ttl := closedTTL
+	if after.IsOpen() {
+		ttl = max(after.EndsAt.Sub(s.clock.Now())+endsAtTTLBuffer, endsAtTTLBuffer)
+	}
+
+	before, err := s.cached(ctx, auctionID)
+	if err != nil {
+		log.Warn("read cached auction metadata before reseed", zap.String("auctionId", auctionID), zap.Error(err))
+	}
+	if err = s.cache.Set(ctx, auctionID, after, ttl); err != nil {
+		return nil, err
+	}
+	return &Change{Key: s.cache.Key(auctionID), Before: before, After: &after, TTL: ttl}, nil
+}
+
+// cached returns the cached metadata, nil when not cached
+func (s *Service) cached(ctx context.Context, auctionID string) (*domain.AuctionMetadata, error) {
+	m, err := s.cache.Get(ctx, auctionID)
+	if errors.Is(err, domain.ErrAuctionNotCached) {
+		return nil, nil
+	}
+	return m, err
+}
@@ -0,0 +1,238 @@
+package auction_admin
+
+import (
+	"context"
+	"kei-services/services/bid-command/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockCache struct {
+	mock.Mock
+}
+
+func (m *MockCache) Get(ctx context.Context, auctionID string) (*domain.AuctionMetadata, error) {
+	args := m.Called(ctx, auctionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AuctionMetadata), args.Error(1)
+}
+
+func (m *MockCache) Key(auctionID string) string { return "auction:" + auctionID }
+
+func (m *MockCache) TTL(ctx context.Context, auctionID string) (time.Duration, error) {
+	args := m.Called(ctx, auctionID)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (m *MockCache) Set(ctx context.Context, auctionID string, meta domain.AuctionMetadata, ttl time.Duration) error {
+	return m.Called(ctx, auctionID, meta, ttl).Error(0)
+}
+
+func (m *MockCache) Replace(ctx context.Context, auctionID string, meta domain.AuctionMetadata) error {
+	return m.Called(ctx, auctionID, meta).Error(0)
+}
+
+func (m *MockCache) Delete(ctx context.Context, auctionID string) error {
+	return m.Called(ctx, auctionID).Error(0)
+}
+
+type MockBidReader struct {
+	mock.Mock
+}
+
+func (m *MockBidReader) Latest(ctx context.Context, auctionID string) (*domain.LatestBid, error) {
+	args := m.Called(ctx, auctionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LatestBid), args.Error(1)
+}
+
+type MockEventSource struct {
+	mock.Mock
+}
+
+func (m *MockEventSource) LatestEvents(ctx context.Context, auctionID string) (*domain.AuctionOpened,
+	*domain.AuctionClosed, error) {
+	args := m.Called(ctx, auctionID)
+	var opened *domain.AuctionOpened
+	var closed *domain.AuctionClosed
+	if args.Get(0) != nil {
+		opened = args.Get(0).(*domain.AuctionOpened)
+	}
+	if args.Get(1) != nil {
+		closed = args.Get(1).(*domain.AuctionClosed)
+	}
+	return opened, closed, args.Error(2)
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+var now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newService() (*Service, *MockCache, *MockBidReader, *MockEventSource) {
+	cache, bids, events := &MockCache{}, &MockBidReader{}, &MockEventSource{}
+	svc := NewService(Deps{Cache: cache, Bids: bids, Events: events, Clock: fixedClock(now)}, zap.NewNop())
+	return svc, cache, bids, events
+}
+
+func TestService_Inspect(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cached metadata behind the latest bid is stale", func(t *testing.T) {
+		svc, cache, bids, _ := newService()
+		cached := &domain.AuctionMetadata{AuctionID: "a1", Status: domain.AuctionOpen, CurrentPrice: 100, Version: 10}
+		cache.On("Get", ctx, "a1").Return(cached, nil)
+		cache.On("TTL", ctx, "a1").Return(time.Minute, nil)
+		bids.On("Latest", ctx, "a1").Return(&domain.LatestBid{ID: "b1", Amount: 110, Seq: 11}, nil)
+
+		snap, err := svc.Inspect(ctx, "a1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "auction:a1", snap.Key)
+		assert.Equal(t, cached, snap.Cached)
+		assert.Equal(t, time.Minute, snap.TTL)
+		assert.True(t, snap.Stale)
+	})
+
+	t.Run("not cached", func(t *testing.T) {
+		svc, cache, bids, _ := newService()
+		cache.On("Get", ctx, "a1").Return(nil, domain.ErrAuctionNotCached)
+		bids.On("Latest", ctx, "a1").Return(nil, nil)
+
+		snap, err := svc.Inspect(ctx, "a1")
+
+		assert.NoError(t, err)
+		assert.Nil(t, snap.Cached)
+		assert.False(t, snap.Stale)
+		cache.AssertNotCalled(t, "TTL", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_Refresh(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("syncs price and version with postgres", func(t *testing.T) {
+		svc, cache, bids, _ := newService()
+		cached := &domain.AuctionMetadata{AuctionID: "a1", Status: domain.AuctionOpen, CurrentPrice: 100, Version: 10,
+			AuctionVersion: 1}
+		cache.On("Get", ctx, "a1").Return(cached, nil)
+		bids.On("Latest", ctx, "a1").Return(&domain.LatestBid{Amount: 110, Seq: 11}, nil)
+		want := *cached
+		want.CurrentPrice, want.Version = 110, 11
+		cache.On("Replace", ctx, "a1", want).Return(nil)
+
+		res, err := svc.Refresh(ctx, "a1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, cached, res.Before)
+		assert.Equal(t, &want, res.After)
+		cache.AssertExpectations(t)
+	})
+
+	t.Run("not cached", func(t *testing.T) {
+		svc, cache, _, _ := newService()
+		cache.On("Get", ctx, "a1").Return(nil, domain.ErrAuctionNotCached)
+
+		_, err := svc.Refresh(ctx, "a1")
+
+		assert.ErrorIs(t, err, domain.ErrAuctionNotCached)
+	})
+
+	t.Run("a newer bid was cached meanwhile", func(t *testing.T) {
+		svc, cache, bids, _ := newService()
+		cache.On("Get", ctx, "a1").Return(&domain.AuctionMetadata{AuctionID: "a1", Version: 10}, nil)
+		bids.On("Latest", ctx, "a1").Return(&domain.LatestBid{Amount: 110, Seq: 11}, nil)
+		cache.On("Replace", ctx, "a1", mock.Anything).Return(domain.ErrMetadataChanged)
+
+		res, err := svc.Refresh(ctx, "a1")
+
+		assert.ErrorIs(t, err, domain.ErrMetadataChanged)
+		assert.Nil(t, res)
+	})
+}
+
+func TestService_Evict(t *testing.T) {
+	ctx := context.Background()
+	svc, cache, _, _ := newService()
+	cached := &domain.AuctionMetadata{AuctionID: "a1", Status: domain.AuctionClose}
+	cache.On("Get", ctx, "a1").Return(cached, nil)
+	cache.On("Delete", ctx, "a1").Return(nil)
+
+	res, err := svc.Evict(ctx, "a1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, cached, res.Before)
+	assert.Nil(t, res.After)
+	cache.AssertExpectations(t)
+}
+
+func TestService_Reseed(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("open auction expires after its end", func(t *testing.T) {
+		svc, cache, bids, events := newService()
+		opened := &domain.AuctionOpened{AuctionID: "a1", EndsAt: now.Add(time.Hour), StartingPrice: 100,
+			MinIncrement: 5, Version: 1}
+		events.On("LatestEvents", ctx, "a1").Return(opened, nil, nil)
+		bids.On("Latest", ctx, "a1").Return(&domain.LatestBid{Amount: 120, Seq: 7}, nil)
+		cache.On("Get", ctx, "a1").Return(nil, domain.ErrAuctionNotCached)
+		want := domain.AuctionMetadata{AuctionID: "a1", Status: domain.AuctionOpen, EndsAt: opened.EndsAt,
+			StartingPrice: 100, CurrentPrice: 120, MinIncrement: 5, Version: 7, AuctionVersion: 1}
+		cache.On("Set", ctx, "a1", want, time.Hour+endsAtTTLBuffer).Return(nil)
+
+		res, err := svc.Reseed(ctx, "a1")
+
+		assert.NoError(t, err)
+		assert.Nil(t, res.Before)
+		assert.Equal(t, &want, res.After)
+		cache.AssertExpectations(t)
+	})
+
+	t.Run("closed auction", func(t *testing.T) {
+		svc, cache, bids, events := newService()
+		events.On("LatestEvents", ctx, "a1").Return(nil, &domain.AuctionClosed{AuctionID: "a1", Version: 2}, nil)
+		bids.On("Latest", ctx, "a1").Return(nil, nil)
+		cache.On("Get", ctx, "a1").Return(nil, domain.ErrAuctionNotCached)
+		cache.On("Set", ctx, "a1", mock.MatchedBy(func(m domain.AuctionMetadata) bool {
+			return m.Status == domain.AuctionClose
+		}), closedTTL).Return(nil)
+
+		_, err := svc.Reseed(ctx, "a1")
+
+		assert.NoError(t, err)
+		cache.AssertExpectations(t)
+	})
+
+	t.Run("no events retained", func(t *testing.T) {
+		svc, cache, _, events := newService()
+		events.On("LatestEvents", ctx, "a1").Return(nil, nil, nil)
+
+		_, err := svc.Reseed(ctx, "a1")
+
+		assert.ErrorIs(t, err, ErrNoAuctionEvents)
+		cache.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a newer auction event was cached meanwhile", func(t *testing.T) {
+		svc, cache, bids, events := newService()
+		events.On("LatestEvents", ctx, "a1").Return(&domain.AuctionOpened{AuctionID: "a1", EndsAt: now.Add(time.Hour),
+			Version: 1}, nil, nil)
+		bids.On("Latest", ctx, "a1").Return(nil, nil)
+		cache.On("Get", ctx, "a1").Return(&domain.AuctionMetadata{AuctionID: "a1", AuctionVersion: 2}, nil)
+		cache.On("Set", ctx, "a1", mock.Anything, mock.Anything).Return(domain.ErrMetadataChanged)
+
+		res, err := svc.Reseed(ctx, "a1")
+
+		assert.ErrorIs(t, err, domain.ErrMetadataChanged)
+		assert.Nil(t, res)
+	})
+}
@@ -2,6 +2,7 @@ package place_bid
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"kei-services/pkg/middleware"
 	"kei-services/services/bid-command/internal/application"
@@ -48,17 +49,39 @@ func (s *Service) Handle(ctx context.Context, cmd Command) (*Result, error) {
 		zap.String("bidder_id", cmd.BidderID),
 		zap.Float64("amount", cmd.Amount))
 
+	// fail fast rather than commit a bid that can't be published
+	if a, ok := s.pub.(domain.IAvailability); ok && !a.Available() {
+		log.Warn("publish path unavailable, rejecting bid", zap.String("auction_id", cmd.AuctionID))
+		return nil, domain.ErrPublishUnavailable
+	}
+
 	// fast pre-check using cache
 	auction, err := s.cache.Get(ctx, cmd.AuctionID)
-	if err = domain.ValidateBid(auction, cmd.Amount, nil); err != nil {
-		log.Warn("validate bid failed", zap.Error(err))
-		return nil, err
+	switch {
+	case errors.Is(err, domain.ErrMetadataUnavailable):
+		log.Warn("auction metadata unavailable", zap.String("auction_id", cmd.AuctionID), zap.Error(err))
+		return nil, domain.ErrMetadataUnavailable
+	case errors.Is(err, domain.ErrMetadataStale):
+		// an open auction past its end may have been closed since, without a way to tell reject rather than
+		// accept a bid after close
+		if auction.IsOpen() && !s.clock.Now().Before(auction.EndsAt) {
+			log.Warn("auction metadata stale past endsAt", zap.String("auction_id", cmd.AuctionID), zap.Error(err))
+			return nil, domain.ErrMetadataUnavailable
+		}
+		// the cached price may be behind, skip the pre-check and leave it to the check against the DB
+		log.Warn("auction metadata stale, skipping pre-check", zap.String("auction_id", cmd.AuctionID), zap.Error(err))
+	default:
+		if err = domain.ValidateBid(auction, cmd.Amount, nil); err != nil {
+			log.Warn("validate bid failed", zap.Error(err))
+			return nil, err
+		}
 	}
 
 	bid := domain.NewBid(cmd.AuctionID, cmd.BidderID, cmd.Amount, s.clock.Now().UTC())
 
 	// authoritative check against latest bid inside DB transaction
 	var out *Result
+	var placedSeq int64
 	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
 		// get latest bid with row lock to serialize concurrent bids
 		latest, err := s.bidRepo.LatestForUpdate(ctx, cmd.AuctionID)
@@ -90,6 +113,7 @@ func (s *Service) Handle(ctx context.Context, cmd Command) (*Result, error) {
 		if id != "" {
 			bid = bid.WithID(id)
 		}
+		placedSeq = seq
 
 		// Compute the snapshot after acceptance
 		after := domain.ApplyAccepted(domain.AuctionMetadata{
@@ -131,6 +155,7 @@ func (s *Service) Handle(ctx context.Context, cmd Command) (*Result, error) {
 		BidderID:  out.BidderID,
 		Amount:    out.CurrentPrice,
 		At:        out.At,
+		Seq:       placedSeq,
 	}); err != nil {
 		log.Error("publish bids.placed failed",
 			zap.String("auctionId", out.AuctionID),
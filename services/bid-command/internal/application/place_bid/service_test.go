@@ -316,3 +316,189 @@ func TestService_Handle_PublishFails(t *testing.T) {
 	mockTx.AssertExpectations(t)
 	mockClock.AssertExpectations(t)
 }
+
+type MockAvailablePublisher struct {
+	MockBidsPlacedPublisher
+}
+
+func (m *MockAvailablePublisher) Available() bool {
+	args := m.Called()
+	return args.Bool(0)
+}
+
+func TestService_Handle_PublishUnavailable(t *testing.T) {
+	ctx := context.Background()
+
+	cmd := Command{
+		AuctionID: "auction-1",
+		BidderID:  "bidder-1",
+		Amount:    120.0,
+	}
+
+	mockCache := new(MockAuctionMetadataStore)
+	mockRepo := new(MockBidRepository)
+	mockPub := new(MockAvailablePublisher)
+	mockTx := new(MockTxManager)
+	mockClock := new(MockClock)
+
+	mockPub.On("Available").Return(false)
+
+	deps := Deps{
+		BidRepo: mockRepo,
+		Cache:   mockCache,
+		Pub:     mockPub,
+		Tx:      mockTx,
+		Clock:   mockClock,
+	}
+
+	service := NewService(deps, zap.NewNop())
+
+	// Execute
+	result, err := service.Handle(ctx, cmd)
+
+	// Assert: rejected before reading the cache or opening a tx
+	assert.ErrorIs(t, err, domain.ErrPublishUnavailable)
+	assert.Nil(t, result)
+
+	mockPub.AssertExpectations(t)
+	mockCache.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	mockTx.AssertNotCalled(t, "WithinTx", mock.Anything, mock.Anything)
+}
+
+func TestService_Handle_MetadataUnavailable(t *testing.T) {
+	ctx := context.Background()
+
+	cmd := Command{
+		AuctionID: "auction-1",
+		BidderID:  "bidder-1",
+		Amount:    120.0,
+	}
+
+	mockCache := new(MockAuctionMetadataStore)
+	mockRepo := new(MockBidRepository)
+	mockPub := new(MockBidsPlacedPublisher)
+	mockTx := new(MockTxManager)
+	mockClock := new(MockClock)
+
+	mockCache.On("Get", ctx, "auction-1").Return(nil, domain.ErrMetadataUnavailable)
+
+	deps := Deps{
+		BidRepo: mockRepo,
+		Cache:   mockCache,
+		Pub:     mockPub,
+		Tx:      mockTx,
+		Clock:   mockClock,
+	}
+
+	service := NewService(deps, zap.NewNop())
+
+	// Execute
+	result, err := service.Handle(ctx, cmd)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrMetadataUnavailable)
+	assert.Nil(t, result)
+
+	mockCache.AssertExpectations(t)
+	mockTx.AssertNotCalled(t, "WithinTx", mock.Anything, mock.Anything)
+}
+
+func TestService_Handle_MetadataStale(t *testing.T) {
+	ctx := context.Background()
+	fixedTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// last read before Redis went away, the DB has moved on to 150
+	stale := &domain.AuctionMetadata{
+		AuctionID:     "auction-1",
+		Status:        domain.AuctionOpen,
+		EndsAt:        fixedTime.Add(1 * time.Hour),
+		StartingPrice: 100.0,
+		CurrentPrice:  100.0,
+		MinIncrement:  10.0,
+		Version:       1,
+	}
+
+	t.Run("validated against the DB", func(t *testing.T) {
+		mockCache := new(MockAuctionMetadataStore)
+		mockRepo := new(MockBidRepository)
+		mockPub := new(MockBidsPlacedPublisher)
+		mockTx := new(MockTxManager)
+		mockClock := new(MockClock)
+
+		mockCache.On("Get", ctx, "auction-1").Return(stale, domain.ErrMetadataStale)
+		mockClock.On("Now").Return(fixedTime)
+		mockTx.On("WithinTx", ctx, mock.Anything).Return(nil)
+		mockRepo.On("LatestForUpdate", ctx, "auction-1").Return(&domain.LatestBid{Amount: 150.0, Seq: 4}, nil)
+
+		service := NewService(Deps{BidRepo: mockRepo, Cache: mockCache, Pub: mockPub, Tx: mockTx, Clock: mockClock},
+			zap.NewNop())
+
+		// Execute: 120 passes the stale pre-check but not the DB's latest bid
+		result, err := service.Handle(ctx, Command{AuctionID: "auction-1", BidderID: "bidder-1", Amount: 120.0})
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrBelowMinIncrement)
+		assert.Nil(t, result)
+
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+		mockPub.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+
+	t.Run("accepted", func(t *testing.T) {
+		mockCache := new(MockAuctionMetadataStore)
+		mockRepo := new(MockBidRepository)
+		mockPub := new(MockBidsPlacedPublisher)
+		mockTx := new(MockTxManager)
+		mockClock := new(MockClock)
+
+		mockCache.On("Get", ctx, "auction-1").Return(stale, domain.ErrMetadataStale)
+		mockClock.On("Now").Return(fixedTime)
+		mockTx.On("WithinTx", ctx, mock.Anything).Return(nil)
+		mockRepo.On("LatestForUpdate", ctx, "auction-1").Return(&domain.LatestBid{Amount: 150.0, Seq: 4}, nil)
+		mockRepo.On("Insert", ctx, mock.AnythingOfType("*domain.Bid")).Return("bid-123", int64(5), nil)
+		mockPub.On("Publish", ctx, mock.AnythingOfType("domain.BidPlaced")).Return(nil)
+
+		service := NewService(Deps{BidRepo: mockRepo, Cache: mockCache, Pub: mockPub, Tx: mockTx, Clock: mockClock},
+			zap.NewNop())
+
+		// Execute
+		result, err := service.Handle(ctx, Command{AuctionID: "auction-1", BidderID: "bidder-1", Amount: 160.0})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Equal(t, 160.0, result.CurrentPrice)
+		assert.Equal(t, 5, result.Version)
+
+		mockRepo.AssertExpectations(t)
+		mockPub.AssertExpectations(t)
+	})
+
+	t.Run("open past endsAt", func(t *testing.T) {
+		mockCache := new(MockAuctionMetadataStore)
+		mockRepo := new(MockBidRepository)
+		mockPub := new(MockBidsPlacedPublisher)
+		mockTx := new(MockTxManager)
+		mockClock := new(MockClock)
+
+		// still OPEN when last read, but it has ended since and may have been closed while Redis was away
+		ended := *stale
+		ended.EndsAt = fixedTime.Add(-1 * time.Minute)
+		mockCache.On("Get", ctx, "auction-1").Return(&ended, domain.ErrMetadataStale)
+		mockClock.On("Now").Return(fixedTime)
+
+		service := NewService(Deps{BidRepo: mockRepo, Cache: mockCache, Pub: mockPub, Tx: mockTx, Clock: mockClock},
+			zap.NewNop())
+
+		// Execute
+		result, err := service.Handle(ctx, Command{AuctionID: "auction-1", BidderID: "bidder-1", Amount: 160.0})
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrMetadataUnavailable)
+		assert.Nil(t, result)
+
+		mockTx.AssertNotCalled(t, "WithinTx", mock.Anything, mock.Anything)
+		mockPub.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+}
@@ -1,6 +1,8 @@
 package cfg
 
 import (
+	"errors"
+	"kei-services/pkg/breaker"
 	"kei-services/pkg/config"
 	"kei-services/pkg/infra/kafka"
 	"kei-services/pkg/infra/postgres"
@@ -8,6 +10,9 @@ import (
 	"kei-services/pkg/logger"
 	"kei-services/pkg/profiler"
 	swagger "kei-services/pkg/swagger"
+	"kei-services/pkg/tracing"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -17,15 +22,162 @@ type Config struct {
 
 	Cors *config.Cors
 
+	RateLimit *config.RateLimit
+
+	Features Features
+
 	Pprof *profiler.Config
 
 	Swagger *swagger.Config
 
 	Logger *logger.Config
 
+	Tracing *tracing.Config
+
 	Postgres *postgres.Config
 
 	Redis *redis.Config
 
 	KafkaWriter *kafka.WriterConfig
+
+	Events *EventsConfig
+
+	Admin *AdminConfig
+
+	Breakers *BreakersConfig
+
+	MetadataL1 *MetadataL1Config
+}
+
+// Validate reports every problem in c at once, required sections that are missing included
+func (c *Config) Validate() error {
+	return errors.Join(
+		config.Required("App", c.App),
+		config.Required("Network", c.Network),
+		config.Required("Cors", c.Cors),
+		config.Optional("RateLimit", c.RateLimit),
+		config.Required("Pprof", c.Pprof),
+		config.Required("Swagger", c.Swagger),
+		config.Required("Logger", c.Logger),
+		config.Optional("Tracing", c.Tracing),
+		config.Required("Postgres", c.Postgres),
+		config.Required("Redis", c.Redis),
+		config.Required("KafkaWriter", c.KafkaWriter),
+		config.Optional("Events", c.Events),
+		config.Optional("Admin", c.Admin),
+		config.Optional("Breakers", c.Breakers),
+		config.Optional("MetadataL1", c.MetadataL1),
+	)
+}
+
+// EventsConfig sets how published events are encoded
+type EventsConfig struct {
+	Encoding         string // json (default) or protobuf
+	PublishTimeoutMs int    // default: 3000
+}
+
+func (e *EventsConfig) Validate() error {
+	var errs []error
+	switch e.Encoding {
+	case "", "json", "protobuf":
+	default:
+		errs = append(errs, config.Problem("Encoding", "%q, expected json or protobuf", e.Encoding))
+	}
+	if e.PublishTimeoutMs < 0 {
+		errs = append(errs, config.Problem("PublishTimeoutMs", "must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+func (e *EventsConfig) PublishTimeout() time.Duration {
+	if e == nil || e.PublishTimeoutMs <= 0 {
+		return 3 * time.Second
+	}
+	return time.Duration(e.PublishTimeoutMs) * time.Millisecond
+}
+
+// AdminConfig enables the operator API under /admin, see the admin routes
+type AdminConfig struct {
+	IsEnabled       bool
+	Token           string `mapstructure:"-"` // bearer token, ADMIN_TOKEN env only, never from the config file
+	ReseedTimeoutMs int    // replaying the auction topics, default: 30000
+}
+
+func (a *AdminConfig) Validate() error {
+	if !a.IsEnabled {
+		return nil
+	}
+	var errs []error
+	if len(a.Token) < 16 {
+		errs = append(errs, config.Problem("Token", "must be at least 16 characters when enabled, set ADMIN_TOKEN"))
+	}
+	if a.ReseedTimeoutMs < 0 {
+		errs = append(errs, config.Problem("ReseedTimeoutMs", "must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+func (a *AdminConfig) ReseedTimeout() time.Duration {
+	if a == nil || a.ReseedTimeoutMs <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(a.ReseedTimeoutMs) * time.Millisecond
+}
+
+// BreakersConfig sets the circuit breakers around place_bid's dependencies, read at startup only
+type BreakersConfig struct {
+	Cache   *breaker.Config // auction metadata reads from Redis
+	Publish *breaker.Config // bids.placed writes to Kafka, PublishTimeoutMs already bounds each call
+}
+
+func (b *BreakersConfig) Validate() error {
+	return errors.Join(
+		config.Optional("Cache", b.Cache),
+		config.Optional("Publish", b.Publish),
+	)
+}
+
+// MetadataL1Config keeps auction metadata in memory in front of Redis for place_bid, read at startup only
+type MetadataL1Config struct {
+	IsEnabled  bool
+	TTLMs      int // bounds staleness when an invalidation is lost, default: 1000
+	MaxEntries int // least recently used auctions are evicted first, default: 10000
+}
+
+func (m *MetadataL1Config) Validate() error {
+	var errs []error
+	if m.TTLMs < 0 {
+		errs = append(errs, config.Problem("TTLMs", "must not be negative"))
+	}
+	if m.MaxEntries < 0 {
+		errs = append(errs, config.Problem("MaxEntries", "must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MetadataL1Config) TTL() time.Duration {
+	if m.TTLMs <= 0 {
+		return time.Second
+	}
+	return time.Duration(m.TTLMs) * time.Millisecond
+}
+
+func (m *MetadataL1Config) Size() int {
+	if m.MaxEntries <= 0 {
+		return 10000
+	}
+	return m.MaxEntries
+}
+
+// Features are runtime toggles. Config keys are case-insensitive, names are matched in lower case
+type Features map[string]bool
+
+const FeaturePlaceBids = "placeBids"
+
+// Enabled reports toggle name, def when it isn't configured
+func (f Features) Enabled(name string, def bool) bool {
+	if v, ok := f[strings.ToLower(name)]; ok {
+		return v
+	}
+	return def
 }
@@ -3,13 +3,12 @@ package cfg
 import (
 	"fmt"
 	"kei-services/pkg/config"
+	"kei-services/pkg/infra/kafka"
 	"kei-services/pkg/infra/postgres"
 	"kei-services/pkg/infra/redis"
-	"log"
 	"os"
 	"strings"
 
-	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
@@ -24,6 +23,7 @@ func Load(path string) (*Config, error) {
 	// env bindings
 	config.BindSsl(v)
 	redis.BindEnv(v)
+	kafka.BindEnv(v, "KAFKA", "kafkaWriter")
 	postgres.BindPostgresDb(v, "PGDB", "postgres")
 
 	if err := v.ReadInConfig(); err != nil {
@@ -42,15 +42,12 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("unmarshal config: %w", err)
 	}
 
-	return &cfg, nil
-}
+	// the admin bearer token is read from env only
+	if s := os.Getenv("ADMIN_TOKEN"); s != "" && cfg.Admin != nil {
+		cfg.Admin.Token = s
+	}
 
-func HotReload(path string, cfg *Config) {
-	v := viper.New()
-	v.WatchConfig()
-	v.OnConfigChange(func(e fsnotify.Event) {
-		log.Println("Config file changed:", e.Name)
-	})
+	return &cfg, nil
 }
 
 func splitCSV(s string) []string {
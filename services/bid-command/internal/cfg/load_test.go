@@ -0,0 +1,36 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_AdminToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path,
+		[]byte(`{"Admin": {"isEnabled": true, "token": "token-from-the-config-file"}}`), 0o600))
+
+	tests := []struct {
+		name string
+		env  string
+		want string
+	}{
+		{name: "ignored in the config file", env: "", want: ""},
+		{name: "read from ADMIN_TOKEN", env: "token-from-the-environment", want: "token-from-the-environment"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ADMIN_TOKEN", tt.env)
+
+			c, err := Load(path)
+
+			require.NoError(t, err)
+			require.NotNil(t, c.Admin)
+			assert.Equal(t, tt.want, c.Admin.Token)
+		})
+	}
+}
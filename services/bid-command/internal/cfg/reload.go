@@ -0,0 +1,244 @@
+package cfg
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// Reloader holds the running config and applies changes to the runtime-tunable settings, Logger.Level,
+// Cors.AllowOrigins, RateLimit, Features and Events.PublishTimeoutMs, when the config file changes.
+// Changes to anything else need a restart and are ignored with a warning
+type Reloader struct {
+	path    string
+	mu      sync.Mutex // serialises reloads with Subscribe
+	current atomic.Pointer[Config]
+	subs    []func(*Config)
+}
+
+// NewReloader loads path, the loaded config is Current until a reload applies
+func NewReloader(path string) (*Reloader, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Reloader{path: path}
+	r.current.Store(c)
+	return r, nil
+}
+
+func (r *Reloader) Current() *Config { return r.current.Load() }
+
+// Subscribe calls fn with every config a reload applies. Subscribers run in order before Reload returns,
+// each should swap its own state in one step
+func (r *Reloader) Subscribe(fn func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs = append(r.subs, fn)
+}
+
+// Watch reloads after writes to the config file until ctx is done. The directory is watched, so files
+// replaced by editors or Kubernetes ConfigMaps are seen too
+func (r *Reloader) Watch(ctx context.Context, log *zap.Logger) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watch config: %w", err)
+	}
+	defer w.Close()
+
+	if err = w.Add(filepath.Dir(r.path)); err != nil {
+		return fmt.Errorf("watch config dir: %w", err)
+	}
+
+	target := filepath.Clean(r.path)
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			// ConfigMaps swap the ..data symlink rather than writing the file
+			if filepath.Clean(e.Name) != target && filepath.Base(e.Name) != "..data" {
+				continue
+			}
+			if e.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				debounce = time.After(250 * time.Millisecond) // editors write in several steps
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warn("watch config", zap.Error(err))
+		case <-debounce:
+			debounce = nil
+			if err := r.Reload(log); err != nil {
+				log.Warn("config reload rejected, keeping the running config", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Reload reads the config file and applies its runtime-tunable settings. Nothing is applied when the
+// file doesn't load or fails Validate
+func (r *Reloader) Reload(log *zap.Logger) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := Load(r.path)
+	if err != nil {
+		return err
+	}
+	if err = next.Validate(); err != nil {
+		return err
+	}
+
+	cur := r.Current()
+	if restart := restartOnly(cur, next); len(restart) > 0 {
+		log.Warn("config changes need a restart, ignored", zap.Strings("sections", restart))
+	}
+
+	applied := withRuntime(cur, next)
+	changes := runtimeDiff(cur, applied)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	r.current.Store(applied)
+	for _, fn := range r.subs {
+		fn(applied)
+	}
+	log.Info("config reloaded", zap.Strings("changes", changes))
+	return nil
+}
+
+// withRuntime copies cur with the runtime-tunable settings of next
+func withRuntime(cur, next *Config) *Config {
+	out := *cur
+	if cur.Logger != nil && next.Logger != nil {
+		l := *cur.Logger
+		l.Level = next.Logger.Level
+		out.Logger = &l
+	}
+	if cur.Cors != nil && next.Cors != nil {
+		c := *cur.Cors
+		c.AllowOrigins = next.Cors.AllowOrigins
+		out.Cors = &c
+	}
+	out.RateLimit = next.RateLimit
+	out.Features = next.Features
+	if next.Events != nil {
+		e := EventsConfig{}
+		if cur.Events != nil {
+			e = *cur.Events
+		}
+		e.PublishTimeoutMs = next.Events.PublishTimeoutMs
+		out.Events = &e
+	}
+	return &out
+}
+
+// restartOnly lists the sections of next that differ from cur in more than runtime-tunable settings
+func restartOnly(cur, next *Config) []string {
+	a, b := reflect.ValueOf(frozen(cur)), reflect.ValueOf(frozen(next))
+	var out []string
+	for i := range a.NumField() {
+		if !reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			out = append(out, a.Type().Field(i).Name)
+		}
+	}
+	return out
+}
+
+// frozen is c without its runtime-tunable settings and what main sets in code
+func frozen(c *Config) Config {
+	out := *c
+	if c.Logger != nil {
+		l := *c.Logger
+		l.Level = ""
+		out.Logger = &l
+	}
+	if c.Cors != nil {
+		co := *c.Cors
+		co.AllowOrigins = nil
+		out.Cors = &co
+	}
+	out.RateLimit, out.Features = nil, nil
+	if c.Events != nil {
+		e := *c.Events
+		e.PublishTimeoutMs = 0
+		out.Events = &e
+	}
+	if c.KafkaWriter != nil {
+		w := *c.KafkaWriter
+		w.Metrics = nil
+		out.KafkaWriter = &w
+	}
+	return out
+}
+
+// runtimeDiff describes the runtime-tunable settings changed between a and b, as "setting: old -> new"
+func runtimeDiff(a, b *Config) []string {
+	var out []string
+	diff := func(name string, before, after any) {
+		if !reflect.DeepEqual(before, after) {
+			out = append(out, fmt.Sprintf("%s: %v -> %v", name, before, after))
+		}
+	}
+
+	var la, lb string
+	if a.Logger != nil && b.Logger != nil {
+		la, lb = a.Logger.Level, b.Logger.Level
+	}
+	diff("Logger.Level", la, lb)
+
+	var ca, cb []string
+	if a.Cors != nil && b.Cors != nil {
+		ca, cb = a.Cors.AllowOrigins, b.Cors.AllowOrigins
+	}
+	diff("Cors.AllowOrigins", ca, cb)
+
+	rate := func(c *Config) any {
+		if c.RateLimit == nil {
+			return "none"
+		}
+		return fmt.Sprintf("%+v", *c.RateLimit)
+	}
+	diff("RateLimit", rate(a), rate(b))
+
+	names := make([]string, 0, len(a.Features)+len(b.Features))
+	for name := range a.Features {
+		names = append(names, name)
+	}
+	for name := range b.Features {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range slices.Compact(names) {
+		before, okA := a.Features[name]
+		after, okB := b.Features[name]
+		if okA != okB || before != after {
+			out = append(out, fmt.Sprintf("Features.%s: %s -> %s", name, toggle(before, okA), toggle(after, okB)))
+		}
+	}
+
+	diff("Events.PublishTimeout", a.Events.PublishTimeout(), b.Events.PublishTimeout())
+	return out
+}
+
+func toggle(v, ok bool) string {
+	if !ok {
+		return "unset"
+	}
+	return fmt.Sprint(v)
+}
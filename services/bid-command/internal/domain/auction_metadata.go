@@ -18,7 +18,10 @@ type AuctionMetadata struct {
 	StartingPrice float64       `json:"startingPrice"` // min starting price
 	CurrentPrice  float64       `json:"currentPrice"`  // last accepted price, 0 if none
 	MinIncrement  float64       `json:"minIncrement"`  // required when >= CurrentPrice
-	Version       int           `json:"version"`
+	Version       int           `json:"version"`       // latest bid seq, the auction version until the first bid
+
+	// AuctionVersion orders auction.opened/closed for auction-projector, kept as is when bid-command writes
+	AuctionVersion int `json:"auctionVersion"`
 }
 
 func (m AuctionMetadata) IsOpen() bool {
@@ -34,3 +37,43 @@ func (m AuctionMetadata) MinNextBid() float64 {
 
 	return m.CurrentPrice + m.MinIncrement
 }
+
+// MetadataFromEvents rebuilds an auction's metadata from its latest auction.opened and auction.closed the
+// way auction-projector projects them, nil without either. A close older than the open is ignored
+func MetadataFromEvents(opened *AuctionOpened, closed *AuctionClosed) *AuctionMetadata {
+	if closed != nil && opened != nil && closed.Version < opened.Version {
+		closed = nil
+	}
+
+	m := &AuctionMetadata{}
+	switch {
+	case opened != nil:
+		m.AuctionID = opened.AuctionID
+		m.Status = AuctionOpen
+		m.EndsAt = opened.EndsAt.UTC()
+		m.StartingPrice = opened.StartingPrice
+		m.MinIncrement = opened.MinIncrement
+		m.Version, m.AuctionVersion = opened.Version, opened.Version
+	case closed != nil:
+		m.AuctionID = closed.AuctionID
+	default:
+		return nil
+	}
+	if closed != nil {
+		m.Status = AuctionClose
+		m.Version, m.AuctionVersion = max(m.Version, closed.Version), closed.Version
+	}
+	return m
+}
+
+// WithLatestBid returns m with the current price and version of the latest bid in the bids table,
+// authoritative over the cache. The version stays the auction's until the first bid
+func (m AuctionMetadata) WithLatestBid(latest *LatestBid) AuctionMetadata {
+	if latest == nil {
+		m.CurrentPrice, m.Version = 0, m.AuctionVersion
+		return m
+	}
+	m.CurrentPrice = latest.Amount
+	m.Version = max(int(latest.Seq), m.AuctionVersion)
+	return m
+}
@@ -85,3 +85,60 @@ func TestAuctionMetadata_MinNextBid(t *testing.T) {
 		})
 	}
 }
+
+func TestMetadataFromEvents(t *testing.T) {
+	endsAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	opened := &AuctionOpened{AuctionID: "auction-1", EndsAt: endsAt, StartingPrice: 100, MinIncrement: 5, Version: 2}
+
+	t.Run("no events", func(t *testing.T) {
+		assert.Nil(t, MetadataFromEvents(nil, nil))
+	})
+
+	t.Run("opened", func(t *testing.T) {
+		m := MetadataFromEvents(opened, nil)
+		assert.Equal(t, &AuctionMetadata{
+			AuctionID:      "auction-1",
+			Status:         AuctionOpen,
+			EndsAt:         endsAt,
+			StartingPrice:  100,
+			MinIncrement:   5,
+			Version:        2,
+			AuctionVersion: 2,
+		}, m)
+	})
+
+	t.Run("closed after opened", func(t *testing.T) {
+		m := MetadataFromEvents(opened, &AuctionClosed{AuctionID: "auction-1", Version: 3})
+		assert.Equal(t, AuctionClose, m.Status)
+		assert.Equal(t, 3, m.AuctionVersion)
+		assert.Equal(t, 100.0, m.StartingPrice)
+	})
+
+	t.Run("close older than the open is ignored", func(t *testing.T) {
+		m := MetadataFromEvents(opened, &AuctionClosed{AuctionID: "auction-1", Version: 1})
+		assert.True(t, m.IsOpen())
+		assert.Equal(t, 2, m.AuctionVersion)
+	})
+
+	t.Run("closed without opened", func(t *testing.T) {
+		m := MetadataFromEvents(nil, &AuctionClosed{AuctionID: "auction-1", Version: 4})
+		assert.Equal(t, &AuctionMetadata{AuctionID: "auction-1", Status: AuctionClose, Version: 4, AuctionVersion: 4}, m)
+	})
+}
+
+func TestAuctionMetadata_WithLatestBid(t *testing.T) {
+	m := AuctionMetadata{AuctionID: "auction-1", Status: AuctionOpen, CurrentPrice: 150, Version: 42, AuctionVersion: 2}
+
+	t.Run("latest bid is authoritative", func(t *testing.T) {
+		got := m.WithLatestBid(&LatestBid{Amount: 120, Seq: 40})
+		assert.Equal(t, 120.0, got.CurrentPrice)
+		assert.Equal(t, 40, got.Version)
+		assert.Equal(t, 2, got.AuctionVersion)
+	})
+
+	t.Run("no bids resets to the auction version", func(t *testing.T) {
+		got := m.WithLatestBid(nil)
+		assert.Equal(t, 0.0, got.CurrentPrice)
+		assert.Equal(t, 2, got.Version)
+	})
+}
@@ -5,6 +5,17 @@ import "errors"
 var (
 	ErrAuctionClosed     = errors.New("auction_closed")
 	ErrAuctionNotFound   = errors.New("auction_not_found")
+	ErrAuctionNotCached  = errors.New("auction_metadata_not_found") // no metadata in the cache
 	ErrBelowMinIncrement = errors.New("below_min_increment")
 	ErrInvalidAmount     = errors.New("invalid_amount")
+	ErrMetadataChanged   = errors.New("auction_metadata_changed") // cache moved past an admin write, not written
+)
+
+// Circuit breaker outcomes, see the policies in place_bid
+var (
+	// ErrMetadataStale comes with the last metadata read while the cache is unavailable, its current price may
+	// be behind
+	ErrMetadataStale       = errors.New("auction_metadata_stale")
+	ErrMetadataUnavailable = errors.New("auction_metadata_unavailable") // cache unavailable, nothing to fall back to
+	ErrPublishUnavailable  = errors.New("publish_unavailable")          // bids.placed can't be published
 )
@@ -9,6 +9,7 @@ type BidPlaced struct {
 	BidderID  string    `json:"bidderId"`
 	Amount    float64   `json:"amount"`
 	At        time.Time `json:"at"`
+	Seq       int64     `json:"seq,omitempty"` // bids table sequence, orders bids of one auction
 }
 
 // AuctionOpened is a domain event emitted by the auction service when an auction is opened
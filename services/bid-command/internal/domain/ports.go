@@ -6,10 +6,11 @@ import (
 )
 
 type LatestBid struct {
-	ID     string
-	Amount float64
-	Seq    int64 // monotonic sequence
-	At     time.Time
+	ID       string
+	BidderID string // only set by IBidReader
+	Amount   float64
+	Seq      int64 // monotonic sequence
+	At       time.Time
 }
 
 type IBidRepository interface {
@@ -21,10 +22,36 @@ type IAuctionMetadataStore interface {
 	Get(ctx context.Context, auctionID string) (*AuctionMetadata, error)
 }
 
+// IBidReader reads bids without locking, for inspection
+type IBidReader interface {
+	Latest(ctx context.Context, auctionID string) (*LatestBid, error)
+}
+
+// IAuctionMetadataCache is the store behind IAuctionMetadataStore, inspected and repaired by the admin API
+type IAuctionMetadataCache interface {
+	IAuctionMetadataStore
+	Key(auctionID string) string
+	TTL(ctx context.Context, auctionID string) (time.Duration, error) // negative without expiry
+	// Set and Replace return ErrMetadataChanged instead of overwriting a newer auction or bid version
+	Set(ctx context.Context, auctionID string, meta AuctionMetadata, ttl time.Duration) error
+	Replace(ctx context.Context, auctionID string, meta AuctionMetadata) error // keeps the TTL
+	Delete(ctx context.Context, auctionID string) error
+}
+
+// IAuctionEventSource finds an auction's latest events in the retained auction topics, nil when none is left
+type IAuctionEventSource interface {
+	LatestEvents(ctx context.Context, auctionID string) (*AuctionOpened, *AuctionClosed, error)
+}
+
 type IBidsPlacedPublisher interface {
 	Publish(ctx context.Context, evt BidPlaced) error
 }
 
+// IAvailability is implemented by adapters behind a circuit breaker, false unless it is closed
+type IAvailability interface {
+	Available() bool
+}
+
 type IClock interface {
 	Now() time.Time
 }
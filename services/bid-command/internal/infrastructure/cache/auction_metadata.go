@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	redisInfra "kei-services/pkg/infra/redis"
 	"kei-services/services/bid-command/internal/domain"
 	"time"
 
@@ -11,15 +12,20 @@ import (
 	"go.uber.org/zap"
 )
 
-var _ domain.IAuctionMetadataStore = (*AuctionMetadataCache)(nil)
+var _ domain.IAuctionMetadataCache = (*AuctionMetadataCache)(nil)
+
+// ActivePrefixKey is where auction-projector records the key prefix currently served, rebuilds
+// switch it once the new prefix caught up
+const ActivePrefixKey = "auction-meta:active-prefix"
 
 type AuctionMetadataCache struct {
 	CacheKey string
-	R        *redis.Client
+	Prefix   redisInfra.PrefixSource // optional, overrides CacheKey to follow projection rebuilds
+	R        redis.UniversalClient
 	Log      *zap.Logger
 }
 
-func NewAuctionMetadataCache(r *redis.Client, log *zap.Logger) *AuctionMetadataCache {
+func NewAuctionMetadataCache(r redis.UniversalClient, log *zap.Logger) *AuctionMetadataCache {
 	return &AuctionMetadataCache{
 		CacheKey: "auction:",
 		R:        r,
@@ -27,11 +33,19 @@ func NewAuctionMetadataCache(r *redis.Client, log *zap.Logger) *AuctionMetadataC
 	}
 }
 
+// Key is the Redis key of auction id under the active prefix
+func (c AuctionMetadataCache) Key(id string) string {
+	if c.Prefix != nil {
+		return c.Prefix.Prefix() + id
+	}
+	return c.CacheKey + id
+}
+
 // Get returns auction metadata from cache
 func (c AuctionMetadataCache) Get(ctx context.Context, auctionId string) (*domain.AuctionMetadata, error) {
-	raw, err := c.R.Get(ctx, c.CacheKey+auctionId).Bytes()
+	raw, err := c.R.Get(ctx, c.Key(auctionId)).Bytes()
 	if errors.Is(err, redis.Nil) {
-		return nil, errors.New("auction_metadata_not_found")
+		return nil, domain.ErrAuctionNotCached
 	}
 	if err != nil {
 		return nil, err
@@ -45,17 +59,89 @@ func (c AuctionMetadataCache) Get(ctx context.Context, auctionId string) (*domai
 	return &meta, nil
 }
 
-// Set stores auction metadata in cache with a TTL.
-// TTL should be set according to auction duration + buffer
+// Set stores auction metadata in cache with a TTL, unless the cached auction or bid version is newer, see
+// setIfNotNewerLua. TTL should be set according to auction duration + buffer
 func (c AuctionMetadataCache) Set(ctx context.Context, id string, auction domain.AuctionMetadata, ttl time.Duration) error {
+	return c.setIfNotNewer(ctx, id, auction, max(int(ttl/time.Second), 1))
+}
+
+func (c AuctionMetadataCache) Delete(ctx context.Context, id string) error {
+	return c.write(ctx, id, func(p redis.Pipeliner, key string) { p.Del(ctx, key) })
+}
+
+// Replace overwrites the cached metadata of id, keeping the key's TTL, unless it is gone or its auction or
+// bid version is newer, see setIfNotNewerLua
+func (c AuctionMetadataCache) Replace(ctx context.Context, id string, auction domain.AuctionMetadata) error {
+	return c.setIfNotNewer(ctx, id, auction, 0)
+}
+
+// setIfNotNewer writes auction unless auction-projector or a bid moved the cached value past it since it was
+// read, ttlSec 0 keeps the TTL and requires the key to exist
+func (c AuctionMetadataCache) setIfNotNewer(ctx context.Context, id string, auction domain.AuctionMetadata,
+	ttlSec int) error {
 	b, err := json.Marshal(auction)
 	if err != nil {
 		return err
 	}
 
-	return c.R.Set(ctx, c.CacheKey+id, b, ttl).Err()
+	set, err := setIfNotNewerLua.Run(ctx, c.R, []string{c.Key(id)}, string(b), auction.AuctionVersion,
+		auction.Version, ttlSec, InvalidateChannel).Int()
+	if err != nil {
+		return err
+	}
+	if set == 0 {
+		return domain.ErrMetadataChanged
+	}
+	return nil
 }
 
-func (c AuctionMetadataCache) Delete(ctx context.Context, id string) error {
-	return c.R.Del(ctx, c.CacheKey+id).Err()
+// setIfNotNewerLua sets the key unless the cached auctionVersion or version is greater than the new one, like
+// auction-projector's setIfNewerLua, and publishes the key once set. Values that aren't valid json are
+// overwritten, repairing them is what admin writes are for
+var setIfNotNewerLua = redis.NewScript(`
+local key = KEYS[1]
+local value = ARGV[1]
+local auctionVersion = tonumber(ARGV[2])
+local version = tonumber(ARGV[3])
+local ttlsec = tonumber(ARGV[4])
+local channel = ARGV[5]
+
+local cur = redis.call('GET', key)
+if cur then
+  local ok, obj = pcall(cjson.decode, cur)
+  if ok then
+    local curAuctionVersion = tonumber(obj['auctionVersion'] or obj['version']) or 0
+    local curVersion = tonumber(obj['version']) or 0
+    if curAuctionVersion > auctionVersion or curVersion > version then
+      return 0
+    end
+  end
+elseif ttlsec == 0 then
+  return 0
+end
+
+if ttlsec > 0 then
+  redis.call('SET', key, value, 'EX', ttlsec)
+else
+  redis.call('SET', key, value, 'KEEPTTL')
+end
+redis.call('PUBLISH', channel, key)
+return 1
+`)
+
+// write runs cmd on the key of id and publishes the key on InvalidateChannel in the same transaction, so
+// L1 caches drop what they hold
+func (c AuctionMetadataCache) write(ctx context.Context, id string, cmd func(p redis.Pipeliner, key string)) error {
+	key := c.Key(id)
+	_, err := c.R.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		cmd(p, key)
+		p.Publish(ctx, InvalidateChannel, key)
+		return nil
+	})
+	return err
+}
+
+// TTL returns the time left before the metadata of id expires, negative when it doesn't expire or is missing
+func (c AuctionMetadataCache) TTL(ctx context.Context, id string) (time.Duration, error) {
+	return c.R.TTL(ctx, c.Key(id)).Result()
 }
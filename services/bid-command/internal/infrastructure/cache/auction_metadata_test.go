@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"kei-services/services/bid-command/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAuctionMetadataCache_AdminWrites(t *testing.T) {
+	ctx := context.Background()
+	meta := domain.AuctionMetadata{AuctionID: "a1", Status: domain.AuctionOpen, CurrentPrice: 110, Version: 11,
+		AuctionVersion: 1}
+	raw, err := json.Marshal(meta)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		write   func(c *AuctionMetadataCache) error
+		ttlSec  int
+		set     int64
+		wantErr error
+	}{
+		{"set", func(c *AuctionMetadataCache) error { return c.Set(ctx, "a1", meta, time.Hour) }, 3600, 1, nil},
+		{"set behind the cache", func(c *AuctionMetadataCache) error { return c.Set(ctx, "a1", meta, time.Hour) },
+			3600, 0, domain.ErrMetadataChanged},
+		{"replace keeps the ttl", func(c *AuctionMetadataCache) error { return c.Replace(ctx, "a1", meta) }, 0, 1, nil},
+		{"replace behind the cache", func(c *AuctionMetadataCache) error { return c.Replace(ctx, "a1", meta) },
+			0, 0, domain.ErrMetadataChanged},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, r := redismock.NewClientMock()
+			r.ExpectEvalSha(setIfNotNewerLua.Hash(), []string{"auction:a1"}, string(raw), 1, 11, tt.ttlSec,
+				InvalidateChannel).SetVal(tt.set)
+
+			err := tt.write(NewAuctionMetadataCache(db, zap.NewNop()))
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, r.ExpectationsWereMet())
+		})
+	}
+}
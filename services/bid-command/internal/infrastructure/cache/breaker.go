@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"kei-services/pkg/breaker"
+	"kei-services/services/bid-command/internal/domain"
+	"sync"
+	"time"
+)
+
+var _ domain.IAuctionMetadataStore = (*BreakerStore)(nil)
+
+// BreakerStore reads metadata through a circuit breaker. While the breaker is open, or a read fails, it
+// falls back to the last metadata it read for the auction with domain.ErrMetadataStale, without one read
+// within maxAge it fails with domain.ErrMetadataUnavailable. Cache misses don't count as failures
+type BreakerStore struct {
+	next domain.IAuctionMetadataStore
+	b    *breaker.Breaker
+
+	mu        sync.Mutex
+	lastKnown map[string]knownMetadata
+	maxKnown  int
+	maxAge    time.Duration
+	now       func() time.Time
+}
+
+type knownMetadata struct {
+	meta   domain.AuctionMetadata
+	readAt time.Time
+}
+
+// NewBreakerStore keeps the last read metadata of up to maxKnown auctions for the fallback, for maxAge
+func NewBreakerStore(next domain.IAuctionMetadataStore, b *breaker.Breaker, maxKnown int,
+	maxAge time.Duration) *BreakerStore {
+	return &BreakerStore{
+		next:      next,
+		b:         b,
+		lastKnown: make(map[string]knownMetadata),
+		maxKnown:  maxKnown,
+		maxAge:    maxAge,
+		now:       time.Now,
+	}
+}
+
+// IsFailure tells the breaker which store errors count against Redis
+func IsFailure(err error) bool {
+	return !errors.Is(err, domain.ErrAuctionNotCached) && !errors.Is(err, context.Canceled)
+}
+
+func (s *BreakerStore) Get(ctx context.Context, auctionID string) (*domain.AuctionMetadata, error) {
+	var meta *domain.AuctionMetadata
+	err := s.b.Do(ctx, func(ctx context.Context) error {
+		var err error
+		meta, err = s.next.Get(ctx, auctionID)
+		return err
+	})
+	switch {
+	case err == nil:
+		s.remember(*meta)
+		return meta, nil
+	case !IsFailure(err):
+		if errors.Is(err, domain.ErrAuctionNotCached) {
+			s.forget(auctionID)
+		}
+		return nil, err
+	}
+
+	last, ok := s.known(auctionID)
+	if !ok {
+		return nil, errors.Join(domain.ErrMetadataUnavailable, err)
+	}
+	return &last, errors.Join(domain.ErrMetadataStale, err)
+}
+
+// known returns the last metadata read for auctionID unless it is older than maxAge
+func (s *BreakerStore) known(auctionID string) (domain.AuctionMetadata, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.lastKnown[auctionID]
+	if !ok {
+		return domain.AuctionMetadata{}, false
+	}
+	if s.now().Sub(k.readAt) > s.maxAge {
+		delete(s.lastKnown, auctionID)
+		return domain.AuctionMetadata{}, false
+	}
+	return k.meta, true
+}
+
+func (s *BreakerStore) remember(m domain.AuctionMetadata) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.lastKnown[m.AuctionID]; !ok && len(s.lastKnown) >= s.maxKnown {
+		for id := range s.lastKnown { // drop any, auctions read recently are read again soon
+			delete(s.lastKnown, id)
+			break
+		}
+	}
+	s.lastKnown[m.AuctionID] = knownMetadata{meta: m, readAt: s.now()}
+}
+
+func (s *BreakerStore) forget(auctionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.lastKnown, auctionID)
+}
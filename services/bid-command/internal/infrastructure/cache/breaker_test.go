@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"kei-services/services/bid-command/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) Get(ctx context.Context, auctionID string) (*domain.AuctionMetadata, error) {
+	args := m.Called(ctx, auctionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AuctionMetadata), args.Error(1)
+}
+
+func TestBreakerStore_Fallback(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	meta := &domain.AuctionMetadata{AuctionID: "a1", Status: domain.AuctionOpen, CurrentPrice: 100}
+	down := errors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		readAgo time.Duration // since the last successful read, 0 for none
+		wantErr error
+	}{
+		{"never read", 0, domain.ErrMetadataUnavailable},
+		{"read recently", 30 * time.Second, domain.ErrMetadataStale},
+		{"read too long ago", 2 * time.Minute, domain.ErrMetadataUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := new(MockStore)
+			// a nil breaker calls straight through, failures fall back all the same
+			s := NewBreakerStore(next, nil, 10, time.Minute)
+			if tt.readAgo > 0 {
+				s.now = func() time.Time { return now.Add(-tt.readAgo) }
+				next.On("Get", ctx, "a1").Return(meta, nil).Once()
+				_, err := s.Get(ctx, "a1")
+				assert.NoError(t, err)
+			}
+			s.now = func() time.Time { return now }
+			next.On("Get", ctx, "a1").Return(nil, down)
+
+			got, err := s.Get(ctx, "a1")
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.ErrorIs(t, err, down)
+			if tt.wantErr == domain.ErrMetadataStale {
+				assert.Equal(t, meta, got)
+			} else {
+				assert.Nil(t, got)
+			}
+		})
+	}
+}
+
+func TestBreakerStore_MissForgets(t *testing.T) {
+	ctx := context.Background()
+	next := new(MockStore)
+	s := NewBreakerStore(next, nil, 10, time.Minute)
+
+	next.On("Get", ctx, "a1").Return(&domain.AuctionMetadata{AuctionID: "a1"}, nil).Once()
+	next.On("Get", ctx, "a1").Return(nil, domain.ErrAuctionNotCached).Once()
+	next.On("Get", ctx, "a1").Return(nil, errors.New("connection refused")).Once()
+
+	_, err := s.Get(ctx, "a1")
+	assert.NoError(t, err)
+	_, err = s.Get(ctx, "a1")
+	assert.ErrorIs(t, err, domain.ErrAuctionNotCached)
+	_, err = s.Get(ctx, "a1")
+	assert.ErrorIs(t, err, domain.ErrMetadataUnavailable)
+}
@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"kei-services/pkg/lru"
+	"kei-services/services/bid-command/internal/domain"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// InvalidateChannel carries the Redis key of auction metadata after each write, auction-projector publishes
+// it in the same script as the write
+const InvalidateChannel = "auction-meta:invalidate"
+
+// l1HealthCheck is how long the invalidation subscription may stay silent before it is pinged
+const l1HealthCheck = 15 * time.Second
+
+var _ domain.IAuctionMetadataStore = (*L1Store)(nil)
+
+// L1Store keeps auction metadata in memory for a short TTL in front of next, dropping an entry when its key
+// is published on InvalidateChannel. Entries are only served while the subscription is up, invalidations
+// missed while it was down can't be told apart.
+//
+// Pub/sub is asynchronous, an entry may still be served after Redis changed until its invalidation arrives,
+// at most for ttl. Open auctions are never cached past their EndsAt, so the usual close is read from Redis,
+// only an auction closed early can be served as open for that window
+type L1Store struct {
+	next     domain.IAuctionMetadataStore
+	key      func(auctionID string) string // the Redis key, so a prefix switch misses instead of serving old keys
+	entries  *lru.Cache[string, domain.AuctionMetadata]
+	ttl      time.Duration
+	requests *prometheus.CounterVec // labels: result
+	log      *zap.Logger
+
+	mu      sync.Mutex
+	live    bool                 // subscribed, invalidations are arriving
+	epoch   uint64               // bumped when the subscription changes, no load in flight is kept
+	loading map[string]*keyLoads // keys with loads in flight, a load that raced its key's invalidation isn't kept
+}
+
+// keyLoads tracks the loads in flight for one key, dropped once the last one returns so the map stays
+// bounded by concurrent misses
+type keyLoads struct {
+	gen   uint64 // bumped by invalidations of the key
+	count int
+}
+
+func NewL1Store(next domain.IAuctionMetadataStore, key func(string) string, size int, ttl time.Duration,
+	requests *prometheus.CounterVec, log *zap.Logger) *L1Store {
+	return &L1Store{
+		next:     next,
+		key:      key,
+		entries:  lru.New[string, domain.AuctionMetadata](size),
+		ttl:      ttl,
+		requests: requests,
+		log:      log,
+		loading:  make(map[string]*keyLoads),
+	}
+}
+
+func (s *L1Store) Get(ctx context.Context, auctionID string) (*domain.AuctionMetadata, error) {
+	key := s.key(auctionID)
+
+	s.mu.Lock()
+	live := s.live
+	s.mu.Unlock()
+	if !live {
+		s.observe("bypass")
+		return s.next.Get(ctx, auctionID)
+	}
+
+	if meta, ok := s.entries.Get(key); ok {
+		s.observe("hit")
+		return &meta, nil
+	}
+	s.observe("miss")
+
+	loads, epoch, gen := s.startLoad(key)
+	meta, err := s.next.Get(ctx, auctionID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if loads.count--; loads.count == 0 {
+		delete(s.loading, key)
+	}
+	if err != nil {
+		return meta, err
+	}
+	if s.live && s.epoch == epoch && loads.gen == gen {
+		expiresAt := time.Now().Add(s.ttl)
+		if !meta.EndsAt.IsZero() && meta.IsOpen() && meta.EndsAt.Before(expiresAt) {
+			expiresAt = meta.EndsAt // the auction may close from then on, read it from Redis
+		}
+		s.entries.Add(key, *meta, expiresAt)
+	}
+	return meta, nil
+}
+
+// startLoad registers a load of key, the returned epoch and gen tell whether it raced an invalidation
+func (s *L1Store) startLoad(key string) (*keyLoads, uint64, uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	loads, ok := s.loading[key]
+	if !ok {
+		loads = &keyLoads{}
+		s.loading[key] = loads
+	}
+	loads.count++
+	return loads, s.epoch, loads.gen
+}
+
+// Listen subscribes to InvalidateChannel until ctx is done, resubscribing after connection errors. The
+// cache starts serving once subscribed and is purged whenever the subscription is (re)established or lost
+func (s *L1Store) Listen(ctx context.Context, r redis.UniversalClient) {
+	ps := r.Subscribe(ctx, InvalidateChannel)
+	defer func() { _ = ps.Close() }()
+	defer s.setLive(false)
+
+	for {
+		msg, err := ps.ReceiveTimeout(ctx, l1HealthCheck)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				err = ps.Ping(ctx) // the pong or a reconnect comes back through ReceiveTimeout
+			}
+			if err != nil {
+				if s.setLive(false) {
+					s.log.Warn("auction metadata invalidations lost, bypassing L1 cache", zap.Error(err))
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Second):
+				}
+			}
+			continue
+		}
+
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind == "subscribe" {
+				s.setLive(true)
+				s.log.Info("auction metadata L1 cache serving", zap.String("channel", m.Channel))
+			}
+		case *redis.Message:
+			s.invalidate(m.Payload)
+		}
+	}
+}
+
+func (s *L1Store) invalidate(key string) {
+	s.mu.Lock()
+	if loads, ok := s.loading[key]; ok {
+		loads.gen++
+	}
+	s.mu.Unlock()
+	if s.entries.Remove(key) {
+		s.log.Debug("auction metadata invalidated", zap.String("key", key))
+	}
+}
+
+// setLive purges the entries, a new subscription can't vouch for them either. Reports whether live changed
+func (s *L1Store) setLive(live bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries.Purge()
+	s.epoch++
+	changed := s.live != live
+	s.live = live
+	return changed
+}
+
+func (s *L1Store) observe(result string) {
+	if s.requests != nil {
+		s.requests.WithLabelValues(result).Inc()
+	}
+}
@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"context"
+	"kei-services/services/bid-command/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func newL1(next *MockStore, ttl time.Duration) *L1Store {
+	return NewL1Store(next, func(id string) string { return "auction:" + id }, 10, ttl, nil, zap.NewNop())
+}
+
+func TestL1Store_Get(t *testing.T) {
+	ctx := context.Background()
+	open := &domain.AuctionMetadata{AuctionID: "a1", Status: domain.AuctionOpen, EndsAt: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name      string
+		live      bool
+		before    func(s *L1Store) // between the two reads
+		wantCalls int
+	}{
+		{name: "bypassed while not subscribed", live: false, wantCalls: 2},
+		{name: "served from memory while subscribed", live: true, wantCalls: 1},
+		{
+			name:      "invalidated key is read again",
+			live:      true,
+			before:    func(s *L1Store) { s.invalidate("auction:a1") },
+			wantCalls: 2,
+		},
+		{
+			name:      "other keys' invalidations don't drop it",
+			live:      true,
+			before:    func(s *L1Store) { s.invalidate("auction:a2") },
+			wantCalls: 1,
+		},
+		{
+			name:      "purged on resubscribe",
+			live:      true,
+			before:    func(s *L1Store) { s.setLive(true) },
+			wantCalls: 2,
+		},
+		{
+			name:      "bypassed once the subscription is lost",
+			live:      true,
+			before:    func(s *L1Store) { s.setLive(false) },
+			wantCalls: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := new(MockStore)
+			next.On("Get", ctx, "a1").Return(open, nil)
+			s := newL1(next, time.Minute)
+			if tt.live {
+				s.setLive(true)
+			}
+
+			_, err := s.Get(ctx, "a1")
+			assert.NoError(t, err)
+			if tt.before != nil {
+				tt.before(s)
+			}
+			got, err := s.Get(ctx, "a1")
+
+			assert.NoError(t, err)
+			assert.Equal(t, open, got)
+			next.AssertNumberOfCalls(t, "Get", tt.wantCalls)
+		})
+	}
+}
+
+func TestL1Store_InvalidationDuringLoad(t *testing.T) {
+	ctx := context.Background()
+	next := new(MockStore)
+	s := newL1(next, time.Minute)
+	s.setLive(true)
+
+	// the write is published while the value read before it is on its way back
+	next.On("Get", ctx, "a1").Return(&domain.AuctionMetadata{AuctionID: "a1", Version: 1}, nil).
+		Run(func(mock.Arguments) { s.invalidate("auction:a1") }).Once()
+	next.On("Get", ctx, "a1").Return(&domain.AuctionMetadata{AuctionID: "a1", Version: 2}, nil).Once()
+
+	_, err := s.Get(ctx, "a1")
+	assert.NoError(t, err)
+	assert.Zero(t, s.entries.Len(), "a load that raced an invalidation must not be kept")
+
+	got, err := s.Get(ctx, "a1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, got.Version)
+	next.AssertExpectations(t)
+}
+
+func TestL1Store_OtherKeyInvalidatedDuringLoad(t *testing.T) {
+	ctx := context.Background()
+	next := new(MockStore)
+	s := newL1(next, time.Minute)
+	s.setLive(true)
+
+	// a2 is written while a1 is loading, a1's load is still good
+	next.On("Get", ctx, "a1").Return(&domain.AuctionMetadata{AuctionID: "a1", Version: 1}, nil).
+		Run(func(mock.Arguments) { s.invalidate("auction:a2") }).Once()
+
+	_, err := s.Get(ctx, "a1")
+	assert.NoError(t, err)
+	got, err := s.Get(ctx, "a1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, got.Version)
+	assert.Empty(t, s.loading, "finished loads must not be tracked")
+	next.AssertExpectations(t)
+}
+
+func TestL1Store_ExpiryCappedAtEndsAt(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		meta      *domain.AuctionMetadata
+		wantCalls int
+	}{
+		{
+			name: "open auction ending before the ttl",
+			meta: &domain.AuctionMetadata{AuctionID: "a1", Status: domain.AuctionOpen,
+				EndsAt: time.Now().Add(30 * time.Millisecond)},
+			wantCalls: 2,
+		},
+		{
+			name: "open auction ending after the ttl",
+			meta: &domain.AuctionMetadata{AuctionID: "a1", Status: domain.AuctionOpen,
+				EndsAt: time.Now().Add(time.Hour)},
+			wantCalls: 1,
+		},
+		{
+			name: "closed auction keeps the ttl",
+			meta: &domain.AuctionMetadata{AuctionID: "a1", Status: domain.AuctionClose,
+				EndsAt: time.Now().Add(-time.Hour)},
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := new(MockStore)
+			next.On("Get", ctx, "a1").Return(tt.meta, nil)
+			s := newL1(next, time.Minute)
+			s.setLive(true)
+
+			_, err := s.Get(ctx, "a1")
+			assert.NoError(t, err)
+			time.Sleep(50 * time.Millisecond)
+			_, err = s.Get(ctx, "a1")
+
+			assert.NoError(t, err)
+			next.AssertNumberOfCalls(t, "Get", tt.wantCalls)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"kei-services/pkg/tracing"
 	"kei-services/services/bid-command/internal/domain"
 	"kei-services/services/bid-command/internal/infrastructure/db/tx"
 	sqlc2 "kei-services/services/bid-command/sqlc"
@@ -11,7 +12,10 @@ import (
 	"go.uber.org/zap"
 )
 
-var _ domain.IBidRepository = (*BidRepo)(nil)
+var (
+	_ domain.IBidRepository = (*BidRepo)(nil)
+	_ domain.IBidReader     = (*BidRepo)(nil)
+)
 
 type BidRepo struct {
 	DB  *sql.DB
@@ -22,7 +26,7 @@ type BidRepo struct {
 func NewBidRepo(db *sql.DB, log *zap.Logger) *BidRepo {
 	return &BidRepo{
 		DB:  db,
-		Q:   sqlc2.New(db),
+		Q:   sqlc2.New(tracing.SQL(db, "postgresql")),
 		Log: log,
 	}
 }
@@ -33,7 +37,7 @@ func NewBidRepo(db *sql.DB, log *zap.Logger) *BidRepo {
 func (r *BidRepo) Insert(ctx context.Context, b *domain.Bid) (string, int64, error) {
 	q := r.Q
 	if t, ok := tx.FromCtx(ctx); ok {
-		q = r.Q.WithTx(t)
+		q = sqlc2.New(tracing.SQL(t, "postgresql"))
 	}
 
 	row, err := q.InsertBid(ctx, sqlc2.InsertBidParams{
@@ -51,7 +55,7 @@ func (r *BidRepo) Insert(ctx context.Context, b *domain.Bid) (string, int64, err
 func (r *BidRepo) LatestForUpdate(ctx context.Context, auctionID string) (*domain.LatestBid, error) {
 	q := r.Q
 	if t, ok := tx.FromCtx(ctx); ok {
-		q = r.Q.WithTx(t)
+		q = sqlc2.New(tracing.SQL(t, "postgresql"))
 	}
 
 	res, err := q.LatestForUpdate(ctx, auctionID)
@@ -69,3 +73,22 @@ func (r *BidRepo) LatestForUpdate(ctx context.Context, auctionID string) (*domai
 		At:     res.At,
 	}, nil
 }
+
+// Latest reads the latest bid of auctionID without locking it, nil when there is none
+func (r *BidRepo) Latest(ctx context.Context, auctionID string) (*domain.LatestBid, error) {
+	res, err := r.Q.Latest(ctx, auctionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &domain.LatestBid{
+		ID:       res.ID,
+		BidderID: res.BidderID,
+		Amount:   res.Amount,
+		Seq:      res.Seq,
+		At:       res.At,
+	}, nil
+}
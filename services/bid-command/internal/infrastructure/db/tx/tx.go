@@ -3,7 +3,12 @@ package tx
 import (
 	"context"
 	"database/sql"
+	"kei-services/pkg/tracing"
 	"kei-services/services/bid-command/internal/application"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var _ application.ITxManager = (*TxManager)(nil)
@@ -16,13 +21,27 @@ func NewTxManager(db *sql.DB) TxManager {
 
 type txKey struct{}
 
-// WithinTx executes the given function within a db transaction
+var tracer = tracing.Tracer("kei-services/services/bid-command/db/tx")
+
+// WithinTx executes the given function within a db transaction, traced as one span from begin to commit
 func (m TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
 	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
 		// context already has a transaction, so just call the function
 		return fn(ctx)
 	}
 
+	ctx, span := tracer.Start(ctx, "db.tx", trace.WithAttributes(attribute.String("db.system", "postgresql")))
+	defer span.End()
+
+	err := m.withinTx(ctx, fn)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+func (m TxManager) withinTx(ctx context.Context, fn func(ctx context.Context) error) error {
 	tx, err := m.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
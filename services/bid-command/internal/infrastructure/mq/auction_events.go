@@ -0,0 +1,112 @@
+package mq
+
+import (
+	"context"
+	"kei-services/pkg/eventspb"
+	kafkaInfra "kei-services/pkg/infra/kafka"
+	"kei-services/pkg/schema"
+	"kei-services/services/bid-command/internal/domain"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	TopicAuctionOpened = "auction.opened"
+	TopicAuctionClosed = "auction.closed"
+)
+
+var _ domain.IAuctionEventSource = (*AuctionEvents)(nil)
+
+// auctionSchemas are the auction event versions bid-command understands, kept in step with auction-projector
+var auctionSchemas = func() *schema.Registry {
+	r := schema.NewRegistry()
+	schema.Register[domain.AuctionOpened](r, TopicAuctionOpened, 1, nil)
+	schema.Register[domain.AuctionClosed](r, TopicAuctionClosed, 1, nil)
+	schema.RegisterProto(r, TopicAuctionOpened, func() *eventspb.AuctionOpened { return &eventspb.AuctionOpened{} },
+		func(m *eventspb.AuctionOpened) domain.AuctionOpened {
+			return domain.AuctionOpened{
+				AuctionID:     m.GetAuctionId(),
+				EndsAt:        asTime(m.GetEndsAt()),
+				StartingPrice: m.GetStartingPrice(),
+				MinIncrement:  m.GetMinIncrement(),
+				Currency:      m.GetCurrency(),
+				Version:       int(m.GetVersion()),
+			}
+		})
+	schema.RegisterProto(r, TopicAuctionClosed, func() *eventspb.AuctionClosed { return &eventspb.AuctionClosed{} },
+		func(m *eventspb.AuctionClosed) domain.AuctionClosed {
+			return domain.AuctionClosed{
+				AuctionID: m.GetAuctionId(),
+				ClosedAt:  asTime(m.GetClosedAt()),
+				Version:   int(m.GetVersion()),
+			}
+		})
+	return r
+}()
+
+// AuctionEvents scans the auction topics from their first retained offset, without a consumer group so no
+// committed offset moves. Every partition is read since the auction service's partitioner isn't known
+type AuctionEvents struct {
+	brokers  []string
+	security *kafkaInfra.Security
+	log      *zap.Logger
+}
+
+// NewAuctionEvents dials brokers with sec, the writer's Security so both share credentials
+func NewAuctionEvents(brokers []string, sec *kafkaInfra.Security, log *zap.Logger) *AuctionEvents {
+	return &AuctionEvents{brokers: brokers, security: sec, log: log}
+}
+
+// LatestEvents returns the highest version auction.opened and auction.closed of auctionID still retained
+func (a *AuctionEvents) LatestEvents(ctx context.Context, auctionID string) (*domain.AuctionOpened,
+	*domain.AuctionClosed, error) {
+	end, err := kafkaInfra.EndOffsets(ctx, a.brokers, a.security, []string{TopicAuctionOpened, TopicAuctionClosed})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var opened *domain.AuctionOpened
+	var closed *domain.AuctionClosed
+	var scanned, undecodable int
+	err = kafkaInfra.Replay(ctx, a.brokers, a.security, nil, nil, end, func(msg kafka.Message) error {
+		scanned++
+		evt, err := auctionSchemas.DecodeMessage(msg)
+		if err != nil {
+			undecodable++ // auction-projector dead-letters these, nothing to recover here
+			return nil
+		}
+		switch e := evt.(type) {
+		case domain.AuctionOpened:
+			if e.AuctionID == auctionID && (opened == nil || e.Version >= opened.Version) {
+				opened = &e
+			}
+		case domain.AuctionClosed:
+			if e.AuctionID == auctionID && (closed == nil || e.Version >= closed.Version) {
+				closed = &e
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	a.log.Debug("scanned auction topics",
+		zap.String("auctionId", auctionID),
+		zap.Int("messages", scanned),
+		zap.Int("undecodable", undecodable),
+		zap.Bool("opened", opened != nil),
+		zap.Bool("closed", closed != nil))
+	return opened, closed, nil
+}
+
+// asTime keeps a missing timestamp as the zero time, like a missing JSON field
+func asTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
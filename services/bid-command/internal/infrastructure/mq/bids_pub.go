@@ -2,33 +2,56 @@ package mq
 
 import (
 	"context"
-	"encoding/json"
+	"kei-services/pkg/schema"
+	"kei-services/pkg/tracing"
 	"kei-services/services/bid-command/internal/domain"
+	"sync/atomic"
 	"time"
 
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 var _ domain.IBidsPlacedPublisher = (*BidsPublisher)(nil)
 
+// BidPlacedSchemaVersion is the bids.placed payload version, bump it on breaking changes to domain.BidPlaced
+// after consumers registered an upcaster for the previous one
+const BidPlacedSchemaVersion = "1"
+
+var tracer = tracing.Tracer("kei-services/services/bid-command/mq")
+
 type BidsPublisher struct {
-	Writer *kafka.Writer
-	Topic  string
-	Log    *zap.Logger
+	Writer  *kafka.Writer
+	Topic   string
+	Encoder BidPlacedEncoder
+	Log     *zap.Logger
+
+	timeout *atomic.Int64 // nanoseconds, shared by copies so SetTimeout reaches every one
 }
 
-// NewBidsPublisher requires a multi-topic kafka writer
-func NewBidsPublisher(w *kafka.Writer, log *zap.Logger) BidsPublisher {
-	return BidsPublisher{
-		Writer: w,
-		Topic:  "bids.placed",
-		Log:    log,
+// NewBidsPublisher requires a multi-topic kafka writer, timeout bounds each publish
+func NewBidsPublisher(w *kafka.Writer, enc BidPlacedEncoder, timeout time.Duration, log *zap.Logger) BidsPublisher {
+	p := BidsPublisher{
+		Writer:  w,
+		Topic:   "bids.placed",
+		Encoder: enc,
+		Log:     log,
+		timeout: &atomic.Int64{},
 	}
+	p.SetTimeout(timeout)
+	return p
+}
+
+// SetTimeout changes the publish timeout of p and its copies, for publishes starting after it returns
+func (p BidsPublisher) SetTimeout(d time.Duration) {
+	p.timeout.Store(int64(d))
 }
 
 func (p BidsPublisher) Publish(ctx context.Context, evt domain.BidPlaced) error {
-	payload, err := json.Marshal(evt)
+	payload, err := p.Encoder.Encode(evt)
 	if err != nil {
 		return err
 	}
@@ -37,15 +60,30 @@ func (p BidsPublisher) Publish(ctx context.Context, evt domain.BidPlaced) error
 		Key:   []byte(evt.AuctionID),
 		Value: payload,
 		Headers: []kafka.Header{
-			{Key: "content-type", Value: []byte("application/json")},
-			{Key: "schema", Value: []byte("bids.placed")},
-			{Key: "schema-version", Value: []byte("1")},
+			{Key: schema.HeaderContentType, Value: []byte(p.Encoder.ContentType())},
+			{Key: schema.HeaderSchema, Value: []byte(p.Topic)},
+			{Key: schema.HeaderSchemaVersion, Value: []byte(BidPlacedSchemaVersion)},
 		},
 		Topic: p.Topic,
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	// projectors continue the trace from the traceparent header
+	ctx, span := tracer.Start(ctx, p.Topic+" publish", trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", p.Topic),
+			attribute.String("messaging.kafka.message.key", evt.AuctionID),
+		))
+	defer span.End()
+	tracing.Inject(ctx, &msg)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(p.timeout.Load()))
 	defer cancel()
 
-	return p.Writer.WriteMessages(ctx, msg)
+	if err = p.Writer.WriteMessages(ctx, msg); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
 }
@@ -0,0 +1,69 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"kei-services/pkg/breaker"
+	"kei-services/services/bid-command/internal/domain"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	_ domain.IBidsPlacedPublisher = (*BreakerPublisher)(nil)
+	_ domain.IAvailability        = (*BreakerPublisher)(nil)
+)
+
+// probeTimeout bounds a half-open probe of the Kafka cluster
+const probeTimeout = 5 * time.Second
+
+// BreakerPublisher publishes through a circuit breaker, failing fast with domain.ErrPublishUnavailable while
+// it is open instead of waiting out the publish timeout on every bid
+type BreakerPublisher struct {
+	next  domain.IBidsPlacedPublisher
+	b     *breaker.Breaker
+	probe func(ctx context.Context) error
+
+	probing atomic.Bool
+}
+
+// NewBreakerPublisher probes Kafka with probe while the breaker is half-open, eg a metadata request for the
+// bids.placed topic
+func NewBreakerPublisher(next domain.IBidsPlacedPublisher, b *breaker.Breaker,
+	probe func(ctx context.Context) error) *BreakerPublisher {
+	return &BreakerPublisher{next: next, b: b, probe: probe}
+}
+
+func (p *BreakerPublisher) Publish(ctx context.Context, evt domain.BidPlaced) error {
+	err := p.b.Do(ctx, func(ctx context.Context) error { return p.next.Publish(ctx, evt) })
+	if errors.Is(err, breaker.ErrOpen) {
+		return errors.Join(domain.ErrPublishUnavailable, err)
+	}
+	return err
+}
+
+// Available is true only while the breaker is closed. Callers check it before committing a bid, one admitted
+// while half-open could be rejected by Publish after the commit, so half-open slots go to probe instead,
+// started here in the background, and bids wait until it closed the breaker
+func (p *BreakerPublisher) Available() bool {
+	switch p.b.State() {
+	case breaker.StateClosed:
+		return true
+	case breaker.StateHalfOpen:
+		p.startProbe()
+	}
+	return false
+}
+
+// startProbe runs one probe at a time through the breaker
+func (p *BreakerPublisher) startProbe() {
+	if !p.probing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer p.probing.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+		defer cancel()
+		_ = p.b.Do(ctx, p.probe)
+	}()
+}
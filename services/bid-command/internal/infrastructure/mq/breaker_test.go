@@ -0,0 +1,64 @@
+package mq
+
+import (
+	"context"
+	"errors"
+	"kei-services/pkg/breaker"
+	"kei-services/services/bid-command/internal/domain"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPublisher struct {
+	mock.Mock
+}
+
+func (m *MockPublisher) Publish(ctx context.Context, evt domain.BidPlaced) error {
+	return m.Called(ctx, evt).Error(0)
+}
+
+func TestBreakerPublisher_HalfOpen(t *testing.T) {
+	ctx := context.Background()
+	down := errors.New("kafka down")
+
+	next := new(MockPublisher)
+	next.On("Publish", ctx, mock.Anything).Return(down).Once()
+
+	var probes atomic.Int32
+	var recovered atomic.Bool
+	probe := func(context.Context) error {
+		probes.Add(1)
+		if !recovered.Load() {
+			return down
+		}
+		return nil
+	}
+
+	b := breaker.New("kafka", &breaker.Config{IsEnabled: true, ConsecutiveFailures: 1, OpenMs: 10}, breaker.Options{})
+	p := NewBreakerPublisher(next, b, probe)
+
+	assert.True(t, p.Available())
+	assert.ErrorIs(t, p.Publish(ctx, domain.BidPlaced{}), down)
+	assert.False(t, p.Available(), "open")
+	assert.Zero(t, probes.Load(), "no probe while open")
+
+	// half-open: bids are still turned away, a failing probe opens the breaker again
+	time.Sleep(20 * time.Millisecond)
+	assert.False(t, p.Available(), "half-open")
+	assert.Eventually(t, func() bool { return b.State() == breaker.StateOpen }, time.Second, time.Millisecond)
+	assert.EqualValues(t, 1, probes.Load())
+
+	// the next probe succeeds and closes it
+	recovered.Store(true)
+	time.Sleep(20 * time.Millisecond)
+	assert.False(t, p.Available(), "half-open")
+	assert.Eventually(t, p.Available, time.Second, time.Millisecond)
+	assert.EqualValues(t, 2, probes.Load())
+
+	// no bid ever went through the half-open breaker
+	next.AssertNumberOfCalls(t, "Publish", 1)
+}
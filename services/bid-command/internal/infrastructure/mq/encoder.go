@@ -0,0 +1,55 @@
+package mq
+
+import (
+	"encoding/json"
+	"fmt"
+	"kei-services/pkg/eventspb"
+	"kei-services/pkg/schema"
+	"kei-services/services/bid-command/internal/domain"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// BidPlacedEncoder encodes bids.placed payloads, the content type goes into the content-type header
+type BidPlacedEncoder interface {
+	ContentType() string
+	Encode(evt domain.BidPlaced) ([]byte, error)
+}
+
+// NewBidPlacedEncoder returns the encoder for encoding: json (default) or protobuf. Projectors accept both,
+// switch producers only after every consumer runs a version decoding protobuf
+func NewBidPlacedEncoder(encoding string) (BidPlacedEncoder, error) {
+	switch encoding {
+	case "", "json":
+		return JSONEncoder{}, nil
+	case "protobuf":
+		return ProtobufEncoder{}, nil
+	default:
+		return nil, fmt.Errorf("unknown event encoding %q, expected json or protobuf", encoding)
+	}
+}
+
+type JSONEncoder struct{}
+
+func (JSONEncoder) ContentType() string { return schema.ContentTypeJSON }
+
+func (JSONEncoder) Encode(evt domain.BidPlaced) ([]byte, error) {
+	return json.Marshal(evt)
+}
+
+// ProtobufEncoder encodes events.v1.BidPlaced, see proto/events/v1/bids.proto
+type ProtobufEncoder struct{}
+
+func (ProtobufEncoder) ContentType() string { return schema.ContentTypeProtobuf }
+
+func (ProtobufEncoder) Encode(evt domain.BidPlaced) ([]byte, error) {
+	return proto.Marshal(&eventspb.BidPlaced{
+		AuctionId: evt.AuctionID,
+		BidId:     evt.BidID,
+		BidderId:  evt.BidderID,
+		Amount:    evt.Amount,
+		At:        timestamppb.New(evt.At),
+		Seq:       evt.Seq,
+	})
+}
@@ -0,0 +1,160 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"kei-services/pkg/middleware"
+	"kei-services/services/bid-command/internal/application/auction_admin"
+	"kei-services/services/bid-command/internal/domain"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// HeaderAdminActor names the operator behind an admin request in the audit log, the token only proves
+// the caller is an operator
+const HeaderAdminActor = "X-Admin-Actor"
+
+// AdminMetadataController serves the admin API over the auction metadata cache. Every call is written to
+// the audit log, reads included, with the operator, the outcome and the metadata before and after
+type AdminMetadataController struct {
+	log *zap.Logger
+	svc auction_admin.IService
+}
+
+func NewAdminMetadataController(log *zap.Logger, svc auction_admin.IService) *AdminMetadataController {
+	return &AdminMetadataController{log: log, svc: svc}
+}
+
+// Register mounts the routes on g, which must authenticate operators
+func (h *AdminMetadataController) Register(g gin.IRoutes) {
+	g.GET("/auctions/:auctionId/metadata", h.Inspect)
+	g.POST("/auctions/:auctionId/metadata/refresh", h.Refresh)
+	g.POST("/auctions/:auctionId/metadata/reseed", h.Reseed)
+	g.DELETE("/auctions/:auctionId/metadata", h.Evict)
+}
+
+type adminSnapshot struct {
+	AuctionID string                  `json:"auctionId"`
+	Key       string                  `json:"key"`
+	Cached    *domain.AuctionMetadata `json:"cached"`
+	TTL       string                  `json:"ttl,omitempty"`
+	LatestBid *adminBid               `json:"latestBid"`
+	Stale     bool                    `json:"stale"`
+}
+
+type adminBid struct {
+	ID       string    `json:"id"`
+	BidderID string    `json:"bidderId"`
+	Amount   float64   `json:"amount"`
+	Seq      int64     `json:"seq"`
+	At       time.Time `json:"at"`
+}
+
+type adminChange struct {
+	Action    string                  `json:"action"`
+	AuctionID string                  `json:"auctionId"`
+	Key       string                  `json:"key"`
+	Before    *domain.AuctionMetadata `json:"before"`
+	After     *domain.AuctionMetadata `json:"after"`
+	TTL       string                  `json:"ttl,omitempty"`
+}
+
+func (h *AdminMetadataController) Inspect(c *gin.Context) {
+	auctionID := c.Param("auctionId")
+	snap, err := h.svc.Inspect(c.Request.Context(), auctionID)
+	h.auditLog(c, "inspect", auctionID, err, zap.Bool("cached", snap != nil && snap.Cached != nil))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	out := adminSnapshot{AuctionID: auctionID, Key: snap.Key, Cached: snap.Cached, Stale: snap.Stale}
+	if snap.Cached != nil && snap.TTL >= 0 {
+		out.TTL = snap.TTL.String()
+	}
+	if b := snap.LatestBid; b != nil {
+		out.LatestBid = &adminBid{ID: b.ID, BidderID: b.BidderID, Amount: b.Amount, Seq: b.Seq, At: b.At}
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *AdminMetadataController) Refresh(c *gin.Context) {
+	h.change(c, "refresh", h.svc.Refresh)
+}
+
+func (h *AdminMetadataController) Reseed(c *gin.Context) {
+	h.change(c, "reseed", h.svc.Reseed)
+}
+
+func (h *AdminMetadataController) Evict(c *gin.Context) {
+	h.change(c, "evict", h.svc.Evict)
+}
+
+func (h *AdminMetadataController) change(c *gin.Context, action string,
+	run func(ctx context.Context, auctionID string) (*auction_admin.Change, error)) {
+	auctionID := c.Param("auctionId")
+	res, err := run(c.Request.Context(), auctionID)
+	if err != nil {
+		h.auditLog(c, action, auctionID, err)
+		h.handleError(c, err)
+		return
+	}
+	h.auditLog(c, action, auctionID, nil, zap.Any("before", res.Before), zap.Any("after", res.After))
+
+	out := adminChange{Action: action, AuctionID: auctionID, Key: res.Key, Before: res.Before, After: res.After}
+	if res.After != nil && res.TTL >= 0 {
+		out.TTL = res.TTL.String()
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// auditLog records an admin call on the audit logger, err is its outcome. The request logger already
+// carries the request id
+func (h *AdminMetadataController) auditLog(c *gin.Context, action, auctionID string, err error, fields ...zap.Field) {
+	audit := middleware.LoggerFrom(c.Request.Context(), h.log).Named("audit")
+	fields = append([]zap.Field{
+		zap.String("action", action),
+		zap.String("auctionId", auctionID),
+		zap.String("actor", c.GetHeader(HeaderAdminActor)),
+		zap.String("clientIp", c.ClientIP()),
+	}, fields...)
+	if err != nil {
+		audit.Warn("admin action failed", append(fields, zap.Error(err))...)
+		return
+	}
+	audit.Info("admin action", fields...)
+}
+
+func (h *AdminMetadataController) handleError(c *gin.Context, err error) {
+	log := middleware.LoggerFrom(c.Request.Context(), h.log)
+	switch {
+	case errors.Is(err, domain.ErrAuctionNotCached):
+		writeProblem(c, http.StatusNotFound,
+			"https://example.com/problems/auction-metadata-not-cached",
+			"Auction metadata not cached",
+			"Nothing to refresh, reseed it from the auction events instead",
+		)
+	case errors.Is(err, domain.ErrMetadataChanged):
+		writeProblem(c, http.StatusConflict,
+			"https://example.com/problems/auction-metadata-changed",
+			"Auction metadata changed",
+			"A newer auction event or bid was cached meanwhile, inspect it again before retrying",
+		)
+	case errors.Is(err, auction_admin.ErrNoAuctionEvents):
+		writeProblem(c, http.StatusNotFound,
+			"https://example.com/problems/no-auction-events",
+			"No auction events",
+			"No auction.opened or auction.closed of this auction is retained in Kafka",
+		)
+	default:
+		log.Error("unhandled error in admin metadata API", zap.Error(err))
+		writeProblem(c, http.StatusInternalServerError,
+			"https://example.com/problems/internal",
+			"Internal Server Error",
+			"An unexpected error occurred",
+		)
+	}
+}
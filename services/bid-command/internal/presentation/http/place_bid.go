@@ -14,12 +14,13 @@ import (
 )
 
 type PlaceBidController struct {
-	log *zap.Logger
-	svc place_bid.IService
+	log     *zap.Logger
+	svc     place_bid.IService
+	enabled func() bool // placeBids toggle, read per request
 }
 
-func NewPlaceBidController(log *zap.Logger, svc place_bid.IService) *PlaceBidController {
-	return &PlaceBidController{log: log, svc: svc}
+func NewPlaceBidController(log *zap.Logger, svc place_bid.IService, enabled func() bool) *PlaceBidController {
+	return &PlaceBidController{log: log, svc: svc, enabled: enabled}
 }
 
 var _ openapi.ServerInterface = (*PlaceBidController)(nil)
@@ -28,6 +29,16 @@ func (h *PlaceBidController) PostApiV1BidsAuctionId(c *gin.Context, auctionId st
 	log := middleware.LoggerFrom(c.Request.Context(), h.log)
 	log.Info("post bids: request received", zap.String("auctionId", auctionId), zap.Any("params", c.Request.Body))
 
+	if !h.enabled() {
+		c.Header("Retry-After", "60")
+		writeProblem(c, http.StatusServiceUnavailable,
+			"https://example.com/problems/bids-paused",
+			"Bid placement paused",
+			"Bids are temporarily not accepted, retry later",
+		)
+		return
+	}
+
 	var req openapi.PlaceBidRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Warn("failed to bind", zap.Error(err))
@@ -133,6 +144,24 @@ func (h *PlaceBidController) handleError(c *gin.Context, err error, log *zap.Log
 			"Concurrent update detected; fetch latest price and retry",
 		)
 
+	// dependencies behind an open circuit breaker
+	case errors.Is(err, domain.ErrMetadataUnavailable):
+		log.Warn("auction metadata unavailable", zap.Error(err))
+		c.Header("Retry-After", "5")
+		writeProblem(c, http.StatusServiceUnavailable,
+			"https://example.com/problems/auction-metadata-unavailable",
+			"Auction metadata unavailable",
+			"Bids can't be validated right now, retry shortly",
+		)
+	case errors.Is(err, domain.ErrPublishUnavailable):
+		log.Warn("publish unavailable", zap.Error(err))
+		c.Header("Retry-After", "10")
+		writeProblem(c, http.StatusServiceUnavailable,
+			"https://example.com/problems/publish-unavailable",
+			"Bids temporarily unavailable",
+			"Accepted bids can't be published right now, retry shortly",
+		)
+
 	// fallback
 	default:
 		log.Error("unhandled error in PostAuctionsAuctionIdBids", zap.Error(err))
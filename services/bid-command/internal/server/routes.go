@@ -2,29 +2,42 @@ package server
 
 import (
 	"context"
+	"kei-services/pkg/health"
+	"kei-services/pkg/middleware"
 	"kei-services/services/bid-command/internal/cfg"
 	httpPresentation "kei-services/services/bid-command/internal/presentation/http"
 	"kei-services/services/bid-command/openapi"
-	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
-func registerProtectedRoutes(r *gin.Engine, d *deps, _ *cfg.Config, log *zap.Logger) {
+func registerProtectedRoutes(r *gin.Engine, d *deps, rl *cfg.Reloader, limiter *middleware.RateLimiter,
+	log *zap.Logger) {
 	protected := r.Group("")
-	protected.Use()
+	protected.Use(limiter.Middleware())
 
+	placeBids := func() bool { return rl.Current().Features.Enabled(cfg.FeaturePlaceBids, true) }
 	m := &MasterHandler{
-		PlaceBidHandler: *httpPresentation.NewPlaceBidController(log, d.PlaceBidService),
+		PlaceBidHandler: *httpPresentation.NewPlaceBidController(log, d.PlaceBidService, placeBids),
 	}
 
 	openapi.RegisterHandlers(protected, m)
 }
 
+// registerAdminRoutes mounts the operator API under /admin when enabled, behind the admin bearer token
+func registerAdminRoutes(r *gin.Engine, d *deps, c *cfg.AdminConfig, log *zap.Logger) {
+	if c == nil || !c.IsEnabled {
+		return
+	}
+	admin := r.Group("/admin", middleware.BearerToken(c.Token, log.Named("audit")))
+	httpPresentation.NewAdminMetadataController(log, d.AuctionAdminService).Register(admin)
+	log.Info("admin API enabled", zap.String("path", "/admin"))
+}
+
 type MasterHandler struct {
 	PlaceBidHandler httpPresentation.PlaceBidController
 }
@@ -33,53 +46,19 @@ func (m MasterHandler) PostApiV1BidsAuctionId(c *gin.Context, auctionId string)
 	m.PlaceBidHandler.PostApiV1BidsAuctionId(c, auctionId)
 }
 
-func registerHealthroutes(r *gin.Engine, db *gorm.DB, redis *redis.Client, _ *zap.Logger) {
-	r.GET("/healthz", func(c *gin.Context) {
-		c.String(http.StatusOK, "ok")
-	})
-
-	r.GET("/readyz", func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 500*time.Millisecond)
-		defer cancel()
-
-		type check struct {
-			Name string `json:"name"`
-			OK   bool   `json:"ok"`
-			Err  string `json:"err,omitempty"`
-		}
-
-		var checks []check
-		allOK := true
-
-		// DB ping
-		if sqlDB, err := db.DB(); err != nil {
-			allOK = false
-			checks = append(checks, check{Name: "db", OK: false, Err: err.Error()})
-		} else if err := sqlDB.PingContext(ctx); err != nil {
-			allOK = false
-			checks = append(checks, check{Name: "db", OK: false, Err: err.Error()})
-		} else {
-			checks = append(checks, check{Name: "db", OK: true})
-		}
-
-		// Redis ping
-		if err := redis.Ping(ctx).Err(); err != nil {
-			allOK = false
-			checks = append(checks, check{Name: "redis", OK: false, Err: err.Error()})
-		} else {
-			checks = append(checks, check{Name: "redis", OK: true})
-		}
-
-		// todo add Kafka check
-
-		status := http.StatusOK
-		if !allOK {
-			status = http.StatusServiceUnavailable
+// registerHealthRoutes serves the probes, they pass once MarkStarted is called on the returned Health
+func registerHealthRoutes(r *gin.Engine, db *gorm.DB, redis redis.UniversalClient, w *kafka.Writer,
+	c *cfg.Config) *health.Health {
+	h := health.New(health.NewBuildInfo("bid-command", c.App))
+	pg := health.Func("postgres", func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
 		}
-		c.JSON(status, gin.H{
-			"status": map[bool]string{true: "ready", false: "degraded"}[allOK],
-			"checks": checks,
-			"uptime": time.Since(time.Unix(0, 0)).String(), // todo use start time
-		})
+		return sqlDB.PingContext(ctx)
 	})
+	pg.Startup = true // no bid can be placed without it
+	h.Add(pg, health.Redis(redis), health.Kafka(c.KafkaWriter.Brokers, w.Transport))
+	h.Register(r)
+	return h
 }
@@ -7,6 +7,7 @@ import (
 	"kei-services/pkg/metrics"
 	"kei-services/pkg/middleware"
 	swagger "kei-services/pkg/swagger"
+	"kei-services/pkg/tracing"
 	"kei-services/services/bid-command/internal/cfg"
 	"kei-services/services/bid-command/openapi"
 
@@ -16,11 +17,11 @@ import (
 
 	"github.com/getkin/kin-openapi/openapi3"
 	ginzap "github.com/gin-contrib/zap"
-	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
 	"github.com/segmentio/kafka-go"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -28,40 +29,45 @@ import (
 type Server struct {
 	srv    *http.Server
 	engine *gin.Engine
+	stopBg context.CancelFunc // stops background tasks started by New
 	cfg    *cfg.Config
 	log    *zap.Logger
 }
 
-func New(db *gorm.DB, redis *redis.Client, w *kafka.Writer, cfg *cfg.Config, log *zap.Logger) *Server {
+// New wires the routes, met is shared with the infrastructure clients created by main. Runtime-tunable
+// settings follow rl's reloads
+func New(db *gorm.DB, redis redis.UniversalClient, w *kafka.Writer, met *metrics.Registry, rl *cfg.Reloader,
+	log *zap.Logger) *Server {
+	cfg := rl.Current()
 	if cfg.App.Environment == "prod" {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
 		gin.SetMode(gin.DebugMode)
 	}
 
-	met := metrics.New(metrics.Options{
-		Namespace:   "bidcommand",
-		ConstLabels: prometheus.Labels{"service": "bidcommand", "env": string(cfg.App.Environment)},
-	})
-
 	httpMx := metrics.NewHTTPServerMetrics(met, metrics.HTTPOpts{
 		Namespace: "bidcommand",
 		//ConstLabels: prometheus.Labels{"service": "bid-command", "env": string(cfg.App.Environment)},
 	})
 
+	origins := middleware.NewOrigins(cfg.Cors.AllowOrigins)
+	limiter := middleware.NewRateLimiter(cfg.RateLimit)
+	subscribeRuntime(rl, origins, limiter)
+
 	r := gin.New()
 	r.RedirectTrailingSlash = false
 	r.RedirectFixedPath = false
 	r.Use(
 		metrics.PanicCounterMiddleware(httpMx),
 		ginzap.RecoveryWithZap(log, true),
+		otelgin.Middleware("bid-command", otelgin.WithFilter(tracing.TraceRequest)),
 		ginzap.Ginzap(log, time.RFC3339, true),
 		metrics.GinAdapterMiddleware(httpMx),
 
 		middleware.RequestID(),
 		middleware.WithRequestLogger(log),
 		middleware.RequestLogger(log),
-		middleware.Cors(cfg.Cors, log),
+		middleware.CorsWithOrigins(cfg.Cors, origins, log),
 		middleware.MaxBody(10<<20), // 10 mb
 	)
 
@@ -72,8 +78,12 @@ func New(db *gorm.DB, redis *redis.Client, w *kafka.Writer, cfg *cfg.Config, log
 
 	r.GET("/metrics", gin.WrapH(met.Handler)) // prometheus
 
-	registerHealthroutes(r, db, redis, log)
-	registerProtectedRoutes(r, initDependencies(db, redis, w, cfg, log), cfg, log)
+	hc := registerHealthRoutes(r, db, redis, w, cfg)
+	bg, stopBg := context.WithCancel(context.Background())
+	d := initDependencies(bg, db, redis, w, met, rl, log)
+	registerProtectedRoutes(r, d, rl, limiter, log)
+	registerAdminRoutes(r, d, cfg.Admin, log)
+	hc.MarkStarted()
 
 	r.NoRoute(func(c *gin.Context) { c.JSON(404, gin.H{"error": "not found"}) })
 	r.NoMethod(func(c *gin.Context) { c.JSON(405, gin.H{"error": "method not allowed"}) })
@@ -93,7 +103,15 @@ func New(db *gorm.DB, redis *redis.Client, w *kafka.Writer, cfg *cfg.Config, log
 		MaxHeaderBytes:    1 << 20, // 1mb
 	}
 
-	return &Server{srv: srv, engine: r, cfg: cfg, log: log}
+	return &Server{srv: srv, engine: r, stopBg: stopBg, cfg: cfg, log: log}
+}
+
+// subscribeRuntime applies reloaded CORS origins and rate limits to the running middlewares
+func subscribeRuntime(rl *cfg.Reloader, origins *middleware.Origins, limiter *middleware.RateLimiter) {
+	rl.Subscribe(func(c *cfg.Config) {
+		origins.Set(c.Cors.AllowOrigins)
+		limiter.Update(c.RateLimit)
+	})
 }
 
 func Start(s *Server, cfg *cfg.Config, log *zap.Logger) error {
@@ -115,6 +133,7 @@ func Start(s *Server, cfg *cfg.Config, log *zap.Logger) error {
 
 func Shutdown(ctx context.Context, s *Server, log *zap.Logger) error {
 	log.Info("Shutting down server")
+	defer s.stopBg()
 	return s.srv.Shutdown(ctx)
 }
 
@@ -1,8 +1,15 @@
 package server
 
 import (
+	"context"
+	"kei-services/pkg/breaker"
+	"kei-services/pkg/health"
+	redisInfra "kei-services/pkg/infra/redis"
+	"kei-services/pkg/metrics"
+	"kei-services/services/bid-command/internal/application/auction_admin"
 	"kei-services/services/bid-command/internal/application/place_bid"
 	"kei-services/services/bid-command/internal/cfg"
+	"kei-services/services/bid-command/internal/domain"
 	"kei-services/services/bid-command/internal/infrastructure/cache"
 	"kei-services/services/bid-command/internal/infrastructure/db/repo"
 	"kei-services/services/bid-command/internal/infrastructure/db/tx"
@@ -16,28 +23,98 @@ import (
 )
 
 type deps struct {
-	PlaceBidService place_bid.IService
+	PlaceBidService     place_bid.IService
+	AuctionAdminService auction_admin.IService
 }
 
+// maxLastKnownMetadata and maxLastKnownAge bound the metadata kept for reads while the Redis breaker is open
+const (
+	maxLastKnownMetadata = 10000
+	maxLastKnownAge      = time.Minute
+)
+
 type systemClock struct{}
 
 func (systemClock) Now() time.Time { return time.Now() }
 
-func initDependencies(db *gorm.DB, redis *redis.Client, w *kafka.Writer, cfg *cfg.Config, log *zap.Logger) *deps {
+func initDependencies(bg context.Context, db *gorm.DB, redis redis.UniversalClient, w *kafka.Writer,
+	met *metrics.Registry, rl *cfg.Reloader, log *zap.Logger) *deps {
+
 	sqlDb, err := db.DB()
 	if err != nil {
 		log.Fatal("failed to get sql db from gorm", zap.Error(err))
 	}
 
+	// follows auction-projector rebuilds to the key prefix they switched to
+	metadataCache := cache.NewAuctionMetadataCache(redis, log)
+	activePrefix := redisInfra.NewActivePrefix(redis, cache.ActivePrefixKey, metadataCache.CacheKey, log)
+	if err = activePrefix.Refresh(bg); err != nil {
+		log.Warn("resolve active auction metadata prefix", zap.Error(err))
+	}
+	go activePrefix.Watch(bg, redisInfra.DefaultRefreshInterval)
+	metadataCache.Prefix = activePrefix
+
+	events := rl.Current().Events
+	var encoding string
+	if events != nil {
+		encoding = events.Encoding
+	}
+	encoder, err := mq.NewBidPlacedEncoder(encoding)
+	if err != nil {
+		log.Fatal("events encoder", zap.Error(err))
+	}
+
+	publisher := mq.NewBidsPublisher(w, encoder, events.PublishTimeout(), log)
+	rl.Subscribe(func(c *cfg.Config) { publisher.SetTimeout(c.Events.PublishTimeout()) })
+
+	// only place_bid goes through the breakers, the admin API must reach Redis to repair it
+	var metadataStore domain.IAuctionMetadataStore = metadataCache
+	var bidsPublisher domain.IBidsPlacedPublisher = publisher
+	if b := rl.Current().Breakers; b != nil {
+		bm := breaker.NewMetrics(met)
+		if cb := breaker.New("redis_metadata", b.Cache, breaker.Options{
+			IsFailure: cache.IsFailure, Metrics: bm, Log: log,
+		}); cb != nil {
+			metadataStore = cache.NewBreakerStore(metadataCache, cb, maxLastKnownMetadata, maxLastKnownAge)
+		}
+		if cb := breaker.New("kafka_bids_placed", b.Publish, breaker.Options{Metrics: bm, Log: log}); cb != nil {
+			// half-open probes ask the brokers for the topic's metadata rather than admitting a bid
+			probe := health.Kafka(rl.Current().KafkaWriter.Brokers, w.Transport, w.Topic).Ping
+			bidsPublisher = mq.NewBreakerPublisher(publisher, cb, probe)
+		}
+	}
+
+	// in front of the breaker, entries are only served while Redis delivers invalidations
+	if c := rl.Current().MetadataL1; c != nil && c.IsEnabled {
+		requests := metrics.CCounter(met.Reg, met.DefaultNS, "auction_metadata_l1_requests_total",
+			"Auction metadata L1 cache lookups by result (hit, miss, bypass).", nil, []string{"result"})
+
+		l1 := cache.NewL1Store(metadataStore, metadataCache.Key, c.Size(), c.TTL(), requests, log)
+		go l1.Listen(bg, redis)
+		metadataStore = l1
+		log.Info("auction metadata L1 cache enabled", zap.Duration("ttl", c.TTL()), zap.Int("size", c.Size()))
+	}
+
+	bidRepo := repo.NewBidRepo(sqlDb, log)
 	placeBidService := place_bid.NewService(place_bid.Deps{
-		BidRepo: repo.NewBidRepo(sqlDb, log),
-		Cache:   cache.NewAuctionMetadataCache(redis, log),
-		Pub:     mq.NewBidsPublisher(w, log),
+		BidRepo: bidRepo,
+		Cache:   metadataStore,
+		Pub:     bidsPublisher,
 		Tx:      tx.NewTxManager(sqlDb),
 		Clock:   systemClock{},
 	}, log)
 
+	c := rl.Current()
+	auctionAdminService := auction_admin.NewService(auction_admin.Deps{
+		Cache:         metadataCache,
+		Bids:          bidRepo,
+		Events:        mq.NewAuctionEvents(c.KafkaWriter.Brokers, c.KafkaWriter.Security, log),
+		Clock:         systemClock{},
+		ReseedTimeout: c.Admin.ReseedTimeout(),
+	}, log)
+
 	return &deps{
-		PlaceBidService: placeBidService,
+		PlaceBidService:     placeBidService,
+		AuctionAdminService: auctionAdminService,
 	}
 }
@@ -40,6 +40,35 @@ func (q *Queries) InsertBid(ctx context.Context, arg InsertBidParams) (InsertBid
 	return i, err
 }
 
+const latest = `-- name: Latest :one
+SELECT id, bidder_id, amount, seq, at
+FROM bids
+WHERE auction_id = $1
+ORDER BY seq DESC
+    LIMIT 1
+`
+
+type LatestRow struct {
+	ID       string    `json:"id"`
+	BidderID string    `json:"bidder_id"`
+	Amount   float64   `json:"amount"`
+	Seq      int64     `json:"seq"`
+	At       time.Time `json:"at"`
+}
+
+func (q *Queries) Latest(ctx context.Context, auctionID string) (LatestRow, error) {
+	row := q.db.QueryRowContext(ctx, latest, auctionID)
+	var i LatestRow
+	err := row.Scan(
+		&i.ID,
+		&i.BidderID,
+		&i.Amount,
+		&i.Seq,
+		&i.At,
+	)
+	return i, err
+}
+
 const latestForUpdate = `-- name: LatestForUpdate :one
 SELECT id, amount, seq, at
 FROM bids
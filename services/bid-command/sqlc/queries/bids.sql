@@ -9,4 +9,10 @@ FROM bids
 WHERE auction_id = $1
 ORDER BY seq DESC
     LIMIT 1
-    FOR UPDATE;
\ No newline at end of file
+    FOR UPDATE;
+-- name: Latest :one
+SELECT id, bidder_id, amount, seq, at
+FROM bids
+WHERE auction_id = $1
+ORDER BY seq DESC
+    LIMIT 1;
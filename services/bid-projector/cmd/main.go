@@ -2,21 +2,46 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"kei-services/pkg/config"
+	"kei-services/pkg/health"
 	kafkaInfra "kei-services/pkg/infra/kafka"
 	mongoInfra "kei-services/pkg/infra/mongo"
+	"kei-services/pkg/infra/postgres"
+	redisInfra "kei-services/pkg/infra/redis"
 	"kei-services/pkg/logger"
+	"kei-services/pkg/metrics"
+	"kei-services/pkg/projector"
+	"kei-services/pkg/shutdown"
+	"kei-services/pkg/tracing"
 	"kei-services/services/bid-projector/internal/cfg"
 	"kei-services/services/bid-projector/internal/events"
+	"kei-services/services/bid-projector/internal/projections/cdn"
 	mongoProjection "kei-services/services/bid-projector/internal/projections/mongo"
-	"kei-services/services/bid-projector/internal/projector"
+	redisProjection "kei-services/services/bid-projector/internal/projections/redis"
+	"kei-services/services/bid-projector/internal/rebuild"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	dlqCmd = flag.String("dlq", "",
+		"dead-letter command, runs and exits: list prints the dead-letter topic as JSON lines, redrive re-runs it through the router")
+	rebuildCmd = flag.Bool("rebuild", false,
+		"rebuild bids_history into a new collection next to the live one, switch bid-query over once caught up and exit")
+	rebuildFrom = flag.String("rebuild-from", string(rebuild.SourceKafka),
+		"rebuild source: kafka replays the topics from the earliest offset, postgres copies the bids table first")
+	checkConfig = flag.Bool("check-config", false, "validate the config, print every problem found and exit")
 )
 
 func main() {
@@ -26,6 +51,7 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	config.MustValidate(config.Path(), cfg, *checkConfig)
 
 	log := logger.Init(cfg.Logger, cfg.App)
 	if log == nil {
@@ -35,32 +61,156 @@ func main() {
 
 	log.Info("Starting App", zap.String("version", cfg.App.Version))
 
+	// phases run in reverse, clients registered as they are created close after what uses them stopped
+	sd := shutdown.New(shutdown.DefaultTimeout, log)
+	defer func() { _ = sd.Shutdown() }()
+
+	// tracing, flushed on exit
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, "bid-projector", cfg.App, log)
+	if err != nil {
+		log.Fatal("init tracing", zap.Error(err))
+	}
+	sd.Add("tracing", shutdownTracing)
+
 	ctx, cancel := signal.NotifyContext(context.Background(),
 		os.Interrupt, syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
 	defer cancel()
 
+	switch *dlqCmd {
+	case "", "list", "redrive":
+	default:
+		log.Fatal("unknown dlq command, expected list or redrive", zap.String("dlq", *dlqCmd))
+	}
+	source, err := rebuild.ParseSource(*rebuildFrom)
+	if err != nil {
+		log.Fatal("rebuild-from", zap.Error(err))
+	}
+
+	if *dlqCmd == "list" {
+		if cfg.DeadLetter == nil {
+			log.Fatal("dlq list: no dead-letter topic configured")
+		}
+		n, err := projector.ListDeadLetters(ctx, cfg.KafkaReader.Brokers, cfg.KafkaReader.Security,
+			cfg.DeadLetter.Topic, os.Stdout)
+		if err != nil {
+			log.Fatal("dlq list", zap.Error(err))
+		}
+		log.Info("dlq list done", zap.Int("records", n))
+		return
+	}
+
 	// mongo
 	mc, err := mongoInfra.NewClient(cfg.Mongo, log)
 	if err != nil {
 		log.Fatal("mongo client", zap.Error(err))
 	}
-	defer func() {
-		if err := mc.Disconnect(context.Background()); err != nil {
-			log.Error("disconnect mongo client", zap.Error(err))
-		}
-	}()
+	sd.Add("mongo", mc.Disconnect)
+
+	// redis
+	redisClient, err := redisInfra.Client(cfg.Redis, log)
+	if err != nil {
+		log.Fatal("connect to redis", zap.Error(err))
+	}
+	sd.AddCloser("redis", redisClient)
 
 	// setup kafka reader
 	// ensure topics
-	if err = projector.EnsureTopics(ctx, cfg.KafkaReader.Brokers, cfg.KafkaReader.GroupTopics, 1, 1); err != nil {
-		log.Warn("ensure topics", zap.Strings("topics", cfg.KafkaReader.GroupTopics), zap.Error(err))
+	topics := cfg.KafkaReader.GroupTopics
+	if cfg.DeadLetter != nil {
+		topics = append(append([]string(nil), topics...), cfg.DeadLetter.Topic)
 	}
+	if err = projector.EnsureTopics(ctx, cfg.KafkaReader.Brokers, cfg.KafkaReader.Security, topics, 1, 1); err != nil {
+		log.Warn("ensure topics", zap.Strings("topics", topics), zap.Error(err))
+	}
+
+	// dead-letter topic, failed messages are dropped without one
+	var dlq *kafkaInfra.DeadLetter
+	if cfg.DeadLetter != nil {
+		if dlq, err = kafkaInfra.NewDeadLetter(cfg.KafkaReader.Brokers, cfg.KafkaReader.Security, cfg.DeadLetter, log); err != nil {
+			log.Fatal("dead-letter writer", zap.Error(err))
+		}
+		sd.AddCloser("dead-letter writer", dlq)
+	}
+
+	// the ttl is refreshed on every bump, so only auctions without bids for a week lose their version
+	bidsVersionProjection := redisProjection.NewBidsVersionProjection(redisClient, log, bidsVersionTTL)
 
+	// rebuild runs next to the live projectors and never joins their group
+	if *rebuildCmd {
+		var pg *gorm.DB
+		if source == rebuild.SourcePostgres {
+			if pg, err = postgres.Client(cfg.Postgres, &cfg.Network.Ssl, log); err != nil {
+				log.Fatal("connect to postgres", zap.Error(err))
+			}
+			sqlDB, err := pg.DB()
+			if err != nil {
+				log.Fatal("postgres pool", zap.Error(err))
+			}
+			sd.AddCloser("postgres", sqlDB)
+		}
+		coll, err := rebuild.Run(ctx, rebuild.Deps{
+			Mongo:    mc.DB,
+			Postgres: pg,
+			Versions: bidsVersionProjection,
+			Brokers:  cfg.KafkaReader.Brokers,
+			Security: cfg.KafkaReader.Security,
+			Topics:   cfg.KafkaReader.GroupTopics,
+			NewProjector: func(router *projector.Router) *projector.Projector {
+				return projector.New(nil, router, projectorOptions(cfg, dlq), log)
+			},
+		}, source, log)
+		if err != nil {
+			log.Fatal("rebuild", zap.Error(err))
+		}
+		log.Info("rebuild switched bids_history", zap.String("collection", coll))
+		return
+	}
+
+	// wire projector, writes follow bids_history to the collection a rebuild switched to
+	bidsHistory := mongoInfra.NewActiveCollection(mc.DB, mongoProjection.BidsHistory, log)
+	if err = bidsHistory.Refresh(ctx, nil); err != nil {
+		log.Warn("resolve active bids_history collection", zap.String("collection", bidsHistory.Collection().Name()), zap.Error(err))
+	}
+	go bidsHistory.Watch(ctx, mongoInfra.DefaultRefreshInterval, nil)
+	mongoDbProjection := mongoProjection.NewProjection(bidsHistory, log)
+
+	// version bump must run after the bid is in mongo, purge after the version bump
+	met := metrics.New(metrics.Options{
+		Namespace:   "bidprojector",
+		ConstLabels: prometheus.Labels{"service": "bidprojector", "env": string(cfg.App.Environment)},
+	})
+	projectorMx := projector.NewMetrics(met)
+
+	codec := &events.Codec{}
+	router := projector.NewRouter().Use(projector.Tracing(), projector.Logging(log), projectorMx.Middleware())
+	bidsPlaced := projector.Topic(router, events.TopicBidsPlaced, projector.As[events.BidPlaced](codec.DecodeMessage)).
+		Handle("mongo", mongoDbProjection.OnBidsPlaced).
+		Handle("bids-version", bidsVersionProjection.OnBidsPlaced)
+	if c := cfg.CdnPurge; c != nil && c.IsEnabled {
+		purge := cdn.NewSurrogatePurge(c.URL, c.Method, c.Token, time.Duration(c.TimeoutMs)*time.Millisecond, log)
+		bidsPlaced.Handle("cdn-purge", purge.OnBidsPlaced)
+		go purge.Run(ctx)
+		log.Info("cdn surrogate purge enabled", zap.String("url", c.URL))
+	}
+
+	// redrive runs before the group reader exists, joining the live group would rebalance the running projectors
+	if *dlqCmd == "redrive" {
+		redriven, failed, err := projector.New(nil, router, projectorOptions(cfg, dlq), log).
+			Redrive(ctx, cfg.KafkaReader.Brokers, cfg.KafkaReader.Security, cfg.DeadLetter, 10*time.Second)
+		if err != nil {
+			log.Fatal("dlq redrive", zap.Error(err))
+		}
+		log.Info("dlq redrive done", zap.Int("redriven", redriven), zap.Int("failed", failed))
+		return
+	}
+
+	cfg.KafkaReader.Metrics = met
+	cfg.KafkaReader.Log = log
 	bidReader, err := kafkaInfra.NewReader(cfg.KafkaReader)
 	if err != nil {
 		log.Fatal("kafka reader", zap.Error(err))
 	}
-	defer bidReader.Close()
+	sd.AddCloser("kafka reader", bidReader) // closing commits the final offsets
 
 	log.Info("kafka reader configured",
 		zap.Strings("brokers", cfg.KafkaReader.Brokers),
@@ -70,24 +220,75 @@ func main() {
 		zap.String("startOffset", string(cfg.KafkaReader.Offset)),
 	)
 
-	// wire projector
-	mongoDbProjection := mongoProjection.NewProjection(mc.DB, log)
+	opts := projectorOptions(cfg, dlq)
+	opts.Metrics = projectorMx
+	p := projector.New(bidReader, router, opts, log)
 
-	router := &projector.Router{
-		Codec:    &events.Codec{},
-		Handlers: mongoDbProjection,
+	// probes: dependencies plus the reader's partition assignment, see projector.OpsHandler
+	transport, err := cfg.KafkaReader.Transport()
+	if err != nil {
+		log.Fatal("kafka transport", zap.Error(err))
 	}
+	hc := health.New(health.NewBuildInfo("bid-projector", cfg.App))
+	hc.Add(health.Mongo(mc), health.Redis(redisClient), health.Kafka(cfg.KafkaReader.Brokers, transport))
+	hc.MarkStarted()
 
-	p := projector.New(bidReader, router, log)
-
-	// run projector
+	// ops server: prometheus scrape, liveness and readiness probes, up until the projector drained
+	opsCtx, stopOps := context.WithCancel(context.Background())
+	sd.Add("ops server", func(context.Context) error {
+		stopOps()
+		return nil
+	})
 	go func() {
-		if err = p.Run(ctx); err != nil {
-			log.Error("projector stopped", zap.Error(err))
+		addr := opsAddr(cfg.Network)
+		log.Info("ops server listening", zap.String("address", addr))
+		err := metrics.Serve(opsCtx, addr, projector.OpsHandler(p, met.Handler, hc))
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("ops server", zap.Error(err))
 		}
 	}()
 
-	// block until signal
-	<-ctx.Done()
-	log.Info("shutdown signal received")
+	// run projector, a signal doesn't cancel it but starts the shutdown, which drains it first
+	sd.Add("projector", p.Shutdown)
+	runErr := make(chan error, 1)
+	go func() { runErr <- p.Run(context.Background()) }()
+
+	// block until signal, or the projector failing on its own, which must restart the pod instead of
+	// leaving it up and consuming nothing
+	select {
+	case <-ctx.Done():
+		log.Info("shutdown signal received")
+		_ = sd.Shutdown()
+	case err = <-runErr:
+		log.Error("projector stopped, shutting down", zap.Error(err))
+		_ = sd.Shutdown()
+		log.Fatal("projector failed", zap.Error(err))
+	}
+}
+
+// bidsVersionTTL outlives any auction, bid-query derives ETags from the version and a key that expired
+// and restarted at 1 could revalidate a page cached before it expired
+const bidsVersionTTL = 7 * 24 * time.Hour
+
+func projectorOptions(c *cfg.Config, dlq *kafkaInfra.DeadLetter) projector.Options {
+	opts := projector.Options{DeadLetter: dlq}
+	if r := c.Retry; r != nil {
+		opts.Retry = projector.RetryPolicy{
+			MaxAttempts:    r.MaxAttempts,
+			InitialBackoff: time.Duration(r.InitialBackoffMs) * time.Millisecond,
+			MaxBackoff:     time.Duration(r.MaxBackoffMs) * time.Millisecond,
+		}
+	}
+	if w := c.Workers; w != nil {
+		opts.Workers = w.Count
+		opts.QueueSize = w.QueueSize
+	}
+	return opts
+}
+
+func opsAddr(n *config.Network) string {
+	if n.IsLocalHost {
+		return fmt.Sprintf("127.0.0.1:%d", n.Port)
+	}
+	return fmt.Sprintf(":%d", n.Port)
 }
@@ -1,10 +1,15 @@
 package cfg
 
 import (
+	"errors"
 	"kei-services/pkg/config"
 	"kei-services/pkg/infra/kafka"
 	"kei-services/pkg/infra/mongo"
+	"kei-services/pkg/infra/postgres"
+	"kei-services/pkg/infra/redis"
 	"kei-services/pkg/logger"
+	"kei-services/pkg/tracing"
+	"net/url"
 )
 
 type Config struct {
@@ -14,7 +19,96 @@ type Config struct {
 
 	Logger *logger.Config
 
+	Tracing *tracing.Config
+
 	Mongo *mongo.Config
 
+	Redis *redis.Config
+
+	// Postgres is bid-command's write database, only read by -rebuild -rebuild-from postgres
+	Postgres *postgres.Config
+
 	KafkaReader *kafka.ReaderConfig
+
+	DeadLetter *kafka.DeadLetterConfig
+
+	Retry *RetryConfig
+
+	Workers *WorkersConfig
+
+	CdnPurge *CdnPurgeConfig
+}
+
+// Validate reports every problem in c at once, required sections that are missing included. Postgres is
+// checked when present, -rebuild-from postgres requires it
+func (c *Config) Validate() error {
+	return errors.Join(
+		config.Required("App", c.App),
+		config.Required("Network", c.Network),
+		config.Required("Logger", c.Logger),
+		config.Optional("Tracing", c.Tracing),
+		config.Required("Mongo", c.Mongo),
+		config.Required("Redis", c.Redis),
+		config.Optional("Postgres", c.Postgres),
+		config.Required("KafkaReader", c.KafkaReader),
+		config.Optional("DeadLetter", c.DeadLetter),
+		config.Optional("Retry", c.Retry),
+		config.Optional("Workers", c.Workers),
+		config.Optional("CdnPurge", c.CdnPurge),
+	)
+}
+
+// RetryConfig bounds handler retries before a message is dead-lettered
+type RetryConfig struct {
+	MaxAttempts      int // including the first, default: 5
+	InitialBackoffMs int // default: 200
+	MaxBackoffMs     int // default: 10000
+}
+
+func (c *RetryConfig) Validate() error {
+	var errs []error
+	if c.MaxAttempts < 0 || c.InitialBackoffMs < 0 || c.MaxBackoffMs < 0 {
+		errs = append(errs, config.Problem("MaxAttempts", "attempts and backoffs must not be negative"))
+	}
+	if c.MaxBackoffMs > 0 && c.InitialBackoffMs > c.MaxBackoffMs {
+		errs = append(errs, config.Problem("InitialBackoffMs", "%d exceeds maxBackoffMs %d", c.InitialBackoffMs, c.MaxBackoffMs))
+	}
+	return errors.Join(errs...)
+}
+
+// WorkersConfig sets how many messages are handled in parallel, messages with the same key (auction ID)
+// always go to the same worker so per-auction ordering is kept
+type WorkersConfig struct {
+	Count     int // default: 1
+	QueueSize int // per worker, default: 64
+}
+
+func (c *WorkersConfig) Validate() error {
+	if c.Count < 0 || c.QueueSize < 0 {
+		return config.Problem("Count", "workers and queue size must not be negative")
+	}
+	return nil
+}
+
+// CdnPurgeConfig purges bid-query's surrogate key on every projected bid
+type CdnPurgeConfig struct {
+	IsEnabled bool
+	URL       string // {key} is replaced with the surrogate key, eg https://cdn.example.com/purge/{key}
+	Method    string // default: POST
+	Token     string `json:"-"` // sent as bearer token, env CDN_PURGE_TOKEN
+	TimeoutMs int    // default: 2000
+}
+
+func (c *CdnPurgeConfig) Validate() error {
+	if !c.IsEnabled {
+		return nil
+	}
+	var errs []error
+	if u, err := url.Parse(c.URL); err != nil || u.Host == "" {
+		errs = append(errs, config.Problem("URL", "%q, expected an absolute URL", c.URL))
+	}
+	if c.TimeoutMs < 0 {
+		errs = append(errs, config.Problem("TimeoutMs", "must not be negative"))
+	}
+	return errors.Join(errs...)
 }
@@ -3,7 +3,9 @@ package cfg
 import (
 	"fmt"
 	"kei-services/pkg/config"
+	"kei-services/pkg/infra/kafka"
 	"kei-services/pkg/infra/mongo"
+	"kei-services/pkg/infra/postgres"
 	"kei-services/pkg/infra/redis"
 	"log"
 	"os"
@@ -24,7 +26,10 @@ func Load(path string) (*Config, error) {
 	// env bindings
 	config.BindSsl(v)
 	redis.BindEnv(v)
+	kafka.BindEnv(v, "KAFKA", "kafkaReader")
 	mongo.BindMongoDb(v, "MONGO", "mongo")
+	postgres.BindPostgresDb(v, "PGDB", "postgres")
+	_ = v.BindEnv("cdnpurge.token", "CDN_PURGE_TOKEN")
 
 	if err := v.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("read config from %q: %w", path, err)
@@ -1,9 +1,12 @@
 package events
 
 import (
-	"encoding/json"
-	"fmt"
+	"kei-services/pkg/eventspb"
+	"kei-services/pkg/schema"
 	"time"
+
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // BidPlaced is a domain event emitted by the bid command service when a bid is accepted
@@ -13,19 +16,52 @@ type BidPlaced struct {
 	BidderID  string    `json:"bidderId"`
 	Amount    float64   `json:"amount"`
 	At        time.Time `json:"at"`
+	Seq       int64     `json:"seq,omitempty"` // bids table sequence, 0 from producers before it was added
 }
 
+const (
+	TopicBidsPlaced = "bids.placed"
+)
+
+// schemas are the event versions this service understands. Bump a schema's current version together with
+// its struct and register an upcaster lifting the previous version, eg
+//
+//	schema.Register[BidPlaced](r, TopicBidsPlaced, 2, map[int]schema.Upcaster{1: bidPlacedV1ToV2})
+var schemas = func() *schema.Registry {
+	r := schema.NewRegistry()
+	schema.Register[BidPlaced](r, TopicBidsPlaced, 1, nil)
+	schema.RegisterProto(r, TopicBidsPlaced, func() *eventspb.BidPlaced { return &eventspb.BidPlaced{} }, bidPlacedFromProto)
+	return r
+}()
+
+// Codec decodes JSON or protobuf events by their content-type, schema and schema-version headers, upcasting
+// older versions. Unknown content types, schemas or versions fail to decode and are dead-lettered
 type Codec struct{}
 
+// Decode decodes payload as the topic's schema at version 1, for messages without schema headers
 func (c *Codec) Decode(topic string, payload []byte) (any, error) {
-	switch topic {
-	case "bids.placed":
-		var e BidPlaced
-		if err := json.Unmarshal(payload, &e); err != nil {
-			return nil, err
-		}
-		return e, nil
-	default:
-		return nil, fmt.Errorf("unknown topic %s", topic)
+	return c.DecodeMessage(kafka.Message{Topic: topic, Value: payload})
+}
+
+func (c *Codec) DecodeMessage(msg kafka.Message) (any, error) {
+	return schemas.DecodeMessage(msg)
+}
+
+func bidPlacedFromProto(m *eventspb.BidPlaced) BidPlaced {
+	return BidPlaced{
+		AuctionID: m.GetAuctionId(),
+		BidID:     m.GetBidId(),
+		BidderID:  m.GetBidderId(),
+		Amount:    m.GetAmount(),
+		At:        asTime(m.GetAt()),
+		Seq:       m.GetSeq(),
+	}
+}
+
+// asTime keeps a missing timestamp as the zero time, like a missing JSON field
+func asTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
 	}
+	return ts.AsTime()
 }
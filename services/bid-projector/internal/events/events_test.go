@@ -2,10 +2,15 @@ package events
 
 import (
 	"encoding/json"
+	"kei-services/pkg/eventspb"
+	"kei-services/pkg/schema"
 	"testing"
 	"time"
 
+	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestCodec_Decode_BidPlaced(t *testing.T) {
@@ -51,3 +56,61 @@ func TestCodec_Decode_BidPlaced(t *testing.T) {
 		assert.Contains(t, err.Error(), "unknown topic")
 	})
 }
+
+func TestCodec_DecodeMessage_SchemaVersion(t *testing.T) {
+	codec := &Codec{}
+	payload := []byte(`{"auctionId":"auction-1","bidId":"bid-1","bidderId":"bidder-1","amount":120.5,"at":"2024-01-01T12:00:00Z"}`)
+
+	headers := func(version string) []kafka.Header {
+		return []kafka.Header{
+			{Key: "content-type", Value: []byte("application/json")},
+			{Key: "schema", Value: []byte("bids.placed")},
+			{Key: "schema-version", Value: []byte(version)},
+		}
+	}
+
+	t.Run("current version decodes", func(t *testing.T) {
+		decoded, err := codec.DecodeMessage(kafka.Message{Topic: "bids.placed", Value: payload, Headers: headers("1")})
+		assert.NoError(t, err)
+		assert.Equal(t, "bid-1", decoded.(BidPlaced).BidID)
+	})
+
+	t.Run("unknown version returns error", func(t *testing.T) {
+		_, err := codec.DecodeMessage(kafka.Message{Topic: "bids.placed", Value: payload, Headers: headers("99")})
+		assert.ErrorIs(t, err, schema.ErrUnknownVersion)
+	})
+}
+
+func TestCodec_DecodeMessage_Protobuf(t *testing.T) {
+	codec := &Codec{}
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	payload, err := proto.Marshal(&eventspb.BidPlaced{
+		AuctionId: "auction-1",
+		BidId:     "bid-1",
+		BidderId:  "bidder-1",
+		Amount:    120.5,
+		At:        timestamppb.New(at),
+		Seq:       7,
+	})
+	assert.NoError(t, err)
+
+	decoded, err := codec.DecodeMessage(kafka.Message{
+		Topic: "bids.placed",
+		Value: payload,
+		Headers: []kafka.Header{
+			{Key: "content-type", Value: []byte("application/x-protobuf")},
+			{Key: "schema", Value: []byte("bids.placed")},
+			{Key: "schema-version", Value: []byte("1")},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, BidPlaced{
+		AuctionID: "auction-1",
+		BidID:     "bid-1",
+		BidderID:  "bidder-1",
+		Amount:    120.5,
+		At:        at,
+		Seq:       7,
+	}, decoded)
+}
@@ -0,0 +1,130 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"kei-services/services/bid-projector/internal/events"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxPendingPurges bounds the keys waiting for the worker, further auctions are dropped until it catches up
+const maxPendingPurges = 10000
+
+// SurrogatePurge purges the CDN surrogate key bid-query tags list bids pages with, so cached pages are
+// dropped as soon as a new bid is projected. Register it after the mongo and version projections.
+//
+// Purging is best effort and off the handler chain: OnBidsPlaced only queues the key and Run purges it,
+// so a slow CDN never delays or retries the projection. Bursts on one auction collapse into a single purge,
+// dropped or failed purges are logged, cached pages still expire via Surrogate-Control
+type SurrogatePurge struct {
+	url    string // {key} is replaced with the surrogate key
+	method string
+	token  string
+	client *http.Client
+	log    *zap.Logger
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+	wake    chan struct{}
+}
+
+func NewSurrogatePurge(purgeURL, method, token string, timeout time.Duration, log *zap.Logger) *SurrogatePurge {
+	if method == "" {
+		method = http.MethodPost
+	}
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &SurrogatePurge{
+		url:     purgeURL,
+		method:  method,
+		token:   token,
+		client:  &http.Client{Timeout: timeout},
+		log:     log,
+		pending: make(map[string]struct{}),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// surrogateKey must match bid-query's SurrogateKey
+func surrogateKey(auctionID string) string { return "bids-" + auctionID }
+
+// OnBidsPlaced queues the auction's surrogate key and never fails
+func (p *SurrogatePurge) OnBidsPlaced(_ context.Context, e events.BidPlaced) error {
+	key := surrogateKey(e.AuctionID)
+
+	p.mu.Lock()
+	_, queued := p.pending[key]
+	full := !queued && len(p.pending) >= maxPendingPurges
+	if !full {
+		p.pending[key] = struct{}{}
+	}
+	p.mu.Unlock()
+
+	if full {
+		p.log.Warn("purge queue full, surrogate key dropped", zap.String("key", key))
+		return nil
+	}
+	select {
+	case p.wake <- struct{}{}:
+	default: // worker already signalled
+	}
+	return nil
+}
+
+// Run purges queued keys until ctx is done, keys still queued then are dropped
+func (p *SurrogatePurge) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.wake:
+		}
+
+		p.mu.Lock()
+		keys := p.pending
+		p.pending = make(map[string]struct{}, len(keys))
+		p.mu.Unlock()
+
+		for key := range keys {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := p.purge(ctx, key); err != nil {
+				p.log.Warn("purge surrogate key failed", zap.String("key", key), zap.Error(err))
+				continue
+			}
+			p.log.Debug("surrogate key purged", zap.String("key", key))
+		}
+	}
+}
+
+func (p *SurrogatePurge) purge(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, p.method, strings.ReplaceAll(p.url, "{key}", url.PathEscape(key)), nil)
+	if err != nil {
+		return err
+	}
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	req.Header.Set("Surrogate-Key", key)
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 4<<10))
+
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("purge: unexpected status %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package cdn
+
+import (
+	"context"
+	"kei-services/services/bid-projector/internal/events"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestSurrogatePurge(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		purged []string
+	)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		mu.Lock()
+		purged = append(purged, r.Header.Get("Surrogate-Key"))
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	p := NewSurrogatePurge(srv.URL+"/purge/{key}", "", "", time.Second, zap.NewNop())
+
+	// the handler returns while the CDN is still stuck
+	for _, id := range []string{"a_1", "a_1", "a_2", "a_1"} {
+		assert.NoError(t, p.OnBidsPlaced(context.Background(), events.BidPlaced{AuctionID: id}))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() { p.Run(ctx); close(done) }()
+	close(release)
+
+	// a burst on one auction collapses into a single purge
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(purged) == 2
+	}, time.Second, 10*time.Millisecond)
+	mu.Lock()
+	assert.ElementsMatch(t, []string{"bids-a_1", "bids-a_2"}, purged)
+	mu.Unlock()
+
+	cancel()
+	<-done
+}
+
+func TestSurrogatePurge_Failure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	p := NewSurrogatePurge(srv.URL, http.MethodPost, "", time.Second, zap.NewNop())
+
+	assert.Error(t, p.purge(context.Background(), "bids-a_1"))
+	// failures never reach the projector, the bid must not be retried or dead-lettered
+	assert.NoError(t, p.OnBidsPlaced(context.Background(), events.BidPlaced{AuctionID: "a_1"}))
+}
@@ -3,6 +3,7 @@ package mongo
 import (
 	"context"
 	"errors"
+	mongoInfra "kei-services/pkg/infra/mongo"
 	"kei-services/services/bid-projector/internal/events"
 	"time"
 
@@ -12,20 +13,24 @@ import (
 	"go.uber.org/zap"
 )
 
+// BidsHistory is the logical name of the bids read model, rebuilds write to a new physical collection
+// and switch the pointer in read_model_meta once they caught up.
+const BidsHistory = "bids_history"
+
 type Projection struct {
-	log *zap.Logger
-	db  *mongo.Database
+	log  *zap.Logger
+	coll mongoInfra.CollectionSource
 }
 
-func NewProjection(db *mongo.Database, log *zap.Logger) *Projection {
+func NewProjection(coll mongoInfra.CollectionSource, log *zap.Logger) *Projection {
 	return &Projection{
-		log: log,
-		db:  db,
+		log:  log,
+		coll: coll,
 	}
 }
 
 func (p *Projection) OnBidsPlaced(ctx context.Context, evt events.BidPlaced) error {
-	bidsColl := p.db.Collection("bids_history")
+	bidsColl := p.coll.Collection()
 
 	if err := p.insertBidDoc(ctx, bidsColl, evt); err != nil {
 		if isDupKey(err) {
@@ -54,9 +59,39 @@ func (p *Projection) insertBidDoc(ctx context.Context, coll *mongo.Collection, e
 	return err
 }
 
+// InsertBatch bulk inserts evts unordered, bids that already exist are skipped. Used by rebuilds
+// seeding from Postgres
+func (p *Projection) InsertBatch(ctx context.Context, evts []events.BidPlaced) error {
+	if len(evts) == 0 {
+		return nil
+	}
+	docs := make([]any, 0, len(evts))
+	for _, evt := range evts {
+		docs = append(docs, BidDoc{
+			AuctionID: evt.AuctionID,
+			BidID:     evt.BidID,
+			BidderID:  evt.BidderID,
+			Amount:    evt.Amount,
+			At:        evt.At.UTC(),
+		})
+	}
+
+	cctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := p.coll.Collection().InsertMany(cctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil && !onlyDupKeys(err) {
+		return err
+	}
+	return nil
+}
+
 func (p *Projection) EnsureIndexes(ctx context.Context) error {
-	bids := p.db.Collection("bids_history")
+	return EnsureIndexes(ctx, p.coll.Collection())
+}
 
+// EnsureIndexes creates the bids_history indexes on coll, it's idempotent.
+func EnsureIndexes(ctx context.Context, bids *mongo.Collection) error {
 	// bids_history: unique bid_id + auction_id, at DESC for listing
 	_, err := bids.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{
@@ -86,3 +121,16 @@ func isDupKey(err error) bool {
 	}
 	return false
 }
+
+func onlyDupKeys(err error) bool {
+	var bwe mongo.BulkWriteException
+	if !errors.As(err, &bwe) || bwe.WriteConcernError != nil || len(bwe.WriteErrors) == 0 {
+		return false
+	}
+	for _, e := range bwe.WriteErrors {
+		if e.Code != 11000 {
+			return false
+		}
+	}
+	return true
+}
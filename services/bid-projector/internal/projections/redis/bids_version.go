@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"context"
+	"kei-services/services/bid-projector/internal/events"
+	"time"
+
+	goRedis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// BidsVersionProjection bumps a per-auction version key after a bid is written to bids_history.
+// bid-query uses it to key its first page cache and derive ETags, register it after the mongo projection.
+type BidsVersionProjection struct {
+	keyPrefix string
+	ttl       time.Duration
+	redis     goRedis.UniversalClient
+	log       *zap.Logger
+}
+
+func NewBidsVersionProjection(r goRedis.UniversalClient, log *zap.Logger, ttl time.Duration) *BidsVersionProjection {
+	return &BidsVersionProjection{
+		keyPrefix: "bids:version:",
+		ttl:       ttl,
+		redis:     r,
+		log:       log,
+	}
+}
+
+func (p *BidsVersionProjection) key(id string) string { return p.keyPrefix + id }
+
+func (p *BidsVersionProjection) OnBidsPlaced(ctx context.Context, e events.BidPlaced) error {
+	return p.Bump(ctx, e.AuctionID)
+}
+
+// Bump increments the auction's version, rebuilds call it after switching collections so cached pages
+// and ETags of the old read model stop validating.
+func (p *BidsVersionProjection) Bump(ctx context.Context, auctionID string) error {
+	cctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	pipe := p.redis.TxPipeline()
+	incr := pipe.Incr(cctx, p.key(auctionID))
+	if p.ttl > 0 {
+		pipe.Expire(cctx, p.key(auctionID), p.ttl)
+	}
+	if _, err := pipe.Exec(cctx); err != nil {
+		p.log.Warn("bump bids version failed", zap.String("auctionID", auctionID), zap.Error(err))
+		return err
+	}
+
+	p.log.Debug("bids version bumped", zap.String("auctionID", auctionID), zap.Int64("version", incr.Val()))
+	return nil
+}
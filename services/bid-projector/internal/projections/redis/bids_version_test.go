@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"kei-services/services/bid-projector/internal/events"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestBidsVersionProjection_OnBidsPlaced(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		setup   func(r redismock.ClientMock)
+		wantErr bool
+	}{
+		{
+			name: "bumps and refreshes the ttl",
+			ttl:  7 * 24 * time.Hour,
+			setup: func(r redismock.ClientMock) {
+				r.ExpectTxPipeline()
+				r.ExpectIncr("bids:version:a_1").SetVal(3)
+				r.ExpectExpire("bids:version:a_1", 7*24*time.Hour).SetVal(true)
+				r.ExpectTxPipelineExec()
+			},
+		},
+		{
+			name: "no ttl",
+			setup: func(r redismock.ClientMock) {
+				r.ExpectTxPipeline()
+				r.ExpectIncr("bids:version:a_1").SetVal(1)
+				r.ExpectTxPipelineExec()
+			},
+		},
+		{
+			name: "redis error is returned so the event is retried",
+			ttl:  time.Hour,
+			setup: func(r redismock.ClientMock) {
+				r.ExpectTxPipeline()
+				r.ExpectIncr("bids:version:a_1").SetErr(errors.New("connection refused"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, r := redismock.NewClientMock()
+			tt.setup(r)
+
+			p := NewBidsVersionProjection(db, zap.NewNop(), tt.ttl)
+			err := p.OnBidsPlaced(ctx, events.BidPlaced{AuctionID: "a_1"})
+
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.NoError(t, r.ExpectationsWereMet())
+		})
+	}
+}
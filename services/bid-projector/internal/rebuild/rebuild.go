@@ -0,0 +1,206 @@
+// Package rebuild rebuilds bids_history into a fresh collection next to the live one and switches bid-query
+// over once it caught up (blue/green). The old collection is left in place for the operator to drop.
+package rebuild
+
+import (
+	"context"
+	"fmt"
+	kafkaInfra "kei-services/pkg/infra/kafka"
+	mongoInfra "kei-services/pkg/infra/mongo"
+	"kei-services/pkg/projector"
+	"kei-services/services/bid-projector/internal/events"
+	mongoProjection "kei-services/services/bid-projector/internal/projections/mongo"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Source string
+
+const (
+	// SourceKafka replays the topics from the first retained offset, only complete while retention covers
+	// every bid
+	SourceKafka Source = "kafka"
+	// SourcePostgres copies the bids table, then replays what was published after the copy started
+	SourcePostgres Source = "postgres"
+)
+
+func ParseSource(s string) (Source, error) {
+	switch Source(s) {
+	case "", SourceKafka:
+		return SourceKafka, nil
+	case SourcePostgres:
+		return SourcePostgres, nil
+	default:
+		return "", fmt.Errorf("unknown rebuild source %q, expected kafka or postgres", s)
+	}
+}
+
+// IVersionBumper bumps an auction's bids version so bid-query's cached pages and ETags of the old
+// collection stop validating
+type IVersionBumper interface {
+	Bump(ctx context.Context, auctionID string) error
+}
+
+type Deps struct {
+	Mongo    *mongo.Database
+	Postgres *gorm.DB // required for SourcePostgres
+	Versions IVersionBumper
+	Brokers  []string
+	Security *kafkaInfra.Security
+	Topics   []string
+
+	// NewProjector builds a projector for router with the service's retry and dead-letter options
+	NewProjector func(router *projector.Router) *projector.Projector
+}
+
+// Run rebuilds bids_history from src and returns the physical collection it switched to
+func Run(ctx context.Context, d Deps, src Source, log *zap.Logger) (string, error) {
+	previous, err := mongoInfra.ActiveCollectionName(ctx, d.Mongo, mongoProjection.BidsHistory)
+	if err != nil {
+		return "", err
+	}
+
+	target := d.Mongo.Collection(fmt.Sprintf("%s_%s", mongoProjection.BidsHistory,
+		time.Now().UTC().Format("20060102T150405Z")))
+	log = log.With(zap.String("collection", target.Name()), zap.String("previous", previous))
+	log.Info("rebuild starting", zap.String("source", string(src)))
+
+	if err = mongoProjection.EnsureIndexes(ctx, target); err != nil {
+		return "", fmt.Errorf("ensure indexes: %w", err)
+	}
+
+	projection := mongoProjection.NewProjection(mongoInfra.Static(target), log)
+	seen := &auctions{ids: map[string]struct{}{}}
+	codec := &events.Codec{}
+	router := projector.NewRouter().Use(projector.Logging(log))
+	projector.Topic(router, events.TopicBidsPlaced, projector.As[events.BidPlaced](codec.DecodeMessage)).
+		Handle("mongo", projection.OnBidsPlaced).
+		Handle("rebuild-auctions", seen.OnBidsPlaced)
+	p := d.NewProjector(router)
+
+	plan := projector.RebuildPlan{
+		Topics: d.Topics,
+		Switch: func(ctx context.Context) error {
+			return mongoInfra.SwitchActiveCollection(ctx, d.Mongo, mongoProjection.BidsHistory, target.Name())
+		},
+		Grace: 3 * mongoInfra.DefaultRefreshInterval,
+	}
+
+	if src == SourcePostgres {
+		// offsets first: every bid committed after the copy's snapshot is published after them
+		if plan.From, err = kafkaInfra.EndOffsets(ctx, d.Brokers, d.Security, d.Topics); err != nil {
+			return "", err
+		}
+		if err = copyFromPostgres(ctx, d.Postgres, projection, seen, log); err != nil {
+			return "", err
+		}
+	}
+
+	if err = p.Rebuild(ctx, d.Brokers, d.Security, plan); err != nil {
+		return "", err
+	}
+
+	// readers moved over, invalidate what they cached from the previous collection
+	ids := seen.list()
+	for _, id := range ids {
+		if err = d.Versions.Bump(ctx, id); err != nil {
+			log.Warn("bump bids version after rebuild", zap.String("auctionID", id), zap.Error(err))
+		}
+	}
+
+	log.Info("rebuild done, drop the previous collection once no longer needed", zap.Int("auctions", len(ids)))
+	return target.Name(), nil
+}
+
+// copyBatch is the number of rows read per keyset page
+const copyBatch = 1000
+
+type bidRow struct {
+	ID        string
+	AuctionID string
+	BidderID  string
+	Amount    float64
+	At        time.Time
+	Seq       int64
+}
+
+// copyFromPostgres copies bids up to the highest seq at start, keyset paginated on seq
+func copyFromPostgres(ctx context.Context, db *gorm.DB, projection *mongoProjection.Projection, seen *auctions,
+	log *zap.Logger) error {
+	if db == nil {
+		return fmt.Errorf("rebuild from postgres: no postgres configured")
+	}
+
+	var maxSeq int64
+	if err := db.WithContext(ctx).Raw(`SELECT COALESCE(MAX(seq), 0) FROM bids`).Scan(&maxSeq).Error; err != nil {
+		return fmt.Errorf("max seq: %w", err)
+	}
+	log.Info("copying bids from postgres", zap.Int64("maxSeq", maxSeq))
+
+	var after, copied int64
+	for after < maxSeq {
+		var rows []bidRow
+		err := db.WithContext(ctx).Raw(`SELECT id::text AS id, auction_id, bidder_id, amount::float8 AS amount, at, seq
+			FROM bids WHERE seq > ? AND seq <= ? ORDER BY seq LIMIT ?`, after, maxSeq, copyBatch).
+			Scan(&rows).Error
+		if err != nil {
+			return fmt.Errorf("read bids after seq %d: %w", after, err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		batch := make([]events.BidPlaced, 0, len(rows))
+		for _, r := range rows {
+			batch = append(batch, events.BidPlaced{
+				AuctionID: r.AuctionID,
+				BidID:     r.ID,
+				BidderID:  r.BidderID,
+				Amount:    r.Amount,
+				At:        r.At,
+			})
+			seen.add(r.AuctionID)
+		}
+		if err = projection.InsertBatch(ctx, batch); err != nil {
+			return fmt.Errorf("insert bids after seq %d: %w", after, err)
+		}
+
+		after = rows[len(rows)-1].Seq
+		copied += int64(len(rows))
+		log.Debug("copied bids", zap.Int64("copied", copied), zap.Int64("seq", after))
+	}
+
+	log.Info("postgres copy done", zap.Int64("bids", copied))
+	return nil
+}
+
+// auctions records the auctions a rebuild touched, registered as the last handler
+type auctions struct {
+	mu  sync.Mutex
+	ids map[string]struct{}
+}
+
+func (a *auctions) OnBidsPlaced(_ context.Context, e events.BidPlaced) error {
+	a.add(e.AuctionID)
+	return nil
+}
+
+func (a *auctions) add(id string) {
+	a.mu.Lock()
+	a.ids[id] = struct{}{}
+	a.mu.Unlock()
+}
+
+func (a *auctions) list() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]string, 0, len(a.ids))
+	for id := range a.ids {
+		out = append(out, id)
+	}
+	return out
+}
@@ -8,6 +8,8 @@ import (
 	mongoInfra "kei-services/pkg/infra/mongo"
 	"kei-services/pkg/infra/redis"
 	"kei-services/pkg/logger"
+	"kei-services/pkg/shutdown"
+	"kei-services/pkg/tracing"
 	"kei-services/services/bid-query/internal/cfg"
 	"kei-services/services/bid-query/internal/server"
 
@@ -15,12 +17,13 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
 )
 
+var checkConfig = flag.Bool("check-config", false, "validate the config, print every problem found and exit")
+
 func main() {
 	flag.Parse() // parse -config flag
 	_ = godotenv.Load(".env")
@@ -28,6 +31,7 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	config.MustValidate(config.Path(), cfg, *checkConfig)
 
 	log := logger.Init(cfg.Logger, cfg.App)
 	if log == nil {
@@ -37,27 +41,36 @@ func main() {
 
 	log.Info("Starting App", zap.String("version", cfg.App.Version))
 
+	// phases run in reverse, clients registered as they are created close after what uses them stopped
+	sd := shutdown.New(shutdown.DefaultTimeout, log)
+	defer func() { _ = sd.Shutdown() }()
+
+	// tracing, flushed on exit
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, "bid-query", cfg.App, log)
+	if err != nil {
+		log.Fatal("init tracing", zap.Error(err))
+	}
+	sd.Add("tracing", shutdownTracing)
+
 	// mongo
 	mc, err := mongoInfra.NewClient(cfg.Mongo, log)
 	if err != nil {
 		log.Fatal("mongo client", zap.Error(err))
 	}
-	defer func() {
-		if err := mc.Disconnect(context.Background()); err != nil {
-			log.Error("disconnect mongo client", zap.Error(err))
-		}
-	}()
+	sd.Add("mongo", mc.Disconnect)
 
 	// Redis
 	redisClient, err := redis.Client(cfg.Redis, log)
 	if err != nil {
 		log.Fatal("redis new client", zap.Error(err))
 	}
-	defer func() { _ = redisClient.Close() }()
+	sd.AddCloser("redis", redisClient)
 
 	// Create and start server
 	s := server.New(mc.DB, redisClient, cfg, log)
 
+	sd.Add("http server", func(ctx context.Context) error { return server.Shutdown(ctx, s, log) })
+
 	errCh := make(chan error, 1)
 	go func() {
 		errCh <- server.Start(s, cfg, log)
@@ -73,12 +86,10 @@ func main() {
 		log.Info("shutdown signal received")
 	case err = <-errCh:
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			_ = sd.Shutdown()
 			log.Fatal("http: server error", zap.Error(err))
 		}
 	}
 
-	// graceful shutdown
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	_ = server.Shutdown(shutdownCtx, s, log)
+	_ = sd.Shutdown()
 }
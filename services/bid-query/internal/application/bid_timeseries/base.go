@@ -0,0 +1,69 @@
+package bid_timeseries
+
+import (
+	"context"
+	"time"
+)
+
+type IService interface {
+	Handle(ctx context.Context, q Query) (*Result, error)
+}
+
+// Interval is the bucket width
+type Interval string
+
+const (
+	Interval1m  Interval = "1m"
+	Interval5m  Interval = "5m"
+	Interval15m Interval = "15m"
+	Interval1h  Interval = "1h"
+	Interval1d  Interval = "1d"
+)
+
+var intervals = map[Interval]time.Duration{
+	Interval1m:  time.Minute,
+	Interval5m:  5 * time.Minute,
+	Interval15m: 15 * time.Minute,
+	Interval1h:  time.Hour,
+	Interval1d:  24 * time.Hour,
+}
+
+// ParseInterval returns Interval1m for an empty string
+func ParseInterval(s string) (Interval, error) {
+	if s == "" {
+		return Interval1m, nil
+	}
+	i := Interval(s)
+	if _, ok := intervals[i]; !ok {
+		return "", ErrInvalidInterval
+	}
+	return i, nil
+}
+
+func (i Interval) Duration() time.Duration {
+	return intervals[i]
+}
+
+type Query struct {
+	AuctionID string
+	Interval  Interval
+	From      *time.Time // inclusive, nil = first bid
+	To        *time.Time // exclusive, nil = latest bid
+}
+
+// Bucket is a price candle, Open/Close are the first/last accepted amounts in the interval
+type Bucket struct {
+	Start time.Time
+	Count int64
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
+}
+
+type Result struct {
+	AuctionID string
+	Interval  Interval
+	Buckets   []Bucket // ascending by Start, empty intervals omitted
+	Truncated bool     // more buckets exist, before the first one when From is nil, else after the last one
+}
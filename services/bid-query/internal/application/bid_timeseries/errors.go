@@ -0,0 +1,8 @@
+package bid_timeseries
+
+import "errors"
+
+var (
+	ErrInvalidInterval = errors.New("invalid_interval")
+	ErrInvalidRange    = errors.New("invalid_range")
+)
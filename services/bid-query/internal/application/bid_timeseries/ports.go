@@ -0,0 +1,12 @@
+package bid_timeseries
+
+import (
+	"context"
+	"time"
+)
+
+type IBidTimeseriesRepository interface {
+	// Buckets returns up to limit non-empty buckets in ascending order, the earliest from from, or the
+	// latest when from is nil
+	Buckets(ctx context.Context, auctionID string, interval time.Duration, from, to *time.Time, limit int) ([]Bucket, error)
+}
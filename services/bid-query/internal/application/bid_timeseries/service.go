@@ -0,0 +1,68 @@
+package bid_timeseries
+
+import (
+	"context"
+	"fmt"
+	"kei-services/pkg/middleware"
+
+	"go.uber.org/zap"
+)
+
+// maxBuckets caps a single response, a day of 1m buckets is 1440
+const maxBuckets = 5000
+
+type Service struct {
+	repo IBidTimeseriesRepository
+	log  *zap.Logger
+}
+
+var _ IService = (*Service)(nil)
+
+type Deps struct {
+	TimeseriesRepo IBidTimeseriesRepository
+}
+
+func NewService(d Deps, log *zap.Logger) *Service {
+	return &Service{
+		repo: d.TimeseriesRepo,
+		log:  log,
+	}
+}
+
+func (s *Service) Handle(ctx context.Context, q Query) (*Result, error) {
+	log := middleware.LoggerFrom(ctx, s.log).With(
+		zap.String("auctionId", q.AuctionID),
+		zap.String("interval", string(q.Interval)),
+	)
+
+	if _, ok := intervals[q.Interval]; !ok {
+		return nil, ErrInvalidInterval
+	}
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		return nil, ErrInvalidRange
+	}
+
+	// fetch one extra to detect truncation
+	buckets, err := s.repo.Buckets(ctx, q.AuctionID, q.Interval.Duration(), q.From, q.To, maxBuckets+1)
+	if err != nil {
+		log.Warn("aggregate bid buckets failed", zap.Error(err))
+		return nil, fmt.Errorf("bid timeseries: %w", err)
+	}
+
+	truncated := len(buckets) > maxBuckets
+	switch {
+	case truncated && q.From == nil:
+		buckets = buckets[len(buckets)-maxBuckets:] // the repo kept the latest, drop the oldest extra
+	case truncated:
+		buckets = buckets[:maxBuckets]
+	}
+
+	log.Debug("bid timeseries: returning result", zap.Int("buckets", len(buckets)), zap.Bool("truncated", truncated))
+
+	return &Result{
+		AuctionID: q.AuctionID,
+		Interval:  q.Interval,
+		Buckets:   buckets,
+		Truncated: truncated,
+	}, nil
+}
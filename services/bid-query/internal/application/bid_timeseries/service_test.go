@@ -0,0 +1,150 @@
+package bid_timeseries
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockBidTimeseriesRepository struct {
+	mock.Mock
+}
+
+func (m *MockBidTimeseriesRepository) Buckets(ctx context.Context, auctionID string, interval time.Duration,
+	from, to *time.Time, limit int) ([]Bucket, error) {
+	args := m.Called(ctx, auctionID, interval, from, to, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Bucket), args.Error(1)
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Interval
+		wantErr  bool
+	}{
+		{input: "", expected: Interval1m},
+		{input: "1m", expected: Interval1m},
+		{input: "5m", expected: Interval5m},
+		{input: "15m", expected: Interval15m},
+		{input: "1h", expected: Interval1h},
+		{input: "1d", expected: Interval1d},
+		{input: "2m", wantErr: true},
+		{input: "1w", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseInterval(tt.input)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidInterval))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestService_Handle_Success(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	buckets := []Bucket{
+		{Start: start, Count: 3, Open: 100, High: 120, Low: 100, Close: 120},
+		{Start: start.Add(5 * time.Minute), Count: 1, Open: 130, High: 130, Low: 130, Close: 130},
+	}
+
+	mockRepo := new(MockBidTimeseriesRepository)
+	mockRepo.On("Buckets", ctx, "auction-1", 5*time.Minute, (*time.Time)(nil), (*time.Time)(nil), maxBuckets+1).
+		Return(buckets, nil)
+
+	service := NewService(Deps{TimeseriesRepo: mockRepo}, zap.NewNop())
+
+	result, err := service.Handle(ctx, Query{AuctionID: "auction-1", Interval: Interval5m})
+
+	assert.NoError(t, err)
+	assert.Equal(t, buckets, result.Buckets)
+	assert.Equal(t, Interval5m, result.Interval)
+	assert.False(t, result.Truncated)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_Handle_Truncated(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	buckets := make([]Bucket, maxBuckets+1)
+	for i := range buckets {
+		buckets[i].Start = start.Add(time.Duration(i) * time.Minute)
+	}
+
+	tests := []struct {
+		name      string
+		from      *time.Time
+		wantFirst time.Time
+	}{
+		// the repo returned the latest buckets, the oldest one is the extra
+		{name: "without from keeps the latest", from: nil, wantFirst: buckets[1].Start},
+		{name: "with from keeps the earliest", from: &start, wantFirst: buckets[0].Start},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockBidTimeseriesRepository)
+			mockRepo.On("Buckets", ctx, "auction-1", time.Minute, tt.from, (*time.Time)(nil), maxBuckets+1).
+				Return(buckets, nil)
+
+			service := NewService(Deps{TimeseriesRepo: mockRepo}, zap.NewNop())
+
+			result, err := service.Handle(ctx, Query{AuctionID: "auction-1", Interval: Interval1m, From: tt.from})
+
+			assert.NoError(t, err)
+			assert.Len(t, result.Buckets, maxBuckets)
+			assert.Equal(t, tt.wantFirst, result.Buckets[0].Start)
+			assert.True(t, result.Truncated)
+		})
+	}
+}
+
+func TestService_Handle_Validation(t *testing.T) {
+	from := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	to := from.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		query   Query
+		wantErr error
+	}{
+		{
+			name:    "unknown interval",
+			query:   Query{AuctionID: "auction-1", Interval: "3m"},
+			wantErr: ErrInvalidInterval,
+		},
+		{
+			name:    "from after to",
+			query:   Query{AuctionID: "auction-1", Interval: Interval1m, From: &from, To: &to},
+			wantErr: ErrInvalidRange,
+		},
+		{
+			name:    "from equals to",
+			query:   Query{AuctionID: "auction-1", Interval: Interval1m, From: &from, To: &from},
+			wantErr: ErrInvalidRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewService(Deps{TimeseriesRepo: new(MockBidTimeseriesRepository)}, zap.NewNop())
+
+			result, err := service.Handle(context.Background(), tt.query)
+
+			assert.Nil(t, result)
+			assert.True(t, errors.Is(err, tt.wantErr))
+		})
+	}
+}
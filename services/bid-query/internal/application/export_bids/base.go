@@ -0,0 +1,24 @@
+package export_bids
+
+import (
+	"context"
+	"time"
+)
+
+type IService interface {
+	// Handle streams every bid of an auction to emit, in order, without buffering the history
+	Handle(ctx context.Context, q Query, emit func(Row) error) (rows int, err error)
+}
+
+type Query struct {
+	AuctionID string
+	Asc       bool // default newest first
+}
+
+type Row struct {
+	BidID     string
+	AuctionID string
+	BidderID  string
+	Amount    float64
+	At        time.Time
+}
@@ -0,0 +1,7 @@
+package export_bids
+
+import "errors"
+
+var (
+	ErrInvalidAuctionID = errors.New("invalid_auction_id")
+)
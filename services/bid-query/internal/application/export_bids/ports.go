@@ -0,0 +1,10 @@
+package export_bids
+
+import (
+	"context"
+)
+
+type IBidStreamRepository interface {
+	// StreamByAuction calls fn once per bid, ordered by (at, bidId), stops at the first error returned by fn
+	StreamByAuction(ctx context.Context, auctionID string, asc bool, fn func(Row) error) error
+}
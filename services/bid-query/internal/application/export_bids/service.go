@@ -0,0 +1,55 @@
+package export_bids
+
+import (
+	"context"
+	"fmt"
+	"kei-services/pkg/middleware"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	bidStreamRepo IBidStreamRepository
+	log           *zap.Logger
+}
+
+var _ IService = (*Service)(nil)
+
+type Deps struct {
+	BidStreamRepo IBidStreamRepository
+}
+
+func NewService(d Deps, log *zap.Logger) *Service {
+	return &Service{
+		bidStreamRepo: d.BidStreamRepo,
+		log:           log,
+	}
+}
+
+func (s *Service) Handle(ctx context.Context, q Query, emit func(Row) error) (int, error) {
+	log := middleware.LoggerFrom(ctx, s.log).With(
+		zap.String("auctionId", q.AuctionID),
+		zap.Bool("asc", q.Asc),
+	)
+
+	if strings.TrimSpace(q.AuctionID) == "" {
+		return 0, ErrInvalidAuctionID
+	}
+
+	rows := 0
+	err := s.bidStreamRepo.StreamByAuction(ctx, q.AuctionID, q.Asc, func(r Row) error {
+		if err := emit(r); err != nil {
+			return err
+		}
+		rows++
+		return nil
+	})
+	if err != nil {
+		log.Warn("export bids failed", zap.Int("rows", rows), zap.Error(err))
+		return rows, fmt.Errorf("export bids: %w", err)
+	}
+
+	log.Debug("export bids: done", zap.Int("rows", rows))
+	return rows, nil
+}
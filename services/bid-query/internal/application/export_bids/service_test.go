@@ -0,0 +1,76 @@
+package export_bids
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockBidStreamRepository struct {
+	mock.Mock
+	rows []Row
+}
+
+func (m *MockBidStreamRepository) StreamByAuction(ctx context.Context, auctionID string, asc bool, fn func(Row) error) error {
+	args := m.Called(ctx, auctionID, asc)
+	for _, r := range m.rows {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return args.Error(0)
+}
+
+func TestService_Handle_StreamsAllRows(t *testing.T) {
+	ctx := context.Background()
+	fixedTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	mockRepo := &MockBidStreamRepository{rows: []Row{
+		{BidID: "bid-1", AuctionID: "auction-1", BidderID: "bidder-1", Amount: 100, At: fixedTime},
+		{BidID: "bid-2", AuctionID: "auction-1", BidderID: "bidder-2", Amount: 110, At: fixedTime.Add(time.Minute)},
+	}}
+	mockRepo.On("StreamByAuction", ctx, "auction-1", true).Return(nil)
+
+	service := NewService(Deps{BidStreamRepo: mockRepo}, zap.NewNop())
+
+	var got []string
+	rows, err := service.Handle(ctx, Query{AuctionID: "auction-1", Asc: true}, func(r Row) error {
+		got = append(got, r.BidID)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rows)
+	assert.Equal(t, []string{"bid-1", "bid-2"}, got)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_Handle_EmitErrorStopsStream(t *testing.T) {
+	ctx := context.Background()
+	writeErr := errors.New("client went away")
+
+	mockRepo := &MockBidStreamRepository{rows: []Row{{BidID: "bid-1"}, {BidID: "bid-2"}}}
+	mockRepo.On("StreamByAuction", ctx, "auction-1", false).Return(nil)
+
+	service := NewService(Deps{BidStreamRepo: mockRepo}, zap.NewNop())
+
+	rows, err := service.Handle(ctx, Query{AuctionID: "auction-1"}, func(r Row) error {
+		return writeErr
+	})
+
+	assert.True(t, errors.Is(err, writeErr))
+	assert.Equal(t, 0, rows)
+}
+
+func TestService_Handle_EmptyAuctionID(t *testing.T) {
+	service := NewService(Deps{BidStreamRepo: new(MockBidStreamRepository)}, zap.NewNop())
+
+	_, err := service.Handle(context.Background(), Query{AuctionID: " "}, func(Row) error { return nil })
+
+	assert.True(t, errors.Is(err, ErrInvalidAuctionID))
+}
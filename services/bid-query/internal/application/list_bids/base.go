@@ -7,6 +7,9 @@ import (
 
 type IService interface {
 	Handle(ctx context.Context, q Query) (*Result, error)
+	// ETag returns the validator Handle would return for q without querying the read model,
+	// empty when it can't be computed cheaply
+	ETag(ctx context.Context, q Query) (string, error)
 }
 
 // Direction (server-side sort by sequence)
@@ -47,4 +50,5 @@ type Result struct {
 	Items      []Item
 	NextCursor *string // nil when no more
 	HasMore    bool
+	ETag       string // strong validator, quoted
 }
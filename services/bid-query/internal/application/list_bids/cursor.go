@@ -1,48 +1,195 @@
 package list_bids
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// cursorVersion is bumped whenever the envelope layout changes, older versions are rejected
+const cursorVersion = 1
+
 type Cursor struct {
 	At time.Time `json:"at"`
 	ID string    `json:"id"`
 }
 
-func encodeCursor(c *Cursor) (*string, error) {
+// CursorKey is a HMAC key used to sign cursors, ID is embedded in the cursor so old keys can still verify
+type CursorKey struct {
+	ID     string
+	Secret []byte
+}
+
+// CursorCodec signs and verifies pagination cursors.
+// The first key signs new cursors, the remaining keys are only used to verify (key rotation).
+type CursorCodec struct {
+	keys []CursorKey
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+func NewCursorCodec(keys []CursorKey, ttl time.Duration) (*CursorCodec, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one cursor key is required")
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k.ID == "" || len(k.Secret) == 0 {
+			return nil, errors.New("cursor key id and secret are required")
+		}
+		if strings.Contains(k.ID, ".") {
+			return nil, fmt.Errorf("cursor key id %q must not contain '.'", k.ID)
+		}
+		if seen[k.ID] {
+			return nil, fmt.Errorf("duplicate cursor key id %q", k.ID)
+		}
+		seen[k.ID] = true
+	}
+	if ttl <= 0 {
+		ttl = time.Hour
+	}
+
+	return &CursorCodec{
+		keys: append([]CursorKey(nil), keys...),
+		ttl:  ttl,
+		now:  time.Now,
+	}, nil
+}
+
+// cursorBinding is the query a cursor was issued for, a cursor is rejected when replayed against another query
+type cursorBinding struct {
+	AuctionID string
+	Direction Direction
+	Limit     int
+}
+
+func (b cursorBinding) String() string {
+	return b.AuctionID + "|" + b.Direction.String() + "|" + strconv.Itoa(b.Limit)
+}
+
+// cursorEnvelope is the signed payload, encoded as base64url(json) + "." + base64url(hmac)
+type cursorEnvelope struct {
+	V     int       `json:"v"`
+	Kid   string    `json:"kid"`
+	Exp   int64     `json:"exp"` // unix seconds
+	Query string    `json:"q"`
+	At    time.Time `json:"at"`
+	ID    string    `json:"id"`
+}
+
+func (cc *CursorCodec) encode(c *Cursor, b cursorBinding) (*string, error) {
 	if c == nil {
 		return nil, nil
 	}
 
-	b, err := json.Marshal(c)
+	key := cc.keys[0]
+	env := cursorEnvelope{
+		V:     cursorVersion,
+		Kid:   key.ID,
+		Exp:   cc.expiry().Unix(),
+		Query: b.String(),
+		At:    c.At,
+		ID:    c.ID,
+	}
+
+	raw, err := json.Marshal(env)
 	if err != nil {
 		return nil, fmt.Errorf("encode cursor: %w", err)
 	}
 
-	s := base64.RawURLEncoding.EncodeToString(b)
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+	s := payload + "." + base64.RawURLEncoding.EncodeToString(sign(key.Secret, payload))
 	return &s, nil
 }
 
-func decodeCursor(s string) (*Cursor, error) {
+// window is the granularity of cursor expiry. Cursors issued within the same window carry the same exp,
+// so a page rendered twice for the same data is byte-identical, which strong ETags rely on
+func (cc *CursorCodec) window() time.Duration {
+	w := cc.ttl / 2
+	if w < time.Second {
+		w = time.Second
+	}
+	return w
+}
+
+// expiry is rounded up to the end of the current window, cursors live at least ttl
+func (cc *CursorCodec) expiry() time.Time {
+	w := cc.window()
+	return cc.now().Truncate(w).Add(w + cc.ttl)
+}
+
+// epoch changes whenever newly issued cursors would differ (window rollover or signing key rotation)
+func (cc *CursorCodec) epoch() string {
+	return cc.keys[0].ID + "." + strconv.FormatInt(cc.now().UnixNano()/int64(cc.window()), 10)
+}
+
+// decode verifies signature, version, expiry and query binding. All failures wrap ErrInvalidCursor
+func (cc *CursorCodec) decode(s string, b cursorBinding) (*Cursor, error) {
 	if s == "" {
 		return nil, nil
 	}
 
-	b, err := base64.RawURLEncoding.DecodeString(s)
+	payload, sig, ok := strings.Cut(s, ".")
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidCursor)
+	}
+
+	gotSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidCursor)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
 	if err != nil {
-		return nil, fmt.Errorf("decode cursor: %w", err)
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidCursor)
 	}
 
-	var c Cursor
-	if err := json.Unmarshal(b, &c); err != nil {
-		return nil, fmt.Errorf("decode cursor json: %w", err)
+	var env cursorEnvelope
+	if err = json.Unmarshal(raw, &env); err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidCursor)
+	}
+	if env.V != cursorVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidCursor, env.V)
 	}
 
-	if c.ID == "" || c.At.IsZero() {
-		return nil, ErrInvalidCursor
+	key, ok := cc.key(env.Kid)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key", ErrInvalidCursor)
+	}
+	if !hmac.Equal(gotSig, sign(key.Secret, payload)) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidCursor)
 	}
-	return &c, nil
+
+	// only trust the payload after the signature checks out
+	if cc.now().Unix() > env.Exp {
+		return nil, ErrCursorExpired
+	}
+	if env.Query != b.String() {
+		return nil, ErrCursorQueryMismatch
+	}
+	if env.ID == "" || env.At.IsZero() {
+		return nil, fmt.Errorf("%w: missing position", ErrInvalidCursor)
+	}
+
+	return &Cursor{At: env.At, ID: env.ID}, nil
+}
+
+func (cc *CursorCodec) key(id string) (CursorKey, bool) {
+	for _, k := range cc.keys {
+		if k.ID == id {
+			return k, true
+		}
+	}
+	return CursorKey{}, false
+}
+
+func sign(secret []byte, payload string) []byte {
+	m := hmac.New(sha256.New, secret)
+	m.Write([]byte(payload))
+	return m.Sum(nil)
 }
@@ -1,133 +1,224 @@
 package list_bids
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestEncodeCursor(t *testing.T) {
+func newTestCursorCodec(t *testing.T, keys ...CursorKey) *CursorCodec {
+	t.Helper()
+	if len(keys) == 0 {
+		keys = []CursorKey{{ID: "k1", Secret: []byte("test-secret")}}
+	}
+	cc, err := NewCursorCodec(keys, time.Hour)
+	require.NoError(t, err)
+	return cc
+}
+
+var testBinding = cursorBinding{AuctionID: "auction-1", Direction: DirectionDesc, Limit: 50}
+
+func TestNewCursorCodec(t *testing.T) {
 	tests := []struct {
-		name     string
-		cursor   *Cursor
-		wantErr  bool
-		wantNil  bool
+		name    string
+		keys    []CursorKey
+		wantErr bool
 	}{
+		{name: "no keys", keys: nil, wantErr: true},
+		{name: "empty secret", keys: []CursorKey{{ID: "k1"}}, wantErr: true},
+		{name: "empty id", keys: []CursorKey{{Secret: []byte("s")}}, wantErr: true},
+		{name: "dot in id", keys: []CursorKey{{ID: "k.1", Secret: []byte("s")}}, wantErr: true},
 		{
-			name:    "nil cursor returns nil",
-			cursor:  nil,
-			wantNil: true,
-			wantErr: false,
-		},
-		{
-			name: "valid cursor encodes successfully",
-			cursor: &Cursor{
-				At: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-				ID: "bid-123",
-			},
-			wantNil: false,
-			wantErr: false,
+			name:    "duplicate id",
+			keys:    []CursorKey{{ID: "k1", Secret: []byte("a")}, {ID: "k1", Secret: []byte("b")}},
+			wantErr: true,
 		},
+		{name: "valid", keys: []CursorKey{{ID: "k1", Secret: []byte("s")}}, wantErr: false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := encodeCursor(tt.cursor)
-
+			_, err := NewCursorCodec(tt.keys, time.Hour)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 			}
-
-			if tt.wantNil {
-				assert.Nil(t, result)
-			} else {
-				assert.NotNil(t, result)
-				assert.NotEmpty(t, *result)
-			}
 		})
 	}
 }
 
-func TestDecodeCursor(t *testing.T) {
-	fixedTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
-	validCursor := &Cursor{
-		At: fixedTime,
-		ID: "bid-123",
+func TestCursorCodec_Encode(t *testing.T) {
+	cc := newTestCursorCodec(t)
+
+	t.Run("nil cursor returns nil", func(t *testing.T) {
+		result, err := cc.encode(nil, testBinding)
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("valid cursor encodes to payload.signature", func(t *testing.T) {
+		result, err := cc.encode(&Cursor{At: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), ID: "bid-123"}, testBinding)
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Len(t, strings.Split(*result, "."), 2)
+	})
+
+	t.Run("same window encodes identically", func(t *testing.T) {
+		cc := newTestCursorCodec(t)
+		c := &Cursor{At: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), ID: "bid-123"}
+		windowStart := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		cc.now = func() time.Time { return windowStart.Add(time.Second) }
+		first, err := cc.encode(c, testBinding)
+		require.NoError(t, err)
+		firstEpoch := cc.epoch()
+
+		cc.now = func() time.Time { return windowStart.Add(cc.window() - time.Second) }
+		second, err := cc.encode(c, testBinding)
+		require.NoError(t, err)
+		assert.Equal(t, *first, *second)
+		assert.Equal(t, firstEpoch, cc.epoch())
+
+		cc.now = func() time.Time { return windowStart.Add(cc.window()) }
+		third, err := cc.encode(c, testBinding)
+		require.NoError(t, err)
+		assert.NotEqual(t, *first, *third)
+		assert.NotEqual(t, firstEpoch, cc.epoch())
+	})
+}
+
+func TestCursorCodec_RoundTrip(t *testing.T) {
+	cc := newTestCursorCodec(t)
+	original := &Cursor{At: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), ID: "bid-123"}
+
+	encoded, err := cc.encode(original, testBinding)
+	require.NoError(t, err)
+
+	decoded, err := cc.decode(*encoded, testBinding)
+	require.NoError(t, err)
+	assert.Equal(t, original.ID, decoded.ID)
+	assert.True(t, original.At.Equal(decoded.At))
+}
+
+func TestCursorCodec_Decode(t *testing.T) {
+	cc := newTestCursorCodec(t)
+	encoded, err := cc.encode(&Cursor{At: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), ID: "bid-123"}, testBinding)
+	require.NoError(t, err)
+	payload, sig, _ := strings.Cut(*encoded, ".")
+
+	// re-sign a modified envelope with the real key, to check checks after the signature
+	resign := func(mut func(e *cursorEnvelope)) string {
+		raw, _ := base64.RawURLEncoding.DecodeString(payload)
+		var env cursorEnvelope
+		require.NoError(t, json.Unmarshal(raw, &env))
+		mut(&env)
+		b, _ := json.Marshal(env)
+		p := base64.RawURLEncoding.EncodeToString(b)
+		return p + "." + base64.RawURLEncoding.EncodeToString(sign([]byte("test-secret"), p))
 	}
-	validEncoded, _ := encodeCursor(validCursor)
 
 	tests := []struct {
-		name     string
-		input    string
-		wantErr  bool
-		wantNil  bool
-		expected *Cursor
+		name    string
+		input   string
+		binding cursorBinding
+		wantErr error
 	}{
+		{name: "empty string returns nil", input: "", binding: testBinding},
+		{name: "missing signature", input: payload, binding: testBinding, wantErr: ErrInvalidCursor},
+		{name: "invalid base64", input: "not-valid-base64!@#$.abc", binding: testBinding, wantErr: ErrInvalidCursor},
 		{
-			name:    "empty string returns nil",
-			input:   "",
-			wantNil: true,
-			wantErr: false,
+			name:    "tampered payload",
+			input:   base64.RawURLEncoding.EncodeToString([]byte(`{"v":1,"kid":"k1","id":"bid-999"}`)) + "." + sig,
+			binding: testBinding,
+			wantErr: ErrInvalidCursor,
 		},
 		{
-			name:     "valid encoded cursor decodes successfully",
-			input:    *validEncoded,
-			wantNil:  false,
-			wantErr:  false,
-			expected: validCursor,
+			name:    "unknown key id",
+			input:   resign(func(e *cursorEnvelope) { e.Kid = "k9" }),
+			binding: testBinding,
+			wantErr: ErrInvalidCursor,
 		},
 		{
-			name:    "invalid base64 returns error",
-			input:   "not-valid-base64!@#$",
-			wantErr: true,
+			name:    "unsupported version",
+			input:   resign(func(e *cursorEnvelope) { e.V = 99 }),
+			binding: testBinding,
+			wantErr: ErrInvalidCursor,
 		},
 		{
-			name:    "invalid json returns error",
-			input:   "aW52YWxpZC1qc29u", // "invalid-json" in base64
-			wantErr: true,
+			name:    "expired",
+			input:   resign(func(e *cursorEnvelope) { e.Exp = time.Now().Add(-time.Minute).Unix() }),
+			binding: testBinding,
+			wantErr: ErrCursorExpired,
+		},
+		{
+			name:    "different auction",
+			input:   *encoded,
+			binding: cursorBinding{AuctionID: "auction-2", Direction: DirectionDesc, Limit: 50},
+			wantErr: ErrCursorQueryMismatch,
+		},
+		{
+			name:    "different direction",
+			input:   *encoded,
+			binding: cursorBinding{AuctionID: "auction-1", Direction: DirectionAsc, Limit: 50},
+			wantErr: ErrCursorQueryMismatch,
+		},
+		{
+			name:    "different limit",
+			input:   *encoded,
+			binding: cursorBinding{AuctionID: "auction-1", Direction: DirectionDesc, Limit: 10},
+			wantErr: ErrCursorQueryMismatch,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := decodeCursor(tt.input)
+			result, err := cc.decode(tt.input, tt.binding)
 
-			if tt.wantErr {
-				assert.Error(t, err)
-			} else {
+			if tt.wantErr == nil {
 				assert.NoError(t, err)
-			}
-
-			if tt.wantNil {
 				assert.Nil(t, result)
-			} else if tt.expected != nil {
-				assert.NotNil(t, result)
-				assert.Equal(t, tt.expected.ID, result.ID)
-				assert.True(t, tt.expected.At.Equal(result.At))
+				return
 			}
+			assert.Nil(t, result)
+			assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+			assert.True(t, errors.Is(err, ErrInvalidCursor), "all cursor errors must wrap ErrInvalidCursor")
 		})
 	}
 }
 
-func TestCursorRoundTrip(t *testing.T) {
-	t.Run("encode and decode produces same cursor", func(t *testing.T) {
-		original := &Cursor{
-			At: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
-			ID: "bid-123",
-		}
+func TestCursorCodec_KeyRotation(t *testing.T) {
+	oldKey := CursorKey{ID: "k1", Secret: []byte("old-secret")}
+	newKey := CursorKey{ID: "k2", Secret: []byte("new-secret")}
+	c := &Cursor{At: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), ID: "bid-123"}
+
+	issuedBefore, err := newTestCursorCodec(t, oldKey).encode(c, testBinding)
+	require.NoError(t, err)
 
-		encoded, err := encodeCursor(original)
+	t.Run("old key still verifies after rotation", func(t *testing.T) {
+		rotated := newTestCursorCodec(t, newKey, oldKey)
+		decoded, err := rotated.decode(*issuedBefore, testBinding)
 		assert.NoError(t, err)
-		assert.NotNil(t, encoded)
+		assert.Equal(t, c.ID, decoded.ID)
+	})
 
-		decoded, err := decodeCursor(*encoded)
+	t.Run("new cursors are signed with the first key", func(t *testing.T) {
+		rotated := newTestCursorCodec(t, newKey, oldKey)
+		issuedAfter, err := rotated.encode(c, testBinding)
+		require.NoError(t, err)
+
+		_, err = newTestCursorCodec(t, newKey).decode(*issuedAfter, testBinding)
 		assert.NoError(t, err)
-		assert.NotNil(t, decoded)
+	})
 
-		assert.Equal(t, original.ID, decoded.ID)
-		assert.True(t, original.At.Equal(decoded.At))
+	t.Run("retired key no longer verifies", func(t *testing.T) {
+		_, err := newTestCursorCodec(t, newKey).decode(*issuedBefore, testBinding)
+		assert.True(t, errors.Is(err, ErrInvalidCursor))
 	})
 }
@@ -1,8 +1,15 @@
 package list_bids
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrInvalidCursor   = errors.New("invalid_cursor")
 	ErrAuctionNotFound = errors.New("auction_not_found")
+
+	// cursor errors wrap ErrInvalidCursor so callers can match either
+	ErrCursorExpired       = fmt.Errorf("%w: expired", ErrInvalidCursor)
+	ErrCursorQueryMismatch = fmt.Errorf("%w: issued for a different query", ErrInvalidCursor)
 )
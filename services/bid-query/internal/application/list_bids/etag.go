@@ -0,0 +1,33 @@
+package list_bids
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// etag hashes everything that determines the response bytes. Both forms include the query, the raw
+// cursor and the cursor epoch (the next cursor in the body changes with it)
+func etag(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// versionETag is the cheap validator, computed from the per-auction bid version without touching mongo
+func versionETag(b cursorBinding, cursor, epoch string, version int64) string {
+	return etag("v", b.String(), cursor, epoch, strconv.FormatInt(version, 10))
+}
+
+// contentETag is the fallback when no version is available, computed from the returned page
+func contentETag(b cursorBinding, cursor, epoch string, items []Item, hasMore bool) string {
+	parts := make([]string, 0, len(items)+5)
+	parts = append(parts, "c", b.String(), cursor, epoch, strconv.FormatBool(hasMore))
+	for _, it := range items {
+		parts = append(parts, it.BidID)
+	}
+	return etag(parts...)
+}
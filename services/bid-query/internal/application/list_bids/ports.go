@@ -8,3 +8,9 @@ type IBidReadRepository interface {
 	ListByAuction(ctx context.Context, auctionID string, after *Cursor, limit int, asc bool) (
 		items []Item, hasMore bool, next *Cursor, err error)
 }
+
+// IBidsVersionReader returns a per-auction counter bumped after every projected bid, 0 when unknown.
+// The counter must never go backwards, ETags issued for an old value would match again
+type IBidsVersionReader interface {
+	Get(ctx context.Context, auctionID string) (int64, error)
+}
@@ -10,6 +10,8 @@ import (
 
 type Service struct {
 	bidReadRepo IBidReadRepository
+	cursors     *CursorCodec
+	versions    IBidsVersionReader
 	log         *zap.Logger
 }
 
@@ -17,15 +19,31 @@ var _ IService = (*Service)(nil)
 
 type Deps struct {
 	BidReadRepo IBidReadRepository
+	Cursors     *CursorCodec
+	Versions    IBidsVersionReader // optional, nil falls back to content ETags only
 }
 
 func NewService(d Deps, log *zap.Logger) *Service {
 	return &Service{
 		bidReadRepo: d.BidReadRepo,
+		cursors:     d.Cursors,
+		versions:    d.Versions,
 		log:         log,
 	}
 }
 
+// sanitize clamps the limit and builds the binding cursors are issued for
+func sanitize(q Query) (limit int, binding cursorBinding) {
+	limit = q.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	return limit, cursorBinding{AuctionID: q.AuctionID, Direction: q.Direction, Limit: limit}
+}
+
 func (s *Service) Handle(ctx context.Context, q Query) (*Result, error) {
 	log := middleware.LoggerFrom(ctx, s.log).With(
 		zap.String("auctionId", q.AuctionID),
@@ -33,21 +51,15 @@ func (s *Service) Handle(ctx context.Context, q Query) (*Result, error) {
 		zap.String("direction", q.Direction.String()),
 	)
 
-	// sanitize
-	limit := q.Limit
-	if limit <= 0 {
-		limit = 50
-	}
-	if limit > 200 {
-		limit = 200
-	}
+	limit, binding := sanitize(q)
 	asc := q.Direction == DirectionAsc
 
 	var after *Cursor
 	if q.Cursor != "" {
-		c, err := decodeCursor(q.Cursor)
+		c, err := s.cursors.decode(q.Cursor, binding)
 		if err != nil {
-			return nil, ErrInvalidCursor
+			log.Info("rejected cursor", zap.Error(err))
+			return nil, err
 		}
 		after = c
 	}
@@ -58,6 +70,11 @@ func (s *Service) Handle(ctx context.Context, q Query) (*Result, error) {
 		zap.Any("after", after),
 	)
 
+	// epoch and version are read before the query: if a bid lands in between, the page is newer than
+	// its ETag and the next poll just refetches, the other order could pin clients to a stale page
+	epoch := s.cursors.epoch()
+	version := s.version(ctx, q.AuctionID, log)
+
 	log.Debug("fetching bids from repo")
 	items, hasMore, next, err := s.bidReadRepo.ListByAuction(ctx, q.AuctionID, after, limit, asc)
 	if err != nil {
@@ -66,7 +83,7 @@ func (s *Service) Handle(ctx context.Context, q Query) (*Result, error) {
 		return nil, fmt.Errorf("list bids: %w", err)
 	}
 
-	nextStr, err := encodeCursor(next)
+	nextStr, err := s.cursors.encode(next, binding)
 	if err != nil {
 		// encoding failure shouldn't 500 the whole call, log and fall back to end of list
 		log.Warn("encode next cursor failed", zap.Error(err))
@@ -74,6 +91,13 @@ func (s *Service) Handle(ctx context.Context, q Query) (*Result, error) {
 		hasMore = false
 	}
 
+	var tag string
+	if version > 0 {
+		tag = versionETag(binding, q.Cursor, epoch, version)
+	} else {
+		tag = contentETag(binding, q.Cursor, epoch, items, hasMore)
+	}
+
 	nc := ""
 	if nextStr != nil {
 		nc = *nextStr
@@ -81,11 +105,42 @@ func (s *Service) Handle(ctx context.Context, q Query) (*Result, error) {
 	log.Debug("list bids: returning result",
 		zap.Int("items", len(items)),
 		zap.Bool("hasMore", hasMore),
-		zap.String("nextCursor", nc))
+		zap.String("nextCursor", nc),
+		zap.String("etag", tag))
 
 	return &Result{
 		Items:      items,
 		HasMore:    hasMore,
 		NextCursor: nextStr,
+		ETag:       tag,
 	}, nil
 }
+
+func (s *Service) ETag(ctx context.Context, q Query) (string, error) {
+	log := middleware.LoggerFrom(ctx, s.log).With(zap.String("auctionId", q.AuctionID))
+
+	_, binding := sanitize(q)
+	// a cursor that would be rejected must not revalidate a cached page
+	if _, err := s.cursors.decode(q.Cursor, binding); err != nil {
+		return "", err
+	}
+
+	version := s.version(ctx, q.AuctionID, log)
+	if version <= 0 {
+		return "", nil
+	}
+	return versionETag(binding, q.Cursor, s.cursors.epoch(), version), nil
+}
+
+// version returns 0 when no reader is configured or it fails, callers fall back to content ETags
+func (s *Service) version(ctx context.Context, auctionID string, log *zap.Logger) int64 {
+	if s.versions == nil {
+		return 0
+	}
+	v, err := s.versions.Get(ctx, auctionID)
+	if err != nil {
+		log.Warn("read bids version failed", zap.Error(err))
+		return 0
+	}
+	return v
+}
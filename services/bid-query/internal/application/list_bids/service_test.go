@@ -25,6 +25,15 @@ func (m *MockBidReadRepository) ListByAuction(ctx context.Context, auctionID str
 	return args.Get(0).([]Item), args.Bool(1), args.Get(2).(*Cursor), args.Error(3)
 }
 
+type MockBidsVersionReader struct {
+	mock.Mock
+}
+
+func (m *MockBidsVersionReader) Get(ctx context.Context, auctionID string) (int64, error) {
+	args := m.Called(ctx, auctionID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func TestService_Handle_Success(t *testing.T) {
 	ctx := context.Background()
 	fixedTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
@@ -64,6 +73,7 @@ func TestService_Handle_Success(t *testing.T) {
 
 	deps := Deps{
 		BidReadRepo: mockRepo,
+		Cursors:     newTestCursorCodec(t),
 	}
 
 	service := NewService(deps, zap.NewNop())
@@ -89,7 +99,8 @@ func TestService_Handle_WithCursor(t *testing.T) {
 		At: fixedTime,
 		ID: "bid-1",
 	}
-	encodedCursor, _ := encodeCursor(cursor)
+	codec := newTestCursorCodec(t)
+	encodedCursor, _ := codec.encode(cursor, cursorBinding{AuctionID: "auction-1", Direction: DirectionDesc, Limit: 50})
 
 	items := []Item{
 		{
@@ -114,6 +125,7 @@ func TestService_Handle_WithCursor(t *testing.T) {
 
 	deps := Deps{
 		BidReadRepo: mockRepo,
+		Cursors:     codec,
 	}
 
 	service := NewService(deps, zap.NewNop())
@@ -143,6 +155,7 @@ func TestService_Handle_InvalidCursor(t *testing.T) {
 
 	deps := Deps{
 		BidReadRepo: new(MockBidReadRepository),
+		Cursors:     newTestCursorCodec(t),
 	}
 
 	service := NewService(deps, zap.NewNop())
@@ -201,6 +214,7 @@ func TestService_Handle_LimitSanitization(t *testing.T) {
 
 			deps := Deps{
 				BidReadRepo: mockRepo,
+				Cursors:     newTestCursorCodec(t),
 			}
 
 			service := NewService(deps, zap.NewNop())
@@ -250,6 +264,7 @@ func TestService_Handle_DirectionHandling(t *testing.T) {
 
 			deps := Deps{
 				BidReadRepo: mockRepo,
+				Cursors:     newTestCursorCodec(t),
 			}
 
 			service := NewService(deps, zap.NewNop())
@@ -280,6 +295,7 @@ func TestService_Handle_AuctionNotFound(t *testing.T) {
 
 	deps := Deps{
 		BidReadRepo: mockRepo,
+		Cursors:     newTestCursorCodec(t),
 	}
 
 	service := NewService(deps, zap.NewNop())
@@ -293,3 +309,98 @@ func TestService_Handle_AuctionNotFound(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestService_Handle_CursorFromOtherQuery(t *testing.T) {
+	ctx := context.Background()
+	codec := newTestCursorCodec(t)
+
+	// cursor issued for auction-2 replayed against auction-1
+	encoded, _ := codec.encode(&Cursor{At: time.Now(), ID: "bid-1"},
+		cursorBinding{AuctionID: "auction-2", Direction: DirectionDesc, Limit: 50})
+
+	service := NewService(Deps{
+		BidReadRepo: new(MockBidReadRepository),
+		Cursors:     codec,
+	}, zap.NewNop())
+
+	result, err := service.Handle(ctx, Query{
+		AuctionID: "auction-1",
+		Cursor:    *encoded,
+		Limit:     50,
+		Direction: DirectionDesc,
+	})
+
+	assert.Nil(t, result)
+	assert.True(t, errors.Is(err, ErrInvalidCursor))
+	assert.True(t, errors.Is(err, ErrCursorQueryMismatch))
+}
+
+func TestService_ETag(t *testing.T) {
+	ctx := context.Background()
+	fixedTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	items := []Item{{BidID: "bid-1", AuctionID: "auction-1", BidderID: "bidder-1", Amount: 120.0, At: fixedTime}}
+	query := Query{AuctionID: "auction-1", Limit: 50, Direction: DirectionDesc}
+
+	newService := func(versions IBidsVersionReader) (*Service, *MockBidReadRepository) {
+		codec := newTestCursorCodec(t)
+		codec.now = func() time.Time { return fixedTime }
+		mockRepo := new(MockBidReadRepository)
+		mockRepo.On("ListByAuction", ctx, "auction-1", (*Cursor)(nil), 50, false).
+			Return(items, false, (*Cursor)(nil), nil)
+		return NewService(Deps{BidReadRepo: mockRepo, Cursors: codec, Versions: versions}, zap.NewNop()), mockRepo
+	}
+
+	t.Run("version ETag matches Handle without querying", func(t *testing.T) {
+		versions := new(MockBidsVersionReader)
+		versions.On("Get", ctx, "auction-1").Return(int64(7), nil)
+		service, mockRepo := newService(versions)
+
+		tag, err := service.ETag(ctx, query)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, tag)
+		mockRepo.AssertNotCalled(t, "ListByAuction", ctx, "auction-1", (*Cursor)(nil), 50, false)
+
+		result, err := service.Handle(ctx, query)
+		assert.NoError(t, err)
+		assert.Equal(t, tag, result.ETag)
+	})
+
+	t.Run("version bump changes ETag", func(t *testing.T) {
+		versions := new(MockBidsVersionReader)
+		versions.On("Get", ctx, "auction-1").Return(int64(7), nil).Once()
+		versions.On("Get", ctx, "auction-1").Return(int64(8), nil).Once()
+		service, _ := newService(versions)
+
+		before, err := service.ETag(ctx, query)
+		assert.NoError(t, err)
+		after, err := service.ETag(ctx, query)
+		assert.NoError(t, err)
+		assert.NotEqual(t, before, after)
+	})
+
+	t.Run("no version falls back to content ETag", func(t *testing.T) {
+		versions := new(MockBidsVersionReader)
+		versions.On("Get", ctx, "auction-1").Return(int64(0), errors.New("redis down"))
+		service, _ := newService(versions)
+
+		tag, err := service.ETag(ctx, query)
+		assert.NoError(t, err)
+		assert.Empty(t, tag)
+
+		result, err := service.Handle(ctx, query)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.ETag)
+
+		again, err := service.Handle(ctx, query)
+		assert.NoError(t, err)
+		assert.Equal(t, result.ETag, again.ETag)
+	})
+
+	t.Run("invalid cursor is not revalidated", func(t *testing.T) {
+		service, _ := newService(nil)
+
+		tag, err := service.ETag(ctx, Query{AuctionID: "auction-1", Cursor: "bogus", Limit: 50})
+		assert.True(t, errors.Is(err, ErrInvalidCursor))
+		assert.Empty(t, tag)
+	})
+}
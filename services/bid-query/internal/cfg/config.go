@@ -1,6 +1,8 @@
 package cfg
 
 import (
+	"errors"
+	"fmt"
 	"kei-services/pkg/config"
 	"kei-services/pkg/infra/kafka"
 	"kei-services/pkg/infra/mongo"
@@ -8,6 +10,8 @@ import (
 	"kei-services/pkg/logger"
 	"kei-services/pkg/profiler"
 	swagger "kei-services/pkg/swagger"
+	"kei-services/pkg/tracing"
+	"strings"
 )
 
 type Config struct {
@@ -23,9 +27,98 @@ type Config struct {
 
 	Logger *logger.Config
 
+	Tracing *tracing.Config
+
 	Mongo *mongo.Config
 
 	Redis *redis.Config
 
 	KafkaReader *kafka.ReaderConfig
+
+	FirstPageCache *FirstPageCacheConfig
+
+	Cursor *CursorConfig
+
+	HttpCache *HttpCacheConfig
+}
+
+// Validate reports every problem in c at once, required sections that are missing included
+func (c *Config) Validate() error {
+	return errors.Join(
+		config.Required("App", c.App),
+		config.Required("Network", c.Network),
+		config.Required("Cors", c.Cors),
+		config.Required("Pprof", c.Pprof),
+		config.Required("Swagger", c.Swagger),
+		config.Required("Logger", c.Logger),
+		config.Optional("Tracing", c.Tracing),
+		config.Required("Mongo", c.Mongo),
+		config.Required("Redis", c.Redis),
+		config.Optional("KafkaReader", c.KafkaReader),
+		config.Optional("FirstPageCache", c.FirstPageCache),
+		config.Required("Cursor", c.Cursor),
+		config.Optional("HttpCache", c.HttpCache),
+	)
+}
+
+// HttpCacheConfig controls Cache-Control / Surrogate-Control on list bids responses
+type HttpCacheConfig struct {
+	MaxAgeSec          int // browsers, default: 1
+	SurrogateMaxAgeSec int // CDN, purged by surrogate key on new bids, 0 disables surrogate headers
+}
+
+func (c *HttpCacheConfig) Validate() error {
+	if c.MaxAgeSec < 0 || c.SurrogateMaxAgeSec < 0 {
+		return config.Problem("MaxAgeSec", "max ages must not be negative")
+	}
+	return nil
+}
+
+// FirstPageCacheConfig controls caching of first pages of list bids in redis
+type FirstPageCacheConfig struct {
+	IsEnabled bool
+	TTLSec    int // default: 30
+}
+
+func (c *FirstPageCacheConfig) Validate() error {
+	if c.TTLSec < 0 {
+		return config.Problem("TTLSec", "must not be negative")
+	}
+	return nil
+}
+
+// CursorConfig holds the HMAC keys used to sign pagination cursors.
+// The first key signs new cursors, the rest are kept only to verify cursors issued before a rotation
+type CursorConfig struct {
+	Keys   []CursorKeyConfig `mapstructure:"-"` // CURSOR_KEYS env only, never from the config file
+	TTLSec int               // default: 3600
+}
+
+func (c *CursorConfig) Validate() error {
+	if len(c.Keys) == 0 {
+		return config.Problem("Keys", "at least one key is required to sign cursors, set CURSOR_KEYS")
+	}
+	var errs []error
+	seen := make(map[string]bool, len(c.Keys))
+	for i, k := range c.Keys {
+		field := fmt.Sprintf("Keys[%d]", i)
+		switch {
+		case k.ID == "" || k.Secret == "":
+			errs = append(errs, config.Problem(field, "id and secret are required"))
+		case strings.Contains(k.ID, "."):
+			errs = append(errs, config.Problem(field, "id %q must not contain '.'", k.ID))
+		case seen[k.ID]:
+			errs = append(errs, config.Problem(field, "duplicate id %q", k.ID))
+		}
+		seen[k.ID] = true
+	}
+	if c.TTLSec < 0 {
+		errs = append(errs, config.Problem("TTLSec", "must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+type CursorKeyConfig struct {
+	ID     string
+	Secret string `json:"-"`
 }
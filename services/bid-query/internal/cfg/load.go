@@ -3,6 +3,7 @@ package cfg
 import (
 	"fmt"
 	"kei-services/pkg/config"
+	"kei-services/pkg/infra/kafka"
 	"kei-services/pkg/infra/mongo"
 	"kei-services/pkg/infra/redis"
 	"log"
@@ -24,6 +25,7 @@ func Load(path string) (*Config, error) {
 	// env bindings
 	config.BindSsl(v)
 	redis.BindEnv(v)
+	kafka.BindEnv(v, "KAFKA", "kafkaReader")
 	mongo.BindMongoDb(v, "MONGO", "mongo")
 
 	if err := v.ReadInConfig(); err != nil {
@@ -42,6 +44,18 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("unmarshal config: %w", err)
 	}
 
+	// cursor signing keys are read from env only, eg CURSOR_KEYS="k2:new-secret,k1:old-secret", first key signs
+	if s := os.Getenv("CURSOR_KEYS"); s != "" {
+		keys, err := parseCursorKeys(s)
+		if err != nil {
+			return nil, fmt.Errorf("CURSOR_KEYS: %w", err)
+		}
+		if cfg.Cursor == nil {
+			cfg.Cursor = &CursorConfig{}
+		}
+		cfg.Cursor.Keys = keys
+	}
+
 	return &cfg, nil
 }
 
@@ -53,6 +67,23 @@ func HotReload(path string, cfg *Config) {
 	})
 }
 
+// parseCursorKeys splits on ',' only, secrets may contain any other character and are kept verbatim
+func parseCursorKeys(s string) ([]CursorKeyConfig, error) {
+	var keys []CursorKeyConfig
+	for i, p := range strings.Split(s, ",") {
+		id, secret, ok := strings.Cut(p, ":")
+		switch {
+		// do not echo the entry, it may contain a secret
+		case !ok || id == "" || secret == "":
+			return nil, fmt.Errorf("entry %d: expected id:secret", i)
+		case strings.ContainsAny(id, " \t\r\n"):
+			return nil, fmt.Errorf("entry %d: id must not contain whitespace", i)
+		}
+		keys = append(keys, CursorKeyConfig{ID: id, Secret: secret})
+	}
+	return keys, nil
+}
+
 func splitCSV(s string) []string {
 	parts := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' || r == ' ' })
 	out := make([]string, 0, len(parts))
@@ -0,0 +1,49 @@
+package cfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCursorKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []CursorKeyConfig
+		wantErr bool
+	}{
+		{
+			name: "single key",
+			in:   "k1:secret",
+			want: []CursorKeyConfig{{ID: "k1", Secret: "secret"}},
+		},
+		{
+			name: "rotation keeps order",
+			in:   "k2:new,k1:old",
+			want: []CursorKeyConfig{{ID: "k2", Secret: "new"}, {ID: "k1", Secret: "old"}},
+		},
+		{
+			name: "secret keeps spaces, semicolons and colons",
+			in:   "k1:a b;c:d",
+			want: []CursorKeyConfig{{ID: "k1", Secret: "a b;c:d"}},
+		},
+		{name: "missing separator", in: "k1secret", wantErr: true},
+		{name: "empty id", in: ":secret", wantErr: true},
+		{name: "empty secret", in: "k1:", wantErr: true},
+		{name: "empty entry", in: "k2:new,,k1:old", wantErr: true},
+		{name: "trailing comma", in: "k1:secret,", wantErr: true},
+		{name: "whitespace in id", in: "k2:new, k1:old", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCursorKeys(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// BidsVersionKeyPrefix must match the key bumped by bid-projector after every bids_history write
+const BidsVersionKeyPrefix = "bids:version:"
+
+type BidsVersion struct {
+	KeyPrefix string
+	R         redis.UniversalClient
+}
+
+func NewBidsVersion(r redis.UniversalClient) *BidsVersion {
+	return &BidsVersion{
+		KeyPrefix: BidsVersionKeyPrefix,
+		R:         r,
+	}
+}
+
+// Get returns the current per-auction bid version, 0 if no bid has been projected yet
+func (v *BidsVersion) Get(ctx context.Context, auctionID string) (int64, error) {
+	n, err := v.R.Get(ctx, v.KeyPrefix+auctionID).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
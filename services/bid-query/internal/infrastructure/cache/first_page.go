@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kei-services/pkg/middleware"
+	"kei-services/services/bid-query/internal/application/list_bids"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+var _ list_bids.IBidReadRepository = (*FirstPageCache)(nil)
+
+// FirstPageCache decorates a bid read repository and caches first pages (no cursor) in Redis.
+// Keys embed the active bids_history collection and the per-auction bid version, so a rebuild switching
+// collections or a new bid makes old entries unreachable instead of needing an explicit delete, stale
+// entries are left to expire via TTL.
+type FirstPageCache struct {
+	KeyPrefix  string
+	Collection func() string // the physical bids_history collection currently served
+	Next       list_bids.IBidReadRepository
+	Version    *BidsVersion
+	R          redis.UniversalClient
+	TTL        time.Duration
+	Requests   *prometheus.CounterVec // labels: result
+	Log        *zap.Logger
+}
+
+func NewFirstPageCache(next list_bids.IBidReadRepository, collection func() string, r redis.UniversalClient,
+	ttl time.Duration, requests *prometheus.CounterVec, log *zap.Logger) *FirstPageCache {
+	return &FirstPageCache{
+		KeyPrefix:  "bids:page:",
+		Collection: collection,
+		Next:       next,
+		Version:    NewBidsVersion(r),
+		R:          r,
+		TTL:        ttl,
+		Requests:   requests,
+		Log:        log,
+	}
+}
+
+type cachedPage struct {
+	Items   []list_bids.Item  `json:"items"`
+	HasMore bool              `json:"hasMore"`
+	Next    *list_bids.Cursor `json:"next,omitempty"`
+}
+
+func (c *FirstPageCache) ListByAuction(ctx context.Context, auctionID string, after *list_bids.Cursor, limit int,
+	asc bool) (items []list_bids.Item, hasMore bool, next *list_bids.Cursor, err error) {
+	// only the first page is hot, deeper pages go straight to the repo
+	if after != nil {
+		return c.Next.ListByAuction(ctx, auctionID, after, limit, asc)
+	}
+
+	log := middleware.LoggerFrom(ctx, c.Log).With(zap.String("auctionId", auctionID))
+
+	ver, err := c.Version.Get(ctx, auctionID)
+	if err != nil {
+		// redis down, serve from repo without caching
+		log.Warn("read bids version failed, bypassing cache", zap.Error(err))
+		c.observe("error")
+		return c.Next.ListByAuction(ctx, auctionID, after, limit, asc)
+	}
+
+	key := c.key(auctionID, ver, limit, asc)
+	raw, err := c.R.Get(ctx, key).Bytes()
+	switch {
+	case err == nil:
+		var p cachedPage
+		if err = json.Unmarshal(raw, &p); err == nil {
+			c.observe("hit")
+			return p.Items, p.HasMore, p.Next, nil
+		}
+		log.Warn("decode cached page failed", zap.String("key", key), zap.Error(err))
+	case !errors.Is(err, redis.Nil):
+		log.Warn("get cached page failed", zap.String("key", key), zap.Error(err))
+	}
+	c.observe("miss")
+
+	items, hasMore, next, err = c.Next.ListByAuction(ctx, auctionID, after, limit, asc)
+	if err != nil {
+		return nil, false, nil, err
+	}
+
+	b, err := json.Marshal(cachedPage{Items: items, HasMore: hasMore, Next: next})
+	if err != nil {
+		log.Warn("encode page for cache failed", zap.Error(err))
+		return items, hasMore, next, nil
+	}
+	if err = c.R.Set(ctx, key, b, c.TTL).Err(); err != nil {
+		log.Warn("set cached page failed", zap.String("key", key), zap.Error(err))
+	}
+
+	return items, hasMore, next, nil
+}
+
+// key eg bids:page:bids_history:a_123:desc:50:v42
+func (c *FirstPageCache) key(auctionID string, ver int64, limit int, asc bool) string {
+	dir := list_bids.DirectionDesc
+	if asc {
+		dir = list_bids.DirectionAsc
+	}
+	return fmt.Sprintf("%s%s:%s:%s:%d:v%d", c.KeyPrefix, c.Collection(), auctionID, dir, limit, ver)
+}
+
+func (c *FirstPageCache) observe(result string) {
+	if c.Requests != nil {
+		c.Requests.WithLabelValues(result).Inc()
+	}
+}
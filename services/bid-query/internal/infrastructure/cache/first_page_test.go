@@ -0,0 +1,200 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"kei-services/services/bid-query/internal/application/list_bids"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockBidReadRepository struct {
+	mock.Mock
+}
+
+func (m *MockBidReadRepository) ListByAuction(ctx context.Context, auctionID string, after *list_bids.Cursor,
+	limit int, asc bool) (items []list_bids.Item, hasMore bool, next *list_bids.Cursor, err error) {
+	args := m.Called(ctx, auctionID, after, limit, asc)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Get(2).(*list_bids.Cursor), args.Error(3)
+	}
+	return args.Get(0).([]list_bids.Item), args.Bool(1), args.Get(2).(*list_bids.Cursor), args.Error(3)
+}
+
+func activeCollection(name string) func() string {
+	return func() string { return name }
+}
+
+func TestFirstPageCache_Key(t *testing.T) {
+	tests := []struct {
+		name       string
+		collection string
+		auction    string
+		ver        int64
+		limit      int
+		asc        bool
+		want       string
+	}{
+		{"desc", "bids_history", "a_123", 42, 50, false, "bids:page:bids_history:a_123:desc:50:v42"},
+		{"asc", "bids_history", "a_123", 42, 50, true, "bids:page:bids_history:a_123:asc:50:v42"},
+		{"no bids yet", "bids_history", "a_1", 0, 20, false, "bids:page:bids_history:a_1:desc:20:v0"},
+		{"rebuilt collection", "bids_history_1700000000", "a_123", 42, 50, false,
+			"bids:page:bids_history_1700000000:a_123:desc:50:v42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewFirstPageCache(nil, activeCollection(tt.collection), nil, time.Minute, nil, zap.NewNop())
+			assert.Equal(t, tt.want, c.key(tt.auction, tt.ver, tt.limit, tt.asc))
+		})
+	}
+}
+
+func TestFirstPageCache_ListByAuction(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	items := []list_bids.Item{{BidID: "b2", AuctionID: "a_1", BidderID: "u1", Amount: 120, At: at}}
+	next := &list_bids.Cursor{At: at, ID: "b2"}
+	page, err := json.Marshal(cachedPage{Items: items, HasMore: true, Next: next})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		after     *list_bids.Cursor
+		setup     func(r redismock.ClientMock, repo *MockBidReadRepository)
+		wantItems []list_bids.Item
+		wantErr   bool
+	}{
+		{
+			name: "hit",
+			setup: func(r redismock.ClientMock, repo *MockBidReadRepository) {
+				r.ExpectGet("bids:version:a_1").SetVal("7")
+				r.ExpectGet("bids:page:bids_history:a_1:desc:20:v7").SetVal(string(page))
+			},
+			wantItems: items,
+		},
+		{
+			name: "miss fills the page under the current version",
+			setup: func(r redismock.ClientMock, repo *MockBidReadRepository) {
+				r.ExpectGet("bids:version:a_1").SetVal("7")
+				r.ExpectGet("bids:page:bids_history:a_1:desc:20:v7").RedisNil()
+				repo.On("ListByAuction", ctx, "a_1", (*list_bids.Cursor)(nil), 20, false).
+					Return(items, true, next, nil)
+				r.ExpectSet("bids:page:bids_history:a_1:desc:20:v7", page, time.Minute).SetVal("OK")
+			},
+			wantItems: items,
+		},
+		{
+			name: "version bump misses the page cached under the old version",
+			setup: func(r redismock.ClientMock, repo *MockBidReadRepository) {
+				r.ExpectGet("bids:version:a_1").SetVal("8")
+				r.ExpectGet("bids:page:bids_history:a_1:desc:20:v8").RedisNil()
+				repo.On("ListByAuction", ctx, "a_1", (*list_bids.Cursor)(nil), 20, false).
+					Return(items, true, next, nil)
+				r.ExpectSet("bids:page:bids_history:a_1:desc:20:v8", page, time.Minute).SetVal("OK")
+			},
+			wantItems: items,
+		},
+		{
+			name: "no version yet",
+			setup: func(r redismock.ClientMock, repo *MockBidReadRepository) {
+				r.ExpectGet("bids:version:a_1").RedisNil()
+				r.ExpectGet("bids:page:bids_history:a_1:desc:20:v0").SetVal(string(page))
+			},
+			wantItems: items,
+		},
+		{
+			name: "version unreadable bypasses the cache",
+			setup: func(r redismock.ClientMock, repo *MockBidReadRepository) {
+				r.ExpectGet("bids:version:a_1").SetErr(errors.New("connection refused"))
+				repo.On("ListByAuction", ctx, "a_1", (*list_bids.Cursor)(nil), 20, false).
+					Return(items, true, next, nil)
+			},
+			wantItems: items,
+		},
+		{
+			name: "undecodable page is refilled",
+			setup: func(r redismock.ClientMock, repo *MockBidReadRepository) {
+				r.ExpectGet("bids:version:a_1").SetVal("7")
+				r.ExpectGet("bids:page:bids_history:a_1:desc:20:v7").SetVal("{not json")
+				repo.On("ListByAuction", ctx, "a_1", (*list_bids.Cursor)(nil), 20, false).
+					Return(items, true, next, nil)
+				r.ExpectSet("bids:page:bids_history:a_1:desc:20:v7", page, time.Minute).SetVal("OK")
+			},
+			wantItems: items,
+		},
+		{
+			name:  "later pages skip the cache",
+			after: next,
+			setup: func(r redismock.ClientMock, repo *MockBidReadRepository) {
+				repo.On("ListByAuction", ctx, "a_1", next, 20, false).
+					Return([]list_bids.Item{}, false, (*list_bids.Cursor)(nil), nil)
+			},
+			wantItems: []list_bids.Item{},
+		},
+		{
+			name: "repo errors aren't cached",
+			setup: func(r redismock.ClientMock, repo *MockBidReadRepository) {
+				r.ExpectGet("bids:version:a_1").SetVal("7")
+				r.ExpectGet("bids:page:bids_history:a_1:desc:20:v7").RedisNil()
+				repo.On("ListByAuction", ctx, "a_1", (*list_bids.Cursor)(nil), 20, false).
+					Return(nil, false, (*list_bids.Cursor)(nil), errors.New("mongo down"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, r := redismock.NewClientMock()
+			repo := new(MockBidReadRepository)
+			tt.setup(r, repo)
+
+			c := NewFirstPageCache(repo, activeCollection("bids_history"), db, time.Minute, nil, zap.NewNop())
+			got, _, _, err := c.ListByAuction(ctx, "a_1", tt.after, 20, false)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantItems, got)
+			}
+			assert.NoError(t, r.ExpectationsWereMet())
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBidsVersion_Get(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		setup   func(r redismock.ClientMock)
+		want    int64
+		wantErr bool
+	}{
+		{"bumped", func(r redismock.ClientMock) { r.ExpectGet("bids:version:a_1").SetVal("12") }, 12, false},
+		{"never bumped", func(r redismock.ClientMock) { r.ExpectGet("bids:version:a_1").RedisNil() }, 0, false},
+		{"redis down", func(r redismock.ClientMock) {
+			r.ExpectGet("bids:version:a_1").SetErr(errors.New("connection refused"))
+		}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, r := redismock.NewClientMock()
+			tt.setup(r)
+
+			got, err := NewBidsVersion(db).Get(ctx, "a_1")
+
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+			assert.NoError(t, r.ExpectationsWereMet())
+		})
+	}
+}
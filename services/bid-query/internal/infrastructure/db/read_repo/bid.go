@@ -3,6 +3,7 @@ package read_repo
 import (
 	"context"
 	"errors"
+	mongoInfra "kei-services/pkg/infra/mongo"
 	"kei-services/pkg/middleware"
 	"kei-services/services/bid-query/internal/application/list_bids"
 	"kei-services/services/bid-query/internal/read_model"
@@ -16,21 +17,28 @@ import (
 
 var _ list_bids.IBidReadRepository = (*MongoBidReadRepo)(nil)
 
+// MongoBidReadRepo reads from whatever collection coll currently returns, so a rebuilt read model can be
+// switched in without a restart
 type MongoBidReadRepo struct {
-	coll *mongo.Collection
+	coll mongoInfra.CollectionSource
 	log  *zap.Logger
 }
 
-func NewMongoBidReadRepo(db *mongo.Database, collection string, log *zap.Logger) *MongoBidReadRepo {
+func NewMongoBidReadRepo(coll mongoInfra.CollectionSource, log *zap.Logger) *MongoBidReadRepo {
 	return &MongoBidReadRepo{
-		coll: db.Collection(collection),
+		coll: coll,
 		log:  log,
 	}
 }
 
-// EnsureIndexes creates indexes. Called only once on service startup
+// EnsureIndexes creates indexes on the current collection. Called only once on service startup
 func (r *MongoBidReadRepo) EnsureIndexes(ctx context.Context) error {
-	if r.coll == nil {
+	return EnsureIndexes(ctx, r.coll.Collection())
+}
+
+// EnsureIndexes creates the indexes listing needs, also run on a rebuilt collection before it's served
+func EnsureIndexes(ctx context.Context, coll *mongo.Collection) error {
+	if coll == nil {
 		return errors.New("nil collection")
 	}
 
@@ -46,7 +54,7 @@ func (r *MongoBidReadRepo) EnsureIndexes(ctx context.Context) error {
 			Options: options.Index().SetName("auction_at_asc_bid_asc"),
 		},
 	}
-	_, err := r.coll.Indexes().CreateMany(ctx, models)
+	_, err := coll.Indexes().CreateMany(ctx, models)
 	return err
 }
 
@@ -81,7 +89,7 @@ func (r *MongoBidReadRepo) ListByAuction(ctx context.Context, auctionID string,
 		SetLimit(int64(limit + 1)) // fetch one extra to detect hasMore
 
 	log.Debug("listing bids", zap.Any("filter", f), zap.Any("findOpts", findOpts))
-	cur, err := r.coll.Find(ctx, f, findOpts)
+	cur, err := r.coll.Collection().Find(ctx, f, findOpts)
 	if err != nil {
 		log.Warn("failed to list items", zap.Error(err))
 		return nil, false, nil, err
@@ -0,0 +1,60 @@
+package read_repo
+
+import (
+	"context"
+	"kei-services/pkg/middleware"
+	"kei-services/services/bid-query/internal/application/export_bids"
+	"kei-services/services/bid-query/internal/read_model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+var _ export_bids.IBidStreamRepository = (*MongoBidReadRepo)(nil)
+
+// exportBatchSize bounds how many documents the driver buffers per round trip
+const exportBatchSize = 1000
+
+// StreamByAuction iterates the mongo cursor one document at a time so memory stays constant
+// regardless of the auction size. Uses the same (auctionId, at, bidId) indexes as ListByAuction
+func (r *MongoBidReadRepo) StreamByAuction(ctx context.Context, auctionID string, asc bool,
+	fn func(export_bids.Row) error) error {
+	log := middleware.LoggerFrom(ctx, r.log).With(zap.String("auctionId", auctionID))
+
+	sort := bson.D{{Key: "at", Value: -1}, {Key: "bidId", Value: -1}}
+	if asc {
+		sort = bson.D{{Key: "at", Value: 1}, {Key: "bidId", Value: 1}}
+	}
+
+	findOpts := options.Find().
+		SetSort(sort).
+		SetBatchSize(exportBatchSize)
+
+	log.Debug("streaming bids", zap.Bool("asc", asc))
+	cur, err := r.coll.Collection().Find(ctx, bson.M{"auctionId": auctionID}, findOpts)
+	if err != nil {
+		log.Warn("failed to open export cursor", zap.Error(err))
+		return err
+	}
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var d read_model.Bids
+		if err = cur.Decode(&d); err != nil {
+			log.Warn("failed to decode item", zap.Error(err))
+			return err
+		}
+		if err = fn(export_bids.Row{
+			BidID:     d.BidID,
+			AuctionID: d.AuctionID,
+			BidderID:  d.BidderID,
+			Amount:    d.Amount,
+			At:        toTime(d.At),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return cur.Err()
+}
@@ -0,0 +1,115 @@
+package read_repo
+
+import (
+	"context"
+	"kei-services/pkg/middleware"
+	"kei-services/services/bid-query/internal/application/bid_timeseries"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+var _ bid_timeseries.IBidTimeseriesRepository = (*MongoBidReadRepo)(nil)
+
+type bucketDoc struct {
+	Start time.Time `bson:"_id"`
+	Count int64     `bson:"count"`
+	Open  float64   `bson:"open"`
+	High  float64   `bson:"high"`
+	Low   float64   `bson:"low"`
+	Close float64   `bson:"close"`
+}
+
+// Buckets aggregates server side with $dateTrunc (MongoDB 5.0+). Bids are sorted by (at, bidId) before
+// grouping so $first/$last give a deterministic open/close. The $match + $sort prefix uses the
+// auction_at_asc_bid_asc index. Without from the latest buckets are kept, they are limited newest first and
+// reversed here
+func (r *MongoBidReadRepo) Buckets(ctx context.Context, auctionID string, interval time.Duration,
+	from, to *time.Time, limit int) ([]bid_timeseries.Bucket, error) {
+	log := middleware.LoggerFrom(ctx, r.log).With(zap.String("auctionId", auctionID))
+
+	match := bson.M{"auctionId": auctionID}
+	if from != nil || to != nil {
+		at := bson.M{}
+		if from != nil {
+			at["$gte"] = *from
+		}
+		if to != nil {
+			at["$lt"] = *to
+		}
+		match["at"] = at
+	}
+
+	unit, binSize := dateTruncUnit(interval)
+	pipeline := bson.A{
+		bson.M{"$match": match},
+		bson.M{"$sort": bson.D{{Key: "at", Value: 1}, {Key: "bidId", Value: 1}}},
+		bson.M{"$group": bson.M{
+			"_id": bson.M{"$dateTrunc": bson.M{
+				"date":     "$at",
+				"unit":     unit,
+				"binSize":  binSize,
+				"timezone": "UTC",
+			}},
+			"count": bson.M{"$sum": 1},
+			"open":  bson.M{"$first": "$amount"},
+			"high":  bson.M{"$max": "$amount"},
+			"low":   bson.M{"$min": "$amount"},
+			"close": bson.M{"$last": "$amount"},
+		}},
+		bson.M{"$sort": bson.M{"_id": bucketOrder(from)}},
+		bson.M{"$limit": limit},
+	}
+
+	log.Debug("aggregating bid buckets", zap.String("unit", unit), zap.Int64("binSize", binSize))
+	cur, err := r.coll.Collection().Aggregate(ctx, pipeline)
+	if err != nil {
+		log.Warn("failed to aggregate", zap.Error(err))
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var docs []bucketDoc
+	if err = cur.All(ctx, &docs); err != nil {
+		log.Warn("failed to decode buckets", zap.Error(err))
+		return nil, err
+	}
+
+	if from == nil {
+		slices.Reverse(docs)
+	}
+	out := make([]bid_timeseries.Bucket, 0, len(docs))
+	for _, d := range docs {
+		out = append(out, bid_timeseries.Bucket{
+			Start: d.Start.UTC(),
+			Count: d.Count,
+			Open:  d.Open,
+			High:  d.High,
+			Low:   d.Low,
+			Close: d.Close,
+		})
+	}
+	return out, nil
+}
+
+// bucketOrder sorts the buckets newest first when from is open, so $limit keeps the latest ones
+func bucketOrder(from *time.Time) int {
+	if from == nil {
+		return -1
+	}
+	return 1
+}
+
+// dateTruncUnit maps an interval onto the largest whole $dateTrunc unit
+func dateTruncUnit(d time.Duration) (unit string, binSize int64) {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return "day", int64(d / (24 * time.Hour))
+	case d%time.Hour == 0:
+		return "hour", int64(d / time.Hour)
+	default:
+		return "minute", int64(d / time.Minute)
+	}
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"errors"
+	"kei-services/pkg/middleware"
+	"kei-services/services/bid-query/internal/application/bid_timeseries"
+	"kei-services/services/bid-query/openapi"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type TimeseriesController struct {
+	log *zap.Logger
+	svc bid_timeseries.IService
+}
+
+func NewTimeseriesController(log *zap.Logger, svc bid_timeseries.IService) *TimeseriesController {
+	return &TimeseriesController{log: log, svc: svc}
+}
+
+func (h *TimeseriesController) GetApiV1AuctionsAuctionIdTimeseries(c *gin.Context, auctionId string,
+	params openapi.GetApiV1AuctionsAuctionIdTimeseriesParams) {
+	log := middleware.LoggerFrom(c.Request.Context(), h.log)
+
+	log.Info("bid timeseries: request received", zap.String("auctionId", auctionId), zap.Any("params", params))
+
+	intervalStr := ""
+	if params.Interval != nil {
+		intervalStr = string(*params.Interval)
+	}
+	interval, err := bid_timeseries.ParseInterval(intervalStr)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	res, err := h.svc.Handle(c.Request.Context(), bid_timeseries.Query{
+		AuctionID: auctionId,
+		Interval:  interval,
+		From:      params.From,
+		To:        params.To,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	buckets := make([]openapi.BidTimeseriesBucket, 0, len(res.Buckets))
+	for _, b := range res.Buckets {
+		buckets = append(buckets, openapi.BidTimeseriesBucket{
+			Start: b.Start,
+			Count: b.Count,
+			Open:  b.Open,
+			High:  b.High,
+			Low:   b.Low,
+			Close: b.Close,
+		})
+	}
+
+	log.Info("bid timeseries: response",
+		zap.String("auctionId", auctionId),
+		zap.Int("buckets", len(buckets)),
+		zap.Bool("truncated", res.Truncated),
+	)
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, openapi.BidTimeseriesResponse{
+		AuctionId: res.AuctionID,
+		Interval:  string(res.Interval),
+		Buckets:   buckets,
+		Truncated: &res.Truncated,
+	})
+}
+
+func (h *TimeseriesController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, bid_timeseries.ErrInvalidInterval):
+		writeProblem(c, http.StatusBadRequest,
+			"https://example.com/problems/invalid-request",
+			"Invalid interval",
+			"interval must be one of 1m, 5m, 15m, 1h, 1d",
+		)
+	case errors.Is(err, bid_timeseries.ErrInvalidRange):
+		writeProblem(c, http.StatusBadRequest,
+			"https://example.com/problems/invalid-request",
+			"Invalid time range",
+			"from must be before to",
+		)
+	default:
+		h.log.Error("bid timeseries failed", zap.Error(err))
+		writeProblem(c, http.StatusInternalServerError,
+			"https://example.com/problems/internal",
+			"Internal Server Error",
+			"An unexpected error occurred",
+		)
+	}
+}
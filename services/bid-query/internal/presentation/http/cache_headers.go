@@ -0,0 +1,47 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheHeaders are set on successful list responses. Browsers get a short max-age and revalidate with
+// If-None-Match, shared caches keep pages longer and are purged by surrogate key when a bid arrives
+type CacheHeaders struct {
+	MaxAge          time.Duration
+	SurrogateMaxAge time.Duration
+}
+
+// SurrogateKey tags every page of an auction, bid-projector purges the same key on each new bid
+func SurrogateKey(auctionID string) string {
+	return "bids-" + auctionID
+}
+
+func (h CacheHeaders) apply(c *gin.Context, auctionID, etag string) {
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(h.MaxAge.Seconds()))+", must-revalidate")
+	if h.SurrogateMaxAge > 0 {
+		c.Header("Surrogate-Control", "max-age="+strconv.Itoa(int(h.SurrogateMaxAge.Seconds())))
+		c.Header("Surrogate-Key", SurrogateKey(auctionID))
+	}
+}
+
+// etagMatches implements the weak comparison If-None-Match requires (RFC 9110 13.1.2)
+func etagMatches(header, etag string) bool {
+	if header == "" || etag == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -2,18 +2,16 @@ package http
 
 import (
 	"kei-services/services/bid-query/internal/application/list_bids"
-	"kei-services/services/bid-query/openapi"
 
 	"go.uber.org/zap"
 )
 
 type HttpController struct {
-	log *zap.Logger
-	svc list_bids.IService
+	log   *zap.Logger
+	svc   list_bids.IService
+	cache CacheHeaders
 }
 
-func NewHttpController(log *zap.Logger, svc list_bids.IService) *HttpController {
-	return &HttpController{log: log, svc: svc}
+func NewHttpController(log *zap.Logger, svc list_bids.IService, cache CacheHeaders) *HttpController {
+	return &HttpController{log: log, svc: svc, cache: cache}
 }
-
-var _ openapi.ServerInterface = (*HttpController)(nil)
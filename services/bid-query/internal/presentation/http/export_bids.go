@@ -0,0 +1,222 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"kei-services/pkg/middleware"
+	"kei-services/services/bid-query/internal/application/export_bids"
+	"kei-services/services/bid-query/openapi"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// flush to the client every n rows so large exports start downloading immediately
+	exportFlushEvery = 500
+	// exports outlive the server WriteTimeout, extended per request instead of globally
+	exportWriteTimeout = 30 * time.Minute
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+type ExportController struct {
+	log *zap.Logger
+	svc export_bids.IService
+}
+
+func NewExportController(log *zap.Logger, svc export_bids.IService) *ExportController {
+	return &ExportController{log: log, svc: svc}
+}
+
+func (h *ExportController) GetApiV1BidsAuctionIdExport(c *gin.Context, auctionId string,
+	params openapi.GetApiV1BidsAuctionIdExportParams) {
+	log := middleware.LoggerFrom(c.Request.Context(), h.log)
+
+	log.Info("export bids: request received", zap.String("auctionId", auctionId), zap.Any("params", params))
+
+	format := openapi.Csv
+	if params.Format != nil {
+		format = *params.Format
+	}
+
+	var (
+		contentType string
+		newEncoder  func(w http.ResponseWriter) rowEncoder
+	)
+	switch format {
+	case openapi.Csv:
+		contentType = "text/csv; charset=utf-8"
+		newEncoder = newCSVEncoder
+	case openapi.Ndjson:
+		contentType = "application/x-ndjson"
+		newEncoder = newNDJSONEncoder
+	default:
+		writeProblem(c, http.StatusBadRequest,
+			"https://example.com/problems/invalid-request",
+			"Unsupported export format",
+			"format must be one of csv, ndjson",
+		)
+		return
+	}
+
+	asc := params.Direction != nil && strings.EqualFold(string(*params.Direction), "asc")
+
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil {
+		log.Warn("export bids: extend write deadline", zap.Error(err))
+	}
+
+	// headers are committed lazily on the first row, so errors before that can still be problem details
+	var enc rowEncoder
+	start := func() {
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": "bids-" + unsafeFileChars.ReplaceAllString(auctionId, "_") + "." + string(format),
+		}))
+		c.Header("Cache-Control", "no-store")
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.Status(http.StatusOK)
+		enc = newEncoder(c.Writer)
+	}
+
+	rows, err := h.svc.Handle(c.Request.Context(), export_bids.Query{AuctionID: auctionId, Asc: asc},
+		func(r export_bids.Row) error {
+			if enc == nil {
+				start()
+			}
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+			return enc.FlushEvery(exportFlushEvery, c.Writer)
+		})
+	if err != nil {
+		if enc == nil {
+			h.handleError(c, err)
+			return
+		}
+		// status line already sent, all we can do is stop and log, the client receives a short file
+		log.Error("export bids: aborted mid stream", zap.Int("rows", rows), zap.Error(err))
+		_ = c.Error(err)
+		return
+	}
+
+	if enc == nil {
+		// no bids, still a valid (empty) export
+		start()
+	}
+	if err = enc.Close(c.Writer); err != nil {
+		log.Warn("export bids: final flush", zap.Error(err))
+	}
+
+	log.Info("export bids: done", zap.String("auctionId", auctionId), zap.Int("rows", rows))
+}
+
+func (h *ExportController) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, export_bids.ErrInvalidAuctionID):
+		writeProblem(c, http.StatusBadRequest,
+			"https://example.com/problems/invalid-request",
+			"Invalid auction id",
+			"auctionId must not be empty",
+		)
+	default:
+		h.log.Error("export bids failed", zap.Error(err))
+		writeProblem(c, http.StatusInternalServerError,
+			"https://example.com/problems/internal",
+			"Internal Server Error",
+			"An unexpected error occurred",
+		)
+	}
+}
+
+// rowEncoder writes export rows in a given format
+type rowEncoder interface {
+	Encode(r export_bids.Row) error
+	FlushEvery(n int, w http.ResponseWriter) error
+	Close(w http.ResponseWriter) error
+}
+
+type csvEncoder struct {
+	w    *csv.Writer
+	rows int
+}
+
+func newCSVEncoder(w http.ResponseWriter) rowEncoder {
+	e := &csvEncoder{w: csv.NewWriter(w)}
+	_ = e.w.Write([]string{"bidId", "auctionId", "bidderId", "amount", "at"})
+	return e
+}
+
+func (e *csvEncoder) Encode(r export_bids.Row) error {
+	e.rows++
+	return e.w.Write([]string{
+		csvText(r.BidID),
+		csvText(r.AuctionID),
+		csvText(r.BidderID),
+		strconv.FormatFloat(r.Amount, 'f', -1, 64),
+		r.At.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// csvText neutralises free text cells that a spreadsheet would otherwise evaluate as a formula
+// (CSV injection), ids are client supplied so a bidder id like "=HYPERLINK(...)" must stay inert
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (e *csvEncoder) FlushEvery(n int, w http.ResponseWriter) error {
+	if e.rows%n != 0 {
+		return nil
+	}
+	return e.Close(w)
+}
+
+func (e *csvEncoder) Close(w http.ResponseWriter) error {
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return err
+	}
+	return http.NewResponseController(w).Flush()
+}
+
+type ndjsonEncoder struct {
+	enc  *json.Encoder
+	rows int
+}
+
+func newNDJSONEncoder(w http.ResponseWriter) rowEncoder {
+	return &ndjsonEncoder{enc: json.NewEncoder(w)}
+}
+
+func (e *ndjsonEncoder) Encode(r export_bids.Row) error {
+	e.rows++
+	// json.Encoder terminates every value with a newline
+	return e.enc.Encode(openapi.Bid{
+		BidId:     r.BidID,
+		AuctionId: r.AuctionID,
+		BidderId:  r.BidderID,
+		Amount:    r.Amount,
+		At:        r.At,
+	})
+}
+
+func (e *ndjsonEncoder) FlushEvery(n int, w http.ResponseWriter) error {
+	if e.rows%n != 0 {
+		return nil
+	}
+	return e.Close(w)
+}
+
+func (e *ndjsonEncoder) Close(w http.ResponseWriter) error {
+	return http.NewResponseController(w).Flush()
+}
@@ -55,12 +55,30 @@ func (h *HttpController) GetApiV1BidsAuctionId(c *gin.Context, auctionId string,
 		Direction: direction,
 	}
 
+	// cheap revalidation: answer 304 from the version key before touching mongo
+	ifNoneMatch := strDeref(params.IfNoneMatch)
+	if ifNoneMatch != "" {
+		etag, err := h.svc.ETag(c.Request.Context(), q)
+		if err != nil {
+			log.Debug("list bids: etag unavailable, serving full response", zap.Error(err))
+		} else if etagMatches(ifNoneMatch, etag) {
+			h.notModified(c, auctionId, etag)
+			return
+		}
+	}
+
 	res, err := h.svc.Handle(c.Request.Context(), q)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
+	// content etag fallback still saves the body
+	if etagMatches(ifNoneMatch, res.ETag) {
+		h.notModified(c, auctionId, res.ETag)
+		return
+	}
+
 	// map to openapi
 	items := make([]openapi.Bid, 0, len(res.Items))
 	for _, it := range res.Items {
@@ -85,6 +103,7 @@ func (h *HttpController) GetApiV1BidsAuctionId(c *gin.Context, auctionId string,
 		zap.String("nextCursor", strDeref(res.NextCursor)),
 		zap.String("firstBidId", first),
 		zap.String("lastBidId", last),
+		zap.String("etag", res.ETag),
 	)
 
 	body := openapi.ListBidsResponse{
@@ -97,17 +116,39 @@ func (h *HttpController) GetApiV1BidsAuctionId(c *gin.Context, auctionId string,
 		c.Header("X-Next-Cursor", *res.NextCursor)
 	}
 
+	h.cache.apply(c, auctionId, res.ETag)
 	c.Header("Content-Type", "application/json")
 	c.JSON(http.StatusOK, body)
 }
 
+func (h *HttpController) notModified(c *gin.Context, auctionID, etag string) {
+	middleware.LoggerFrom(c.Request.Context(), h.log).Debug("list bids: not modified",
+		zap.String("auctionId", auctionID), zap.String("etag", etag))
+
+	h.cache.apply(c, auctionID, etag)
+	c.Status(http.StatusNotModified)
+}
+
 func (h *HttpController) handleError(c *gin.Context, err error) {
+	c.Header("Cache-Control", "no-store")
 	switch {
+	case errors.Is(err, list_bids.ErrCursorExpired):
+		writeProblem(c, http.StatusBadRequest,
+			"https://example.com/problems/cursor-expired",
+			"Cursor expired",
+			"The supplied cursor has expired, restart from the first page",
+		)
+	case errors.Is(err, list_bids.ErrCursorQueryMismatch):
+		writeProblem(c, http.StatusBadRequest,
+			"https://example.com/problems/invalid-cursor",
+			"Invalid cursor",
+			"The supplied cursor was issued for a different auction, direction or limit",
+		)
 	case errors.Is(err, list_bids.ErrInvalidCursor):
 		writeProblem(c, http.StatusBadRequest,
 			"https://example.com/problems/invalid-cursor",
 			"Invalid cursor",
-			"The supplied cursor could not be parsed",
+			"The supplied cursor is malformed or its signature could not be verified",
 		)
 	case errors.Is(err, list_bids.ErrAuctionNotFound):
 		writeProblem(c, http.StatusNotFound,
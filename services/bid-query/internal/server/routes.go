@@ -1,11 +1,10 @@
 package server
 
 import (
-	"context"
+	"kei-services/pkg/health"
 	"kei-services/services/bid-query/internal/cfg"
 	httpPresentation "kei-services/services/bid-query/internal/presentation/http"
 	"kei-services/services/bid-query/openapi"
-	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,67 +13,61 @@ import (
 	"go.uber.org/zap"
 )
 
-func registerProtectedRoutes(r *gin.Engine, d *deps, _ *cfg.Config, log *zap.Logger) {
+func registerProtectedRoutes(r *gin.Engine, d *deps, cfg *cfg.Config, log *zap.Logger) {
 	protected := r.Group("")
 	protected.Use()
 
 	m := &MasterHandler{
-		ListBidsHandler: *httpPresentation.NewHttpController(log, d.ListBidsService),
+		ListBidsHandler:   *httpPresentation.NewHttpController(log, d.ListBidsService, cacheHeaders(cfg.HttpCache)),
+		ExportBidsHandler: *httpPresentation.NewExportController(log, d.ExportBidsService),
+		TimeseriesHandler: *httpPresentation.NewTimeseriesController(log, d.BidTimeseriesService),
 	}
 
 	openapi.RegisterHandlers(protected, m)
 }
 
+func cacheHeaders(c *cfg.HttpCacheConfig) httpPresentation.CacheHeaders {
+	h := httpPresentation.CacheHeaders{MaxAge: time.Second}
+	if c == nil {
+		return h
+	}
+	if c.MaxAgeSec > 0 {
+		h.MaxAge = time.Duration(c.MaxAgeSec) * time.Second
+	}
+	h.SurrogateMaxAge = time.Duration(c.SurrogateMaxAgeSec) * time.Second
+	return h
+}
+
+var _ openapi.ServerInterface = (*MasterHandler)(nil)
+
 type MasterHandler struct {
-	ListBidsHandler httpPresentation.HttpController
+	ListBidsHandler   httpPresentation.HttpController
+	ExportBidsHandler httpPresentation.ExportController
+	TimeseriesHandler httpPresentation.TimeseriesController
 }
 
 func (m MasterHandler) GetApiV1BidsAuctionId(c *gin.Context, auctionId string, params openapi.GetApiV1BidsAuctionIdParams) {
 	m.ListBidsHandler.GetApiV1BidsAuctionId(c, auctionId, params)
 }
 
-func registerHealthroutes(r *gin.Engine, db *mongo.Database, redis *redis.Client, _ *zap.Logger) {
-	r.GET("/healthz", func(c *gin.Context) {
-		c.String(http.StatusOK, "ok")
-	})
-
-	r.GET("/readyz", func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 500*time.Millisecond)
-		defer cancel()
-
-		type check struct {
-			Name string `json:"name"`
-			OK   bool   `json:"ok"`
-			Err  string `json:"err,omitempty"`
-		}
-
-		var checks []check
-		allOK := true
-
-		// DB ping
-		if err := db.Client().Ping(ctx, nil); err != nil {
-			allOK = false
-			checks = append(checks, check{Name: "db", OK: false, Err: err.Error()})
-		} else {
-			checks = append(checks, check{Name: "db", OK: true})
-		}
+func (m MasterHandler) GetApiV1BidsAuctionIdExport(c *gin.Context, auctionId string,
+	params openapi.GetApiV1BidsAuctionIdExportParams) {
+	m.ExportBidsHandler.GetApiV1BidsAuctionIdExport(c, auctionId, params)
+}
 
-		// Redis ping
-		if err := redis.Ping(ctx).Err(); err != nil {
-			allOK = false
-			checks = append(checks, check{Name: "redis", OK: false, Err: err.Error()})
-		} else {
-			checks = append(checks, check{Name: "redis", OK: true})
-		}
+func (m MasterHandler) GetApiV1AuctionsAuctionIdTimeseries(c *gin.Context, auctionId string,
+	params openapi.GetApiV1AuctionsAuctionIdTimeseriesParams) {
+	m.TimeseriesHandler.GetApiV1AuctionsAuctionIdTimeseries(c, auctionId, params)
+}
 
-		status := http.StatusOK
-		if !allOK {
-			status = http.StatusServiceUnavailable
-		}
-		c.JSON(status, gin.H{
-			"status": map[bool]string{true: "ready", false: "degraded"}[allOK],
-			"checks": checks,
-			"uptime": time.Since(time.Unix(0, 0)).String(), // todo use start time
-		})
-	})
+// registerHealthRoutes serves the probes, they pass once MarkStarted is called on the returned Health
+func registerHealthRoutes(r *gin.Engine, db *mongo.Database, redis redis.UniversalClient, c *cfg.Config) *health.Health {
+	h := health.New(health.NewBuildInfo("bid-query", c.App))
+	mongoCheck := health.Mongo(db.Client())
+	mongoCheck.Startup = true
+	redisCheck := health.Redis(redis)
+	redisCheck.Optional = true // only caches, reads fall back to mongo
+	h.Add(mongoCheck, redisCheck)
+	h.Register(r)
+	return h
 }
@@ -7,6 +7,7 @@ import (
 	"kei-services/pkg/metrics"
 	"kei-services/pkg/middleware"
 	swagger "kei-services/pkg/swagger"
+	"kei-services/pkg/tracing"
 	"kei-services/services/bid-query/internal/cfg"
 	"kei-services/services/bid-query/openapi"
 
@@ -21,17 +22,19 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 )
 
 type Server struct {
 	srv    *http.Server
 	engine *gin.Engine
+	stopBg context.CancelFunc // stops background tasks started by New
 	cfg    *cfg.Config
 	log    *zap.Logger
 }
 
-func New(db *mongo.Database, redis *redis.Client, cfg *cfg.Config, log *zap.Logger) *Server {
+func New(db *mongo.Database, redis redis.UniversalClient, cfg *cfg.Config, log *zap.Logger) *Server {
 	if cfg.App.Environment == "prod" {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
@@ -54,6 +57,7 @@ func New(db *mongo.Database, redis *redis.Client, cfg *cfg.Config, log *zap.Logg
 	r.Use(
 		metrics.PanicCounterMiddleware(httpMx),
 		ginzap.RecoveryWithZap(log, true),
+		otelgin.Middleware("bid-query", otelgin.WithFilter(tracing.TraceRequest)),
 		ginzap.Ginzap(log, time.RFC3339, true),
 		metrics.GinAdapterMiddleware(httpMx),
 
@@ -71,8 +75,10 @@ func New(db *mongo.Database, redis *redis.Client, cfg *cfg.Config, log *zap.Logg
 
 	r.GET("/metrics", gin.WrapH(met.Handler)) // prometheus
 
-	registerHealthroutes(r, db, redis, log)
-	registerProtectedRoutes(r, initDependencies(db, redis, cfg, log), cfg, log)
+	hc := registerHealthRoutes(r, db, redis, cfg)
+	bg, stopBg := context.WithCancel(context.Background())
+	registerProtectedRoutes(r, initDependencies(bg, db, redis, met, cfg, log), cfg, log)
+	hc.MarkStarted()
 
 	r.NoRoute(func(c *gin.Context) { c.JSON(404, gin.H{"error": "not found"}) })
 	r.NoMethod(func(c *gin.Context) { c.JSON(405, gin.H{"error": "method not allowed"}) })
@@ -92,7 +98,7 @@ func New(db *mongo.Database, redis *redis.Client, cfg *cfg.Config, log *zap.Logg
 		MaxHeaderBytes:    1 << 20, // 1mb
 	}
 
-	return &Server{srv: srv, engine: r, cfg: cfg, log: log}
+	return &Server{srv: srv, engine: r, stopBg: stopBg, cfg: cfg, log: log}
 }
 
 func Start(s *Server, cfg *cfg.Config, log *zap.Logger) error {
@@ -114,6 +120,7 @@ func Start(s *Server, cfg *cfg.Config, log *zap.Logger) error {
 
 func Shutdown(ctx context.Context, s *Server, log *zap.Logger) error {
 	log.Info("Shutting down server")
+	defer s.stopBg()
 	return s.srv.Shutdown(ctx)
 }
 
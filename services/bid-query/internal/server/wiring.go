@@ -1,9 +1,17 @@
 package server
 
 import (
+	"context"
+	"errors"
+	mongoInfra "kei-services/pkg/infra/mongo"
+	"kei-services/pkg/metrics"
+	"kei-services/services/bid-query/internal/application/bid_timeseries"
+	"kei-services/services/bid-query/internal/application/export_bids"
 	"kei-services/services/bid-query/internal/application/list_bids"
 	"kei-services/services/bid-query/internal/cfg"
+	"kei-services/services/bid-query/internal/infrastructure/cache"
 	"kei-services/services/bid-query/internal/infrastructure/db/read_repo"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -11,17 +19,74 @@ import (
 )
 
 type deps struct {
-	ListBidsService list_bids.IService
+	ListBidsService      list_bids.IService
+	ExportBidsService    export_bids.IService
+	BidTimeseriesService bid_timeseries.IService
 }
 
-func initDependencies(db *mongo.Database, _ *redis.Client, _ *cfg.Config, log *zap.Logger) *deps {
+func initDependencies(bg context.Context, db *mongo.Database, redis redis.UniversalClient, met *metrics.Registry,
+	cfg *cfg.Config, log *zap.Logger) *deps {
+	// follows projection rebuilds, a rebuilt collection gets its indexes before it's served
+	bidsHistory := mongoInfra.NewActiveCollection(db, "bids_history", log)
+	if err := bidsHistory.Refresh(bg, read_repo.EnsureIndexes); err != nil {
+		log.Warn("resolve active bids_history collection", zap.Error(err))
+	}
+	go bidsHistory.Watch(bg, mongoInfra.DefaultRefreshInterval, read_repo.EnsureIndexes)
+
+	mongoBidReadRepo := read_repo.NewMongoBidReadRepo(bidsHistory, log)
+	var bidReadRepo list_bids.IBidReadRepository = mongoBidReadRepo
+
+	if c := cfg.FirstPageCache; c != nil && c.IsEnabled {
+		ttl := time.Duration(c.TTLSec) * time.Second
+		if ttl <= 0 {
+			ttl = 30 * time.Second
+		}
+		requests := metrics.CCounter(met.Reg, met.DefaultNS, "list_bids_cache_requests_total",
+			"First page cache lookups by result (hit, miss, error).", nil, []string{"result"})
+
+		// keyed by the active collection, pages read before a rebuild switched it are not served after
+		collection := func() string { return bidsHistory.Collection().Name() }
+		bidReadRepo = cache.NewFirstPageCache(bidReadRepo, collection, redis, ttl, requests, log)
+		log.Info("list bids first page cache enabled", zap.Duration("ttl", ttl))
+	}
+
+	cursors, err := newCursorCodec(cfg.Cursor)
+	if err != nil {
+		log.Fatal("cursor codec", zap.Error(err))
+	}
 
 	listBidService := list_bids.NewService(list_bids.Deps{
-		BidReadRepo: read_repo.NewMongoBidReadRepo(db, "bids_history", log)},
+		BidReadRepo: bidReadRepo,
+		Cursors:     cursors,
+		Versions:    cache.NewBidsVersion(redis)},
+		log,
+	)
+
+	// exports bypass the first page cache and stream straight from mongo
+	exportBidsService := export_bids.NewService(export_bids.Deps{
+		BidStreamRepo: mongoBidReadRepo},
+		log,
+	)
+
+	bidTimeseriesService := bid_timeseries.NewService(bid_timeseries.Deps{
+		TimeseriesRepo: mongoBidReadRepo},
 		log,
 	)
 
 	return &deps{
-		ListBidsService: listBidService,
+		ListBidsService:      listBidService,
+		ExportBidsService:    exportBidsService,
+		BidTimeseriesService: bidTimeseriesService,
+	}
+}
+
+func newCursorCodec(c *cfg.CursorConfig) (*list_bids.CursorCodec, error) {
+	if c == nil {
+		return nil, errors.New("missing cursor config")
+	}
+	keys := make([]list_bids.CursorKey, 0, len(c.Keys))
+	for _, k := range c.Keys {
+		keys = append(keys, list_bids.CursorKey{ID: k.ID, Secret: []byte(k.Secret)})
 	}
+	return list_bids.NewCursorCodec(keys, time.Duration(c.TTLSec)*time.Second)
 }
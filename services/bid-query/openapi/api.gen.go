@@ -22,10 +22,31 @@ import (
 	"github.com/oapi-codegen/runtime"
 )
 
+// Defines values for GetApiV1AuctionsAuctionIdTimeseriesParamsInterval.
+const (
+	N15m GetApiV1AuctionsAuctionIdTimeseriesParamsInterval = "15m"
+	N1d  GetApiV1AuctionsAuctionIdTimeseriesParamsInterval = "1d"
+	N1h  GetApiV1AuctionsAuctionIdTimeseriesParamsInterval = "1h"
+	N1m  GetApiV1AuctionsAuctionIdTimeseriesParamsInterval = "1m"
+	N5m  GetApiV1AuctionsAuctionIdTimeseriesParamsInterval = "5m"
+)
+
 // Defines values for GetApiV1BidsAuctionIdParamsDirection.
 const (
-	Asc  GetApiV1BidsAuctionIdParamsDirection = "asc"
-	Desc GetApiV1BidsAuctionIdParamsDirection = "desc"
+	GetApiV1BidsAuctionIdParamsDirectionAsc  GetApiV1BidsAuctionIdParamsDirection = "asc"
+	GetApiV1BidsAuctionIdParamsDirectionDesc GetApiV1BidsAuctionIdParamsDirection = "desc"
+)
+
+// Defines values for GetApiV1BidsAuctionIdExportParamsFormat.
+const (
+	Csv    GetApiV1BidsAuctionIdExportParamsFormat = "csv"
+	Ndjson GetApiV1BidsAuctionIdExportParamsFormat = "ndjson"
+)
+
+// Defines values for GetApiV1BidsAuctionIdExportParamsDirection.
+const (
+	GetApiV1BidsAuctionIdExportParamsDirectionAsc  GetApiV1BidsAuctionIdExportParamsDirection = "asc"
+	GetApiV1BidsAuctionIdExportParamsDirectionDesc GetApiV1BidsAuctionIdExportParamsDirection = "desc"
 )
 
 // Bid defines model for Bid.
@@ -37,6 +58,31 @@ type Bid struct {
 	BidderId  string    `json:"bidderId"`
 }
 
+// BidTimeseriesBucket defines model for BidTimeseriesBucket.
+type BidTimeseriesBucket struct {
+	// Close Last accepted amount in the interval
+	Close float64 `json:"close"`
+	Count int64   `json:"count"`
+	High  float64 `json:"high"`
+	Low   float64 `json:"low"`
+
+	// Open First accepted amount in the interval
+	Open float64 `json:"open"`
+
+	// Start Start of the interval (inclusive)
+	Start time.Time `json:"start"`
+}
+
+// BidTimeseriesResponse defines model for BidTimeseriesResponse.
+type BidTimeseriesResponse struct {
+	AuctionId string                `json:"auctionId"`
+	Buckets   []BidTimeseriesBucket `json:"buckets"`
+	Interval  string                `json:"interval"`
+
+	// Truncated True when more buckets exist, before the first one returned when `from` is omitted, after the last one otherwise.
+	Truncated *bool `json:"truncated,omitempty"`
+}
+
 // ListBidsResponse defines model for ListBidsResponse.
 type ListBidsResponse struct {
 	HasMore *bool `json:"hasMore,omitempty"`
@@ -64,9 +110,24 @@ type ProblemDetails struct {
 	Type string `json:"type"`
 }
 
+// GetApiV1AuctionsAuctionIdTimeseriesParams defines parameters for GetApiV1AuctionsAuctionIdTimeseries.
+type GetApiV1AuctionsAuctionIdTimeseriesParams struct {
+	// Interval Bucket width.
+	Interval *GetApiV1AuctionsAuctionIdTimeseriesParamsInterval `form:"interval,omitempty" json:"interval,omitempty"`
+
+	// From Inclusive lower bound on bid time, omit to start from the first bid.
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Exclusive upper bound on bid time, omit to include the latest bid.
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+}
+
+// GetApiV1AuctionsAuctionIdTimeseriesParamsInterval defines parameters for GetApiV1AuctionsAuctionIdTimeseries.
+type GetApiV1AuctionsAuctionIdTimeseriesParamsInterval string
+
 // GetApiV1BidsAuctionIdParams defines parameters for GetApiV1BidsAuctionId.
 type GetApiV1BidsAuctionIdParams struct {
-	// Cursor Cursor from the previous page, omit for the first page
+	// Cursor Signed cursor from the previous page, omit for the first page. Cursors expire and are only valid with the same auctionId, direction and limit they were issued for.
 	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
 
 	// Limit Page size. Max 200.
@@ -74,11 +135,29 @@ type GetApiV1BidsAuctionIdParams struct {
 
 	// Direction Sort in descending or ascending order.
 	Direction *GetApiV1BidsAuctionIdParamsDirection `form:"direction,omitempty" json:"direction,omitempty"`
+
+	// IfNoneMatch ETag of a previously received page, answered with 304 when nothing changed.
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
 }
 
 // GetApiV1BidsAuctionIdParamsDirection defines parameters for GetApiV1BidsAuctionId.
 type GetApiV1BidsAuctionIdParamsDirection string
 
+// GetApiV1BidsAuctionIdExportParams defines parameters for GetApiV1BidsAuctionIdExport.
+type GetApiV1BidsAuctionIdExportParams struct {
+	// Format Output format.
+	Format *GetApiV1BidsAuctionIdExportParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Direction Sort in descending or ascending order.
+	Direction *GetApiV1BidsAuctionIdExportParamsDirection `form:"direction,omitempty" json:"direction,omitempty"`
+}
+
+// GetApiV1BidsAuctionIdExportParamsFormat defines parameters for GetApiV1BidsAuctionIdExport.
+type GetApiV1BidsAuctionIdExportParamsFormat string
+
+// GetApiV1BidsAuctionIdExportParamsDirection defines parameters for GetApiV1BidsAuctionIdExport.
+type GetApiV1BidsAuctionIdExportParamsDirection string
+
 // RequestEditorFn  is the function signature for the RequestEditor callback function
 type RequestEditorFn func(ctx context.Context, req *http.Request) error
 
@@ -152,8 +231,26 @@ func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
 
 // The interface specification for the client above.
 type ClientInterface interface {
+	// GetApiV1AuctionsAuctionIdTimeseries request
+	GetApiV1AuctionsAuctionIdTimeseries(ctx context.Context, auctionId string, params *GetApiV1AuctionsAuctionIdTimeseriesParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
 	// GetApiV1BidsAuctionId request
 	GetApiV1BidsAuctionId(ctx context.Context, auctionId string, params *GetApiV1BidsAuctionIdParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetApiV1BidsAuctionIdExport request
+	GetApiV1BidsAuctionIdExport(ctx context.Context, auctionId string, params *GetApiV1BidsAuctionIdExportParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) GetApiV1AuctionsAuctionIdTimeseries(ctx context.Context, auctionId string, params *GetApiV1AuctionsAuctionIdTimeseriesParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetApiV1AuctionsAuctionIdTimeseriesRequest(c.Server, auctionId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
 }
 
 func (c *Client) GetApiV1BidsAuctionId(ctx context.Context, auctionId string, params *GetApiV1BidsAuctionIdParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
@@ -168,6 +265,106 @@ func (c *Client) GetApiV1BidsAuctionId(ctx context.Context, auctionId string, pa
 	return c.Client.Do(req)
 }
 
+func (c *Client) GetApiV1BidsAuctionIdExport(ctx context.Context, auctionId string, params *GetApiV1BidsAuctionIdExportParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetApiV1BidsAuctionIdExportRequest(c.Server, auctionId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewGetApiV1AuctionsAuctionIdTimeseriesRequest generates requests for GetApiV1AuctionsAuctionIdTimeseries
+func NewGetApiV1AuctionsAuctionIdTimeseriesRequest(server string, auctionId string, params *GetApiV1AuctionsAuctionIdTimeseriesParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "auctionId", runtime.ParamLocationPath, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/auctions/%s/timeseries", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Interval != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "interval", runtime.ParamLocationQuery, *params.Interval); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.From != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "from", runtime.ParamLocationQuery, *params.From); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.To != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "to", runtime.ParamLocationQuery, *params.To); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 // NewGetApiV1BidsAuctionIdRequest generates requests for GetApiV1BidsAuctionId
 func NewGetApiV1BidsAuctionIdRequest(server string, auctionId string, params *GetApiV1BidsAuctionIdParams) (*http.Request, error) {
 	var err error
@@ -253,6 +450,93 @@ func NewGetApiV1BidsAuctionIdRequest(server string, auctionId string, params *Ge
 		return nil, err
 	}
 
+	if params != nil {
+
+		if params.IfNoneMatch != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "If-None-Match", runtime.ParamLocationHeader, *params.IfNoneMatch)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("If-None-Match", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetApiV1BidsAuctionIdExportRequest generates requests for GetApiV1BidsAuctionIdExport
+func NewGetApiV1BidsAuctionIdExportRequest(server string, auctionId string, params *GetApiV1BidsAuctionIdExportParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "auctionId", runtime.ParamLocationPath, auctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/bids/%s/export", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Format != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "format", runtime.ParamLocationQuery, *params.Format); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Direction != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "direction", runtime.ParamLocationQuery, *params.Direction); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
 	return req, nil
 }
 
@@ -299,8 +583,37 @@ func WithBaseURL(baseURL string) ClientOption {
 
 // ClientWithResponsesInterface is the interface specification for the client with responses above.
 type ClientWithResponsesInterface interface {
+	// GetApiV1AuctionsAuctionIdTimeseriesWithResponse request
+	GetApiV1AuctionsAuctionIdTimeseriesWithResponse(ctx context.Context, auctionId string, params *GetApiV1AuctionsAuctionIdTimeseriesParams, reqEditors ...RequestEditorFn) (*GetApiV1AuctionsAuctionIdTimeseriesResponse, error)
+
 	// GetApiV1BidsAuctionIdWithResponse request
 	GetApiV1BidsAuctionIdWithResponse(ctx context.Context, auctionId string, params *GetApiV1BidsAuctionIdParams, reqEditors ...RequestEditorFn) (*GetApiV1BidsAuctionIdResponse, error)
+
+	// GetApiV1BidsAuctionIdExportWithResponse request
+	GetApiV1BidsAuctionIdExportWithResponse(ctx context.Context, auctionId string, params *GetApiV1BidsAuctionIdExportParams, reqEditors ...RequestEditorFn) (*GetApiV1BidsAuctionIdExportResponse, error)
+}
+
+type GetApiV1AuctionsAuctionIdTimeseriesResponse struct {
+	Body                      []byte
+	HTTPResponse              *http.Response
+	JSON200                   *BidTimeseriesResponse
+	ApplicationproblemJSON400 *ProblemDetails
+}
+
+// Status returns HTTPResponse.Status
+func (r GetApiV1AuctionsAuctionIdTimeseriesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetApiV1AuctionsAuctionIdTimeseriesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
 }
 
 type GetApiV1BidsAuctionIdResponse struct {
@@ -327,6 +640,37 @@ func (r GetApiV1BidsAuctionIdResponse) StatusCode() int {
 	return 0
 }
 
+type GetApiV1BidsAuctionIdExportResponse struct {
+	Body                      []byte
+	HTTPResponse              *http.Response
+	ApplicationproblemJSON400 *ProblemDetails
+}
+
+// Status returns HTTPResponse.Status
+func (r GetApiV1BidsAuctionIdExportResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetApiV1BidsAuctionIdExportResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// GetApiV1AuctionsAuctionIdTimeseriesWithResponse request returning *GetApiV1AuctionsAuctionIdTimeseriesResponse
+func (c *ClientWithResponses) GetApiV1AuctionsAuctionIdTimeseriesWithResponse(ctx context.Context, auctionId string, params *GetApiV1AuctionsAuctionIdTimeseriesParams, reqEditors ...RequestEditorFn) (*GetApiV1AuctionsAuctionIdTimeseriesResponse, error) {
+	rsp, err := c.GetApiV1AuctionsAuctionIdTimeseries(ctx, auctionId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetApiV1AuctionsAuctionIdTimeseriesResponse(rsp)
+}
+
 // GetApiV1BidsAuctionIdWithResponse request returning *GetApiV1BidsAuctionIdResponse
 func (c *ClientWithResponses) GetApiV1BidsAuctionIdWithResponse(ctx context.Context, auctionId string, params *GetApiV1BidsAuctionIdParams, reqEditors ...RequestEditorFn) (*GetApiV1BidsAuctionIdResponse, error) {
 	rsp, err := c.GetApiV1BidsAuctionId(ctx, auctionId, params, reqEditors...)
@@ -336,6 +680,48 @@ func (c *ClientWithResponses) GetApiV1BidsAuctionIdWithResponse(ctx context.Cont
 	return ParseGetApiV1BidsAuctionIdResponse(rsp)
 }
 
+// GetApiV1BidsAuctionIdExportWithResponse request returning *GetApiV1BidsAuctionIdExportResponse
+func (c *ClientWithResponses) GetApiV1BidsAuctionIdExportWithResponse(ctx context.Context, auctionId string, params *GetApiV1BidsAuctionIdExportParams, reqEditors ...RequestEditorFn) (*GetApiV1BidsAuctionIdExportResponse, error) {
+	rsp, err := c.GetApiV1BidsAuctionIdExport(ctx, auctionId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetApiV1BidsAuctionIdExportResponse(rsp)
+}
+
+// ParseGetApiV1AuctionsAuctionIdTimeseriesResponse parses an HTTP response from a GetApiV1AuctionsAuctionIdTimeseriesWithResponse call
+func ParseGetApiV1AuctionsAuctionIdTimeseriesResponse(rsp *http.Response) (*GetApiV1AuctionsAuctionIdTimeseriesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetApiV1AuctionsAuctionIdTimeseriesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest BidTimeseriesResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ProblemDetails
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON400 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ParseGetApiV1BidsAuctionIdResponse parses an HTTP response from a GetApiV1BidsAuctionIdWithResponse call
 func ParseGetApiV1BidsAuctionIdResponse(rsp *http.Response) (*GetApiV1BidsAuctionIdResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -376,11 +762,43 @@ func ParseGetApiV1BidsAuctionIdResponse(rsp *http.Response) (*GetApiV1BidsAuctio
 	return response, nil
 }
 
+// ParseGetApiV1BidsAuctionIdExportResponse parses an HTTP response from a GetApiV1BidsAuctionIdExportWithResponse call
+func ParseGetApiV1BidsAuctionIdExportResponse(rsp *http.Response) (*GetApiV1BidsAuctionIdExportResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetApiV1BidsAuctionIdExportResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest ProblemDetails
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationproblemJSON400 = &dest
+
+	}
+
+	return response, nil
+}
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Bid activity time series for an auction
+	// (GET /api/v1/auctions/{auctionId}/timeseries)
+	GetApiV1AuctionsAuctionIdTimeseries(c *gin.Context, auctionId string, params GetApiV1AuctionsAuctionIdTimeseriesParams)
 	// List bids for an auction
 	// (GET /api/v1/bids/{auctionId})
 	GetApiV1BidsAuctionId(c *gin.Context, auctionId string, params GetApiV1BidsAuctionIdParams)
+	// Export the full bid history of an auction
+	// (GET /api/v1/bids/{auctionId}/export)
+	GetApiV1BidsAuctionIdExport(c *gin.Context, auctionId string, params GetApiV1BidsAuctionIdExportParams)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -392,6 +810,57 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(c *gin.Context)
 
+// GetApiV1AuctionsAuctionIdTimeseries operation middleware
+func (siw *ServerInterfaceWrapper) GetApiV1AuctionsAuctionIdTimeseries(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "auctionId" -------------
+	var auctionId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "auctionId", c.Param("auctionId"), &auctionId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter auctionId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApiV1AuctionsAuctionIdTimeseriesParams
+
+	// ------------- Optional query parameter "interval" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "interval", c.Request.URL.Query(), &params.Interval)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter interval: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", c.Request.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter from: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", c.Request.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter to: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetApiV1AuctionsAuctionIdTimeseries(c, auctionId, params)
+}
+
 // GetApiV1BidsAuctionId operation middleware
 func (siw *ServerInterfaceWrapper) GetApiV1BidsAuctionId(c *gin.Context) {
 
@@ -433,6 +902,27 @@ func (siw *ServerInterfaceWrapper) GetApiV1BidsAuctionId(c *gin.Context) {
 		return
 	}
 
+	headers := c.Request.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for If-None-Match, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter If-None-Match: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -443,6 +933,49 @@ func (siw *ServerInterfaceWrapper) GetApiV1BidsAuctionId(c *gin.Context) {
 	siw.Handler.GetApiV1BidsAuctionId(c, auctionId, params)
 }
 
+// GetApiV1BidsAuctionIdExport operation middleware
+func (siw *ServerInterfaceWrapper) GetApiV1BidsAuctionIdExport(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "auctionId" -------------
+	var auctionId string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "auctionId", c.Param("auctionId"), &auctionId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter auctionId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApiV1BidsAuctionIdExportParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", c.Request.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter format: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "direction" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "direction", c.Request.URL.Query(), &params.Direction)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter direction: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetApiV1BidsAuctionIdExport(c, auctionId, params)
+}
+
 // GinServerOptions provides options for the Gin server.
 type GinServerOptions struct {
 	BaseURL      string
@@ -470,34 +1003,57 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 		ErrorHandler:       errorHandler,
 	}
 
+	router.GET(options.BaseURL+"/api/v1/auctions/:auctionId/timeseries", wrapper.GetApiV1AuctionsAuctionIdTimeseries)
 	router.GET(options.BaseURL+"/api/v1/bids/:auctionId", wrapper.GetApiV1BidsAuctionId)
+	router.GET(options.BaseURL+"/api/v1/bids/:auctionId/export", wrapper.GetApiV1BidsAuctionIdExport)
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8RW33PbNhL+V3ZwN3PJHEVSsp1z+GYnl9adpnVtt83U9dggsRI3IQEaPxS5Hv3vHQC0",
-	"REuykzz1SQIB7H7Yb/fbvWeVajslUVrDintmqhpbHv4ek/A/nVYdaksYPvJWOWn9P1zwtmuQFeN8nB4k",
-	"bKp0yy0rmFCubJAlzN51yAomXVuiZsuE8ccX2SSfHIzy16N8fDHOi8mk2Nv7gw0tcYsjS+3AmLGa5CwY",
-	"c5UlJU/EY5v8ejzZ23W+JLF5trzO8/ETZwXqzePOoN5tfZkwjbeONApWXPauhhAHJpOHGIZ4JMzgLbta",
-	"WVTlR6ysx/AjGXtMwpyh6ZQ0uM1Fzc17pfERSKsdroyVSjXIpbdGFttwafXn3xqnrGD/ytYJkPXsZ576",
-	"5coM15rf+bXEhX3jtFHaGxBoKk2dfyEr2M8dv3UIVn1CCVOlwdYI/gJ0fIYJKA3SNQ3QFKSCVmkEjcY1",
-	"1qQsYX6Ll1sPeCK+8Q27gnaqVdlg+xYtp8Zsh0yEjW30R1C7lsuRRi48DsBF13DJ/TaYDiuaUgVWga3J",
-	"gKoqpzXKCkFNw0O76Jclg3Q5kXPekICSBETKExjneZqn8J4kta6N8fH7ZGCcT9KDXclI0lguK9yF+tez",
-	"E9A4xQjG1twCCZSWpoQmIFuB/zrQWZ+zJuPX+wevspKEycrr/x2+Hham07QLqbHcOrON86JG+P7i4hTi",
-	"AaiUQJihRM0tCijvAhylaUYSDOo56j6FvjrY+4vFGhFJi7MoOZZsszNyplbaJpu0G9e2XN9teIJgd+ju",
-	"mARo9Fnn8WOjPkPbc0qy0thiKPCtCMUPX+Lx8uzdm73Xh6+udjL6JKja2s4UWRalZqZEWqk264+bLMAc",
-	"tSRHQ4jPc7pReL3LGNQV39uF6HMBK6fJ3p17SYnFVyLXqI+crderdw/uf/j9wlsMp710hd01IP82tlyG",
-	"cpgqf79nNlDxi0N9B+eo51QhHJ2esITNUZsY3nGap7mPvupQ8o5YwfbSPPU63nFbB2wZ7yibj2O+36+E",
-	"e+n3Zmi3OTtD67Q0vnpNSFYOM5qjhP5uAhI/o7EwJW2sz3GBU+4am4AzJGdQBSH16khRZ1K4id9uvBxM",
-	"tWp7unFOypmgo/8x8OJmLcM3L9M/JQsP0/yhG7Lv0B519NvYd4+jQQvquOYtWtSGFZe7arSHDidvmY8z",
-	"K0J8vDzzQMqwn63TIip2bB2BmM0U2nQVsT/xwgRUS3bVQWL0/MYDpFvP9RpTDBkbAvhiM9kEdMpnCIb+",
-	"whTe8wVM8jx9wltDLdlHznpWWXGQJ6zlC68BrJjkfhUVgRXjbWXaBnGutAWS4L+iFD5FfFYNFgL1U7gE",
-	"aQzc7MYWXHmtkB7O5cOSm2pQvKv4XHl249ARamOS5/6nUtJinP141zVUhYzLPhoP/37g9rnJYmuqCTW9",
-	"KYeeb6/Bvrj8k2vkIqTtPfsw+gkXdvTUGHLOW4Q5bxwCNzAsFR9bn1GlEnchvSol5yjJK+63pQ/7MDrD",
-	"W4fGjuKI+BjC/6taoYGSV5+CQx3PwsnbJE4/1kv4nMS69VUNoR8P+t730BoNKIkpe664PJz9Z/np9f+/",
-	"38bTxiC1g6VjLlZPe0H9rBOrMYEgNSYBtFX6kgWM+/8Axl79QsynykkRW1Ns8qwIM/ZAwVfiHW1FNqJY",
-	"Ot30XajIskZVvKmVscVhfrjHllfLvwMAAP//HLqwZ0cNAAA=",
+	"H4sIAAAAAAAC/+xZW5PbuNH9K138vqq1K5REzWXjVSoP47F3dzbrS2ZmN1tZuyyIaIlYkwANNHWJa/57",
+	"qgFSoiRK1jyk4oe8zIgigG705ZwD6HOUmqI0GjW5aPQ5cmmGhfAfnyvJ/0prSrSk0H8pClNp4k+4FEWZ",
+	"YzQaJsP+ZRxNjS0ERaNImmqSYxRHtCoxGkW6KiZoo4c4EtsTo7Pk7LKXfNdLhvfDZHR2Njo//2fUXkkQ",
+	"9kgVrcUcWaVnfrEqJWX0jdxeU3wYnp13jZ8ouTt28iFJhgfGSrS7wyuHtnv1hziy+KlSFmU0+r021Xax",
+	"tWTcxNDHI44cforer1c0kz8wJfbhuZL3qkCHVqF7XqUfkfbTkebGIX+Q6FKrSjYXjaKfhSMQaYoloYRg",
+	"D5QGyhCUJrRzkUdxK4XnpyUw3U/+WWui0vTtxWYeW5qFiZmaZdvzTrSYm8VOsSUnzTMl6v24fK/sIwNz",
+	"ojlHwtK+vTv+Gsx0a314onSaV07N8emJ1b5TX8Fak456s3WQQ8jiujK+WFi36EqjHe6X1qP7y5eon6oI",
+	"C//h/y1Oo1H0f4MNyAxqhBl01ffDel1hrVjx8zorW14Miy4XyFY6FYRyPxP3tkJYZKihMBahdhZwqRzF",
+	"MMEpf8tZmvoSMRrBIlVWowzTxlNrijEoB6ZQRChjEFNC6yflop5jKEO7UA777/TGw4kxOQq9l8c2PrSq",
+	"r4lkV/J+Vo6eK3kkb5lwr4zFrXiRrXDfm3iTqFMz1pUhjUu6rqwzdj/sb0rxqUIg8xE1TE2IFk+AUsww",
+	"BmNBV3kOagrahNRYdFVOrh/FEb8Sk70NHOiKsIeuoL21ZpJj8QJJqNzth0z6F/veX0FWFUL3LArJfgAu",
+	"y1xowa/BlZiqqUqBDFDGdZGmlbWoU2w6vgx224AS3ei5yJWEiWrwJ4ZhkvSTPrxSWhVVEeLD75WDYXLW",
+	"v+yqdaUdCZ1il9e/3N6AxSkGZygTBEqiJjVV6Lxna+dPc3pQV6obiA8Xl98OJkq6weTDn5991wawyqou",
+	"Tx0JqlxHS2YIP97fv4UwAFIjEWao0XILw2Tl3TFWzZQGh3aOti6hk4N9sVx20hEpyjsj5zJjKd5Nu6uK",
+	"QtjVjiXw67bNPVcSLHLVsf+YmwUUdU6VTi0W6OF6H7dWZac323n8/fb76/Pvnn37vjOjB53KiEo3GgyC",
+	"AJkZ2U9NMaiHu4F3s1co3Wu7eDynO41XmwxBXed7vxG5FjCtrKLVHUNKaL4JCov2qqJs8/R9Y/6nf9zz",
+	"in50NKrfbhzivUUPD74dpobn15n1qfh7hXYFd2jnKkW4ensTxdEcrQvhHfaTftJIBVGqaBSd95M+c1sp",
+	"KPO+DUSpBvPhpvw/ryH7YUBr9uKhM+zg/x+sqUoHQkM97xvHje1AaTIwVUuUa1nAw2RNOi6GEu36VRxg",
+	"06sNLkK/BI9+8+PP1+BolXP+eZOp0DJHB088jcXAkgD5Q24WyNJHy0BWOxroaR9u1n4sFGWmotqMxYbx",
+	"+nBFUBhHcJkkyZpDhd1wZVzTIWEgRHeQPHkcCpsrHsmvm0FrCo29t+M1qfv5DqlF456++/CLw3r2YExm",
+	"zIjM9AKUWVPNMsiNnqEFK/QMXWBmhn/R6JvoB6SrUv06vKrzfNVkeaNQfFlYUSChddHo9y40q5MMNy88",
+	"oUcjX0lRHGnhy7fN95sGCtwWSNaX8G6z7ZoKYgkWSlLWbyx94mLfmGrJic3KEqeiyqnRT6irgvvXP1zy",
+	"n2H4yy4PZauDD/ty00hZX2EWJqbSEoz2/MUdEvuUc0q8ag2p3gitiZKHtsAjt9w/TSnvevhy2XhYleVx",
+	"D70ul9iu4SP+kXm8d+858UG5edQ4SxL+lxpNGE5WoixzlfrSHPzhjN4cyh8lqNf60KNjV/0wCIFwKWqp",
+	"9MxHAoyVaHm/GQrp6/xz9FvvFj9V6Kh306GrX6aZQQcTkX70YbNhLNy8iIOoI2amuZIbRk9zhZrihtIb",
+	"xneMF/3oWCfwVi6ORqymtT89LnI7+rArZEKut/ak0q4qS2Ophd5xKOz6SGBiQEr7TwPnBfVQk5JISc0V",
+	"rULAQ7K8rNmQhJ/VUI+XWi3aOcg1t4E5Amr7BWGm5rheNQaNngPqxltBDQcxVI4rIPUanpFTBYnbh3H4",
+	"ziPvunFLi3NlKscj8RsHT8abE8D46TF45YPLVQsDvxZAvVMzjbIJQPdGa5RozjAhiPyiD2HrfJgslUXP",
+	"Wp41db6CIPaZUf00J4r1rm5kDFJZ9A+BmRWboAxXsECLoJyrULLNENUuFApOb7XNF49Nu/t/y2Tp1L+w",
+	"D6/EEs6S5BDmeQ+7OeUyiaNCLFntRqMzvjeptW80Gu5r8I4kGOuvYiSuMYmLuPVQY1OXX+s4dvvmTbUY",
+	"r34ULj2J5F7eixnrLrEuiXwFFlNUc5R1dQjtOGd1rs+TiyBTtKHMN1fG4mPDJQFgNxu4mfZeG429V4LS",
+	"7CgI/icpZO92oQMKr/yGGxm6QxbXIs2wd200WdNxoL7j4xVMrFk4tFCIZc/HLjBCUJK4LMP5iQxY9O0j",
+	"CENUt4J0nCpin7QOD8gaPYN6XdM6UYY0hjzVslUwZDYn8dKa+mTXYEDdx19y5K6y1swEHYnL9YvXm2h4",
+	"3HCoa6HrmumQitTXknKAmhtcnm76b7jaNzvmDPZa5DKOoazsbMPV9aaN9b4g87XwAdmJwVaAAlgd8+u3",
+	"3mtcUu/QrdEdo+Rc5BWCcNCml+audmLkyvuQGj1HrVCn+DgM/Np0zXlyse/Fa8MHLsmn/PrI5HuvEBQq",
+	"Yash/PGIVJ5DuBqhnd7sbojrMHbTEXFNUuFawWgMtaj0Y9rvK1FqKly2xUCiKD06G1vTdEP3MXgV4taK",
+	"jR2/+C84XgsjX1pTPqPsiEfG5pa4O00tDnDJQvWgaLwji6JwwJ298o1tpq21uQqu737lqGlc5EojSPQa",
+	"ACX8dPfmdRycqaSicCMxqfKPEIy6PtyaRUB15+2g5A9CzbLWOdCikFzkmMfgTP1VoB/IhANtvDSpxRHb",
+	"UC70XxCqKE+WnC9DML4e4fmmorLyorIQdPAc7N8eEDapm7d0TXjS0tfmKcrm6xFdj5M1y56W+w24E1yN",
+	"vkYBNV8tSxg/V3LsL9a4kvud95qESxpwGI8s/KPHVLBmAWP/U2+8UfTNz7xxfbcvaAxTk/PliCcK/8OS",
+	"WXgv6ruFPeyMOwqSWzNTjoxdxetu2hVfIVa9F8qVxqkwey/j1WyGzssYlSNwKmPAGYwFkUizAjX9xb/i",
+	"N399F3mJ4H/066du/i4af5Ha/3ddcPi6IPTyHudsAX1AqXDK5N/GWqnfRudgOcQlAFll8/pWfDQY5CYV",
+	"eWYcjZ4lz86jh/cP/x4AoXcGKe0hAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file